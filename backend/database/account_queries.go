@@ -0,0 +1,286 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	email_utils "github.com/skye-tan/trello/backend/utils/email"
+	hashing_utils "github.com/skye-tan/trello/backend/utils/hashing"
+)
+
+const verifyEmailValidPeriod = time.Hour * 48
+const resetPasswordValidPeriod = time.Hour
+
+// createAccountToken issues a single-use token for the purpose and drops any
+// unused one issued before it, so only the latest emailed link works.
+func createAccountToken(user_id uint, purpose string, valid_period time.Duration) (string, error) {
+	token, err := generateSecretToken()
+	if err != nil {
+		log.Println("Error:", err)
+		return "", custom_errors.ErrTokenFailure
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM AccountToken
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL;`,
+		user_id, purpose)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return "", custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		AccountToken(user_id, purpose, token_hash, expires_at, created_at)
+		VALUES($1, $2, $3, $4, $5);`,
+		user_id, purpose, hashing_utils.HashUsingSha256(token),
+		time.Now().Add(valid_period), time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return "", custom_errors.ErrDatabaseFailure
+	}
+
+	return token, nil
+}
+
+func consumeAccountToken(token string, purpose string) (uint, error) {
+	var user_id uint
+
+	err := DB.QueryRow(`
+		UPDATE AccountToken
+		SET used_at = $1
+		WHERE token_hash = $2 AND purpose = $3 AND used_at IS NULL AND expires_at > $1
+		RETURNING user_id;`,
+		time.Now(), hashing_utils.HashUsingSha256(token), purpose).
+		Scan(&user_id)
+
+	if err == sql.ErrNoRows {
+		return 0, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	return user_id, nil
+}
+
+func setEmailVerified(user_id uint, is_verified bool) error {
+	verified_at := sql.NullTime{Time: time.Now(), Valid: is_verified}
+
+	_, err := DB.Exec(`
+		INSERT INTO
+		EmailVerification(user_id, is_verified, verified_at, created_at)
+		VALUES($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET is_verified = $2, verified_at = $3;`,
+		user_id, is_verified, verified_at, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+// isEmailVerified treats accounts without a verification record as verified,
+// which covers accounts created before verification was introduced.
+func isEmailVerified(user_id uint) (bool, error) {
+	var pending bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM EmailVerification
+			WHERE user_id = $1 AND NOT is_verified
+		);`,
+		user_id).
+		Scan(&pending)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return !pending, nil
+}
+
+func queueVerificationEmail(user User) error {
+	token, err := createAccountToken(user.ID, VerifyEmailPurpose, verifyEmailValidPeriod)
+	if err != nil {
+		return err
+	}
+
+	return queueEmail(user.ID, user.Email, "verify_email", email_utils.TemplateData{
+		Username:  user.Username,
+		ActionURL: email_utils.VerificationURL(token),
+	})
+}
+
+// startEmailVerification marks a freshly created account as unverified and
+// emails it a confirmation link. A failure to queue the email is only logged
+// since the user can ask for a new link later.
+func startEmailVerification(user User) error {
+	err := setEmailVerified(user.ID, false)
+	if err != nil {
+		return err
+	}
+
+	if err := queueVerificationEmail(user); err != nil {
+		log.Println("Error: failed to queue verification email for user", user.ID)
+	}
+
+	return nil
+}
+
+func RequestEmailVerification(requester_user_id uint) error {
+	verified, err := isEmailVerified(requester_user_id)
+	if err != nil {
+		return err
+	} else if verified {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	user, err := GetUserByID(requester_user_id)
+	if err != nil {
+		return err
+	}
+
+	return queueVerificationEmail(user)
+}
+
+func VerifyEmail(token string) error {
+	user_id, err := consumeAccountToken(token, VerifyEmailPurpose)
+	if err != nil {
+		return err
+	}
+
+	return setEmailVerified(user_id, true)
+}
+
+// RequestPasswordReset emails a reset link when the address belongs to an
+// account that can log in with a password. Unknown addresses are accepted
+// silently so the endpoint cannot be used to discover accounts.
+func RequestPasswordReset(email string) error {
+	var user User
+
+	err := DB.QueryRow(`
+		SELECT * FROM Users
+		WHERE LOWER(email) = LOWER($1) AND NOT EXISTS (
+			SELECT 1 FROM ServiceAccount WHERE user_id = Users.id
+		);`,
+		email).
+		Scan(&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.PasswordHash,
+			&user.CreatedAt,
+			&user.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	token, err := createAccountToken(user.ID, ResetPasswordPurpose, resetPasswordValidPeriod)
+	if err != nil {
+		return err
+	}
+
+	return queueEmail(user.ID, user.Email, "password_reset", email_utils.TemplateData{
+		Username:  user.Username,
+		ActionURL: email_utils.PasswordResetURL(token),
+	})
+}
+
+// ResetPassword sets the new password and signs the user out everywhere.
+// Following the emailed link also proves ownership of the address.
+func ResetPassword(token string, password_hash []byte) error {
+	user_id, err := consumeAccountToken(token, ResetPasswordPurpose)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		UPDATE Users
+		SET password_hash = $1, updated_at = $2
+		WHERE id = $3;`,
+		password_hash, time.Now(), user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		UPDATE EmailVerification
+		SET is_verified = true, verified_at = $1
+		WHERE user_id = $2 AND NOT is_verified;`,
+		time.Now(), user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return RevokeSessions(user_id)
+}
+
+// RevokeSessions invalidates every token issued to the user so far, personal
+// access tokens included. Tokens carry their issue time to the microsecond,
+// the precision Postgres keeps, so tokens issued right after the revocation
+// in the same second stay valid while earlier ones do not.
+func RevokeSessions(user_id uint) error {
+	_, err := DB.Exec(`
+		DELETE FROM PersonalAccessToken
+		WHERE user_id = $1;`,
+		user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		SessionRevocation(user_id, revoked_at)
+		VALUES($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET revoked_at = $2;`,
+		user_id, time.Now().Truncate(time.Microsecond))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func ValidateSession(user_id uint, issued_at time.Time) error {
+	var valid bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM Users WHERE id = $1
+		) AND NOT EXISTS (
+			SELECT 1 FROM SessionRevocation WHERE user_id = $1 AND revoked_at > $2
+		);`,
+		user_id, issued_at).
+		Scan(&valid)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if !valid {
+		return custom_errors.ErrAccessDenied
+	}
+
+	return nil
+}
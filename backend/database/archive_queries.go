@@ -0,0 +1,227 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func attachTaskArchives(tasks []Task) error {
+	task_ids := make([]int64, len(tasks))
+	task_indexes := make(map[uint]int)
+	for i, task := range tasks {
+		task_ids[i] = int64(task.ID)
+		task_indexes[task.ID] = i
+	}
+
+	rows, err := DB.Query(`
+		SELECT task_id
+		FROM TaskArchive
+		WHERE task_id = ANY($1::integer[]);`,
+		pq.Array(task_ids))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var task_id uint
+
+		if err := rows.Scan(&task_id); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		tasks[task_indexes[task_id]].IsArchived = true
+	}
+
+	return nil
+}
+
+func isTaskArchived(task_id uint) (bool, error) {
+	var archived bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM TaskArchive
+			WHERE task_id = $1
+		);`,
+		task_id).
+		Scan(&archived)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return archived, nil
+}
+
+func ArchiveTask(requester_user_id uint, workspace_id uint, task_id uint) error {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkTaskPermission(requester_user_id, workspace_id, task_id, TaskUpdateAnyPermission, TaskUpdateOwnPermission)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		TaskArchive(task_id, archived_by, archived_at)
+		VALUES($1, $2, $3)
+		ON CONFLICT DO NOTHING;`,
+		task_id, requester_user_id, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func UnarchiveTask(requester_user_id uint, workspace_id uint, task_id uint) error {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkTaskPermission(requester_user_id, workspace_id, task_id, TaskUpdateAnyPermission, TaskUpdateOwnPermission)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM TaskArchive
+		WHERE task_id = $1;`,
+		task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+// archiveCompletedTasks archives the Completed tasks that have not changed
+// since the cutoff and returns their ids. A zero workspace id stands for
+// every workspace with an auto-archive policy, each with its own cutoff.
+func archiveCompletedTasks(archived_by uint, workspace_id uint, cutoff time.Time) ([]uint, error) {
+	rows, err := DB.Query(`
+		INSERT INTO
+		TaskArchive(task_id, archived_by, archived_at)
+		SELECT Task.id, $1, $2
+		FROM Task
+		LEFT JOIN WorkspaceArchivePolicy ON WorkspaceArchivePolicy.workspace_id = Task.workspace_id
+		WHERE Task.status = $3 AND (
+			($4 != 0 AND Task.workspace_id = $4 AND Task.updated_at <= $5) OR
+			($4 = 0 AND WorkspaceArchivePolicy.auto_archive_days > 0 AND
+				Task.updated_at <= $2 - make_interval(days => WorkspaceArchivePolicy.auto_archive_days))
+		) AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE (item_type = $6 AND item_id = Task.id) OR (item_type = $7 AND item_id = Task.workspace_id)
+		)
+		ON CONFLICT DO NOTHING
+		RETURNING task_id;`,
+		nullableID(archived_by), time.Now(), Completed, workspace_id, cutoff, TaskItem, WorkspaceItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	task_ids := []uint{}
+
+	for rows.Next() {
+		var task_id uint
+
+		if err := rows.Scan(&task_id); err != nil {
+			log.Println("Error:", err)
+			return []uint{}, custom_errors.ErrDatabaseFailure
+		} else {
+			task_ids = append(task_ids, task_id)
+		}
+	}
+
+	return task_ids, nil
+}
+
+// ArchiveCompletedTasks archives every Completed task of the workspace that
+// has been left untouched for the given number of days.
+func ArchiveCompletedTasks(requester_user_id uint, workspace_id uint, older_than_days int) ([]uint, error) {
+	err := checkPermission(requester_user_id, workspace_id, TaskUpdateAnyPermission)
+	if err != nil {
+		return []uint{}, err
+	}
+
+	return archiveCompletedTasks(requester_user_id, workspace_id, time.Now().AddDate(0, 0, -older_than_days))
+}
+
+func AutoArchiveTasks() ([]uint, error) {
+	return archiveCompletedTasks(0, 0, time.Time{})
+}
+
+func GetWorkspaceArchivePolicy(requester_user_id uint, workspace_id uint) (WorkspaceArchivePolicy, error) {
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return WorkspaceArchivePolicy{}, err
+	}
+
+	archive_policy := WorkspaceArchivePolicy{WorkspaceID: workspace_id}
+	var updated_by sql.NullInt64
+	var updated_at sql.NullTime
+
+	err = DB.QueryRow(`
+		SELECT auto_archive_days, updated_by, updated_at
+		FROM WorkspaceArchivePolicy
+		WHERE workspace_id = $1;`,
+		workspace_id).
+		Scan(&archive_policy.AutoArchiveDays, &updated_by, &updated_at)
+
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error:", err)
+		return WorkspaceArchivePolicy{}, custom_errors.ErrDatabaseFailure
+	}
+
+	archive_policy.UpdatedBy = uint(updated_by.Int64)
+	archive_policy.UpdatedAt = updated_at.Time
+
+	return archive_policy, nil
+}
+
+// UpdateWorkspaceArchivePolicy sets after how many days Completed tasks are
+// archived automatically. Zero turns auto-archiving off.
+func UpdateWorkspaceArchivePolicy(requester_user_id uint, workspace_id uint, auto_archive_days int) error {
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceUpdatePermission)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		WorkspaceArchivePolicy(workspace_id, auto_archive_days, updated_by, updated_at)
+		VALUES($1, $2, $3, $4)
+		ON CONFLICT (workspace_id) DO UPDATE
+		SET auto_archive_days = $2, updated_by = $3, updated_at = $4;`,
+		workspace_id, auto_archive_days, requester_user_id, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
@@ -0,0 +1,376 @@
+package database
+
+import (
+	"log"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func GetTaskAssignees(task_id uint) ([]uint, error) {
+	rows, err := DB.Query(`
+		SELECT user_id
+		FROM TaskAssignee
+		WHERE task_id = $1;`,
+		task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	assignees := []uint{}
+
+	for rows.Next() {
+		var assignee uint
+
+		if err := rows.Scan(&assignee); err != nil {
+			log.Println("Error:", err)
+			return []uint{}, custom_errors.ErrDatabaseFailure
+		} else {
+			assignees = append(assignees, assignee)
+		}
+	}
+
+	return assignees, nil
+}
+
+func isTaskAssignee(user_id uint, task_id uint) (bool, error) {
+	var is_assignee bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM TaskAssignee
+			WHERE task_id = $1 AND user_id = $2
+		) OR EXISTS (
+			SELECT 1 FROM Task
+			WHERE id = $1 AND assignee_id = $2
+		);`,
+		task_id, user_id).
+		Scan(&is_assignee)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return is_assignee, nil
+}
+
+func isSubtaskAssignee(user_id uint, subtask_id uint) (bool, error) {
+	var is_assignee bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM SubtaskAssignee
+			WHERE subtask_id = $1 AND user_id = $2
+		) OR EXISTS (
+			SELECT 1 FROM Subtask
+			WHERE id = $1 AND assignee_id = $2
+		);`,
+		subtask_id, user_id).
+		Scan(&is_assignee)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return is_assignee, nil
+}
+
+// checkTaskPermission lets members holding only the narrower permission act
+// on tasks they are assigned to.
+func checkTaskPermission(user_id uint, workspace_id uint, task_id uint, any_permission string, own_permission string) error {
+	return checkOwnPermission(user_id, workspace_id, any_permission, own_permission, func() (bool, error) {
+		return isTaskAssignee(user_id, task_id)
+	})
+}
+
+func checkSubtaskPermission(user_id uint, workspace_id uint, subtask_id uint, any_permission string, own_permission string) error {
+	return checkOwnPermission(user_id, workspace_id, any_permission, own_permission, func() (bool, error) {
+		return isSubtaskAssignee(user_id, subtask_id)
+	})
+}
+
+// checkTaskReassignment requires the assign permission whenever an update
+// hands the task to someone who is not assigned to it yet.
+func checkTaskReassignment(user_id uint, workspace_id uint, task_id uint, assignee_id uint) error {
+	is_assignee, err := isTaskAssignee(assignee_id, task_id)
+	if err != nil {
+		return err
+	} else if is_assignee {
+		return nil
+	}
+
+	return checkPermission(user_id, workspace_id, TaskAssignPermission)
+}
+
+func checkSubtaskReassignment(user_id uint, workspace_id uint, subtask_id uint, assignee_id uint) error {
+	is_assignee, err := isSubtaskAssignee(assignee_id, subtask_id)
+	if err != nil {
+		return err
+	} else if is_assignee {
+		return nil
+	}
+
+	return checkPermission(user_id, workspace_id, SubtaskAssignPermission)
+}
+
+func AddAssigneesToTask(requester_user_id uint, workspace_id uint, task_id uint, user_ids []uint) ([]uint, error) {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return []uint{}, err
+	} else if actual_workspace_id != workspace_id {
+		return []uint{}, custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, TaskAssignPermission)
+	if err != nil {
+		return []uint{}, err
+	}
+
+	user_ids = uniqueIDs(user_ids)
+	for _, user_id := range user_ids {
+		assignee_user_role, err := getMembershipRole(user_id, workspace_id)
+		if err != nil {
+			return []uint{}, err
+		} else if assignee_user_role == NoRole {
+			return []uint{}, custom_errors.ErrInvalidArguments
+		}
+	}
+
+	rows, err := DB.Query(`
+		INSERT INTO
+		TaskAssignee(task_id, user_id)
+		SELECT $1, unnest($2::integer[])
+		ON CONFLICT DO NOTHING
+		RETURNING user_id;`,
+		task_id, pq.Array(toInt64Array(user_ids)))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	added_users := []uint{}
+
+	for rows.Next() {
+		var added_user uint
+
+		if err := rows.Scan(&added_user); err != nil {
+			log.Println("Error:", err)
+			return []uint{}, custom_errors.ErrDatabaseFailure
+		} else {
+			added_users = append(added_users, added_user)
+		}
+	}
+
+	_, err = DB.Exec(`
+		UPDATE Task
+		SET assignee_id = (
+			SELECT user_id
+			FROM TaskAssignee
+			WHERE task_id = $1
+			LIMIT 1
+		)
+		WHERE id = $1 AND assignee_id IS NULL;`,
+		task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return added_users, nil
+}
+
+func RemoveAssigneeFromTask(requester_user_id uint, workspace_id uint, task_id uint, user_id uint) error {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, TaskAssignPermission)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM TaskAssignee
+		WHERE task_id = $1 AND user_id = $2;`,
+		task_id, user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		UPDATE Task
+		SET assignee_id = (
+			SELECT user_id
+			FROM TaskAssignee
+			WHERE task_id = $1
+			LIMIT 1
+		)
+		WHERE id = $1 AND assignee_id = $2;`,
+		task_id, user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetSubtaskAssignees(subtask_id uint) ([]uint, error) {
+	rows, err := DB.Query(`
+		SELECT user_id
+		FROM SubtaskAssignee
+		WHERE subtask_id = $1;`,
+		subtask_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	assignees := []uint{}
+
+	for rows.Next() {
+		var assignee uint
+
+		if err := rows.Scan(&assignee); err != nil {
+			log.Println("Error:", err)
+			return []uint{}, custom_errors.ErrDatabaseFailure
+		} else {
+			assignees = append(assignees, assignee)
+		}
+	}
+
+	return assignees, nil
+}
+
+func AddAssigneesToSubtask(requester_user_id uint, task_id uint, subtask_id uint, user_ids []uint) ([]uint, error) {
+	workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	if err := checkSubtaskInTask(subtask_id, task_id); err != nil {
+		return []uint{}, err
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, SubtaskAssignPermission)
+	if err != nil {
+		return []uint{}, err
+	}
+
+	user_ids = uniqueIDs(user_ids)
+	for _, user_id := range user_ids {
+		assignee_user_role, err := getMembershipRole(user_id, workspace_id)
+		if err != nil {
+			return []uint{}, err
+		} else if assignee_user_role == NoRole {
+			return []uint{}, custom_errors.ErrInvalidArguments
+		}
+	}
+
+	rows, err := DB.Query(`
+		INSERT INTO
+		SubtaskAssignee(subtask_id, user_id)
+		SELECT id, unnest($3::integer[])
+		FROM Subtask
+		WHERE id = $1 AND task_id = $2
+		ON CONFLICT DO NOTHING
+		RETURNING user_id;`,
+		subtask_id, task_id, pq.Array(toInt64Array(user_ids)))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	added_users := []uint{}
+
+	for rows.Next() {
+		var added_user uint
+
+		if err := rows.Scan(&added_user); err != nil {
+			log.Println("Error:", err)
+			return []uint{}, custom_errors.ErrDatabaseFailure
+		} else {
+			added_users = append(added_users, added_user)
+		}
+	}
+
+	_, err = DB.Exec(`
+		UPDATE Subtask
+		SET assignee_id = (
+			SELECT user_id
+			FROM SubtaskAssignee
+			WHERE subtask_id = $1
+			LIMIT 1
+		)
+		WHERE id = $1 AND assignee_id IS NULL;`,
+		subtask_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return added_users, nil
+}
+
+func RemoveAssigneeFromSubtask(requester_user_id uint, task_id uint, subtask_id uint, user_id uint) error {
+	workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	if err := checkSubtaskInTask(subtask_id, task_id); err != nil {
+		return err
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, SubtaskAssignPermission)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM SubtaskAssignee
+		WHERE subtask_id = $1 AND user_id = $2 AND subtask_id IN (
+			SELECT id
+			FROM Subtask
+			WHERE task_id = $3
+		);`,
+		subtask_id, user_id, task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		UPDATE Subtask
+		SET assignee_id = (
+			SELECT user_id
+			FROM SubtaskAssignee
+			WHERE subtask_id = $1
+			LIMIT 1
+		)
+		WHERE id = $1 AND assignee_id = $2;`,
+		subtask_id, user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
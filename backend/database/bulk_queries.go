@@ -0,0 +1,302 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+// checkBulkOperation validates the parts of a bulk operation that are the
+// same for every task, so a bad request fails as a whole instead of once per
+// task.
+func checkBulkOperation(requester_user_id uint, workspace_id uint, operation BulkTaskOperation) error {
+	switch operation.Type {
+	case BulkStatusOperation, BulkArchiveOperation:
+		return checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+
+	case BulkAssignOperation:
+		if err := checkPermission(requester_user_id, workspace_id, TaskAssignPermission); err != nil {
+			return err
+		}
+
+		assignee_user_role, err := getMembershipRole(operation.UserID, workspace_id)
+		if err != nil {
+			return err
+		} else if assignee_user_role == NoRole {
+			return custom_errors.ErrInvalidArguments
+		}
+
+	case BulkAddLabelOperation:
+		if err := checkPermission(requester_user_id, workspace_id, LabelApplyPermission); err != nil {
+			return err
+		}
+
+		label_workspace_id, err := getLabelWorkspaceID(operation.LabelID)
+		if err != nil {
+			return err
+		} else if label_workspace_id != workspace_id {
+			return custom_errors.ErrInvalidArguments
+		}
+
+	case BulkMoveOperation:
+		return checkTaskMove(requester_user_id, workspace_id, operation.TargetWorkspaceID)
+
+	case BulkDeleteOperation:
+		return checkPermission(requester_user_id, workspace_id, TaskDeletePermission)
+
+	default:
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
+
+// checkBulkTask validates the operation against a single task and returns the
+// title the task ends up with.
+func checkBulkTask(requester_user_id uint, workspace_id uint, task_id uint, operation BulkTaskOperation, reserved_titles map[string]bool) (string, error) {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil || actual_workspace_id != workspace_id {
+		return "", custom_errors.ErrInvalidArguments
+	}
+
+	switch operation.Type {
+	case BulkStatusOperation:
+		err := checkTaskPermission(requester_user_id, workspace_id, task_id, TaskUpdateAnyPermission, TaskUpdateOwnPermission)
+		if err != nil {
+			return "", err
+		}
+
+		if !operation.Force && (operation.Status == InProgress || operation.Status == Completed) {
+			blockers, err := GetUnfinishedBlockers(task_id)
+			if err != nil {
+				return "", err
+			} else if len(blockers) != 0 {
+				return "", custom_errors.ErrTaskBlocked
+			}
+		}
+
+	case BulkArchiveOperation:
+		err := checkTaskPermission(requester_user_id, workspace_id, task_id, TaskUpdateAnyPermission, TaskUpdateOwnPermission)
+		if err != nil {
+			return "", err
+		}
+
+	case BulkMoveOperation:
+		title, err := getTaskTitle(task_id)
+		if err != nil {
+			return "", err
+		}
+
+		return uniqueTaskTitle(operation.TargetWorkspaceID, title, reserved_titles)
+	}
+
+	return "", nil
+}
+
+func applyBulkTask(executor queryExecutor, requester_user_id uint, workspace_id uint, task_id uint, operation BulkTaskOperation, title string) error {
+	var err error
+
+	switch operation.Type {
+	case BulkStatusOperation:
+		_, err = executor.Exec(`
+			UPDATE Task
+			SET status = $1, updated_at = $2
+			WHERE id = $3;`,
+			operation.Status, time.Now(), task_id)
+
+	case BulkAssignOperation:
+		// The user is added to the assignees of the task, and only replaces
+		// them when that is asked for explicitly.
+		if operation.Replace {
+			_, err = executor.Exec(`
+				DELETE FROM TaskAssignee
+				WHERE task_id = $1 AND user_id != $2;`,
+				task_id, operation.UserID)
+		}
+
+		if err == nil {
+			_, err = executor.Exec(`
+				INSERT INTO
+				TaskAssignee(task_id, user_id)
+				VALUES($1, $2)
+				ON CONFLICT DO NOTHING;`,
+				task_id, operation.UserID)
+		}
+
+		if err == nil {
+			_, err = executor.Exec(`
+				UPDATE Task
+				SET assignee_id = CASE WHEN $4 THEN $1 ELSE COALESCE(assignee_id, $1) END, updated_at = $2
+				WHERE id = $3;`,
+				operation.UserID, time.Now(), task_id, operation.Replace)
+		}
+
+	case BulkAddLabelOperation:
+		_, err = executor.Exec(`
+			INSERT INTO
+			TaskLabel(task_id, label_id)
+			VALUES($1, $2)
+			ON CONFLICT DO NOTHING;`,
+			task_id, operation.LabelID)
+
+	case BulkMoveOperation:
+		return moveTask(executor, task_id, operation.TargetWorkspaceID, title)
+
+	case BulkDeleteOperation:
+		_, err = executor.Exec(`
+			INSERT INTO
+			Trash(workspace_id, item_type, item_id, deleted_by, deleted_at)
+			VALUES($1, $2, $3, $4, $5)
+			ON CONFLICT DO NOTHING;`,
+			workspace_id, TaskItem, task_id, requester_user_id, time.Now())
+
+	case BulkArchiveOperation:
+		_, err = executor.Exec(`
+			INSERT INTO
+			TaskArchive(task_id, archived_by, archived_at)
+			VALUES($1, $2, $3)
+			ON CONFLICT DO NOTHING;`,
+			task_id, requester_user_id, time.Now())
+	}
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+// lockBulkTasks locks the rows of the tasks and of the tasks blocking them
+// for the rest of the transaction.
+func lockBulkTasks(executor queryExecutor, task_ids []uint) error {
+	rows, err := executor.Query(`
+		SELECT id
+		FROM Task
+		WHERE id = ANY($1) OR id IN (
+			SELECT task_id
+			FROM TaskDependency
+			WHERE target_task_id = ANY($1) AND type = $2
+		)
+		ORDER BY id
+		FOR UPDATE;`,
+		pq.Array(toInt64Array(task_ids)), Blocks)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	if err := rows.Close(); err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+// BulkUpdateTasks runs one operation over many tasks of the workspace. Every
+// task is checked on its own and reported in the results, and the changes to
+// the tasks that pass are applied together in the transaction that holds the
+// tasks locked while they are checked.
+func BulkUpdateTasks(requester_user_id uint, workspace_id uint, task_ids []uint, operation BulkTaskOperation) ([]BulkTaskResult, error) {
+	if err := checkBulkOperation(requester_user_id, workspace_id, operation); err != nil {
+		return []BulkTaskResult{}, err
+	}
+
+	task_ids = uniqueIDs(task_ids)
+
+	tx, err := DB.Begin()
+	if err != nil {
+		log.Println("Error:", err)
+		return []BulkTaskResult{}, custom_errors.ErrDatabaseFailure
+	}
+
+	// The tasks and the tasks that may block them stay locked until the
+	// changes are committed, so they are applied to the state that was checked.
+	if err := lockBulkTasks(tx, task_ids); err != nil {
+		tx.Rollback()
+		return []BulkTaskResult{}, err
+	}
+
+	results := make([]BulkTaskResult, len(task_ids))
+	titles := make([]string, len(task_ids))
+	reserved_titles := make(map[string]bool)
+
+	for i, task_id := range task_ids {
+		results[i].TaskID = task_id
+
+		title, err := checkBulkTask(requester_user_id, workspace_id, task_id, operation, reserved_titles)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		if operation.Type == BulkAssignOperation {
+			is_assignee, err := isTaskAssignee(operation.UserID, task_id)
+			if err != nil {
+				results[i].Error = err.Error()
+				continue
+			}
+			results[i].Assigned = !is_assignee
+		}
+
+		titles[i] = title
+		results[i].Success = true
+	}
+
+	for i, task_id := range task_ids {
+		if !results[i].Success {
+			continue
+		}
+
+		if err := applyBulkTask(tx, requester_user_id, workspace_id, task_id, operation, titles[i]); err != nil {
+			tx.Rollback()
+			return []BulkTaskResult{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error:", err)
+		return []BulkTaskResult{}, custom_errors.ErrDatabaseFailure
+	}
+
+	tasks := []Task{}
+	for i, task_id := range task_ids {
+		if !results[i].Success {
+			continue
+		}
+
+		task, err := scanTask(DB.QueryRow(`
+			SELECT *
+			FROM Task
+			WHERE id = $1;`,
+			task_id))
+
+		if err != nil {
+			log.Println("Error:", err)
+			return []BulkTaskResult{}, custom_errors.ErrDatabaseFailure
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err := attachTaskAssignees(tasks); err != nil {
+		return []BulkTaskResult{}, err
+	}
+
+	if err := attachTaskArchives(tasks); err != nil {
+		return []BulkTaskResult{}, err
+	}
+
+	for i, j := 0, 0; i < len(results); i++ {
+		if results[i].Success {
+			results[i].Task = &tasks[j]
+			j++
+		}
+	}
+
+	return results, nil
+}
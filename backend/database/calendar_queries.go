@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func scanCalendarFeed(row rowScanner) (CalendarFeed, error) {
+	var calendar_feed CalendarFeed
+
+	err := row.Scan(
+		&calendar_feed.UserID,
+		&calendar_feed.Token,
+		&calendar_feed.CreatedAt)
+
+	return calendar_feed, err
+}
+
+// GetCalendarFeed returns the calendar feed of the user, setting one up the
+// first time it is asked for.
+func GetCalendarFeed(requester_user_id uint) (CalendarFeed, error) {
+	token, err := generateSecretToken()
+	if err != nil {
+		log.Println("Error:", err)
+		return CalendarFeed{}, custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		CalendarFeed(user_id, token, created_at)
+		VALUES($1, $2, $3)
+		ON CONFLICT (user_id) DO NOTHING;`,
+		requester_user_id, token, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return CalendarFeed{}, custom_errors.ErrDatabaseFailure
+	}
+
+	calendar_feed, err := scanCalendarFeed(DB.QueryRow(`
+		SELECT *
+		FROM CalendarFeed
+		WHERE user_id = $1;`,
+		requester_user_id))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return CalendarFeed{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return calendar_feed, nil
+}
+
+// RegenerateCalendarFeed replaces the token of the feed, so calendars that
+// subscribed with the old one stop receiving it.
+func RegenerateCalendarFeed(requester_user_id uint) (CalendarFeed, error) {
+	token, err := generateSecretToken()
+	if err != nil {
+		log.Println("Error:", err)
+		return CalendarFeed{}, custom_errors.ErrDatabaseFailure
+	}
+
+	calendar_feed, err := scanCalendarFeed(DB.QueryRow(`
+		INSERT INTO
+		CalendarFeed(user_id, token, created_at)
+		VALUES($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET token = EXCLUDED.token, created_at = EXCLUDED.created_at
+		RETURNING *;`,
+		requester_user_id, token, time.Now()))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return CalendarFeed{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return calendar_feed, nil
+}
+
+func GetCalendarFeedUserID(token string) (uint, error) {
+	var user_id uint
+
+	err := DB.QueryRow(`
+		SELECT user_id
+		FROM CalendarFeed
+		WHERE token = $1;`,
+		token).
+		Scan(&user_id)
+
+	if err == sql.ErrNoRows {
+		return 0, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	return user_id, nil
+}
@@ -192,6 +192,679 @@ func createWatchTable() {
 
 }
 
+func createLabelTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS Label (
+								id integer PRIMARY KEY generated always as identity, 
+								workspace_id integer not null,
+								name varchar(30) not null,
+								color varchar(7) not null,
+								created_at timestamp,
+								updated_at timestamp,
+								UNIQUE (workspace_id, name),
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createTaskLabelTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS TaskLabel (
+								task_id integer not null,
+								label_id integer not null,
+								PRIMARY KEY (task_id, label_id),
+								FOREIGN KEY(task_id) REFERENCES Task(id) ON DELETE CASCADE,
+								FOREIGN KEY(label_id) REFERENCES Label(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createTaskAssigneeTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS TaskAssignee (
+								task_id integer not null,
+								user_id integer not null,
+								PRIMARY KEY (task_id, user_id),
+								FOREIGN KEY(task_id) REFERENCES Task(id) ON DELETE CASCADE,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		TaskAssignee(task_id, user_id)
+		SELECT id, assignee_id
+		FROM Task
+		WHERE assignee_id IS NOT NULL
+		ON CONFLICT DO NOTHING;`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+
+}
+
+func createSubtaskAssigneeTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS SubtaskAssignee (
+								subtask_id integer not null,
+								user_id integer not null,
+								PRIMARY KEY (subtask_id, user_id),
+								FOREIGN KEY(subtask_id) REFERENCES Subtask(id) ON DELETE CASCADE,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		SubtaskAssignee(subtask_id, user_id)
+		SELECT id, assignee_id
+		FROM Subtask
+		WHERE assignee_id IS NOT NULL
+		ON CONFLICT DO NOTHING;`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+
+}
+
+func createTaskDependencyTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS TaskDependency (
+								id integer PRIMARY KEY generated always as identity, 
+								task_id integer not null,
+								target_task_id integer not null,
+								type varchar(30) not null,
+								created_at timestamp,
+								UNIQUE (task_id, target_task_id, type),
+								FOREIGN KEY(task_id) REFERENCES Task(id) ON DELETE CASCADE,
+								FOREIGN KEY(target_task_id) REFERENCES Task(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createRecurringTaskTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS RecurringTask (
+								id integer PRIMARY KEY generated always as identity, 
+								workspace_id integer not null,
+								template_task_id integer not null,
+								rule varchar(100) not null,
+								start_at timestamp not null,
+								next_run_at timestamp not null,
+								is_paused boolean not null default false,
+								created_by integer not null,
+								created_at timestamp,
+								updated_at timestamp,
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE,
+								FOREIGN KEY(template_task_id) REFERENCES Task(id) ON DELETE CASCADE,
+								FOREIGN KEY(created_by) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createRecurringTaskInstanceTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS RecurringTaskInstance (
+								recurring_task_id integer not null,
+								occurrence timestamp not null,
+								task_id integer,
+								PRIMARY KEY (recurring_task_id, occurrence),
+								FOREIGN KEY(recurring_task_id) REFERENCES RecurringTask(id) ON DELETE CASCADE,
+								FOREIGN KEY(task_id) REFERENCES Task(id) ON DELETE SET NULL
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createTemplateTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS Template (
+								id integer PRIMARY KEY generated always as identity, 
+								user_id integer not null,
+								name varchar(30) not null,
+								type varchar(30) not null,
+								created_at timestamp,
+								updated_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createTemplateTaskTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS TemplateTask (
+								id integer PRIMARY KEY generated always as identity, 
+								template_id integer not null,
+								title varchar(30) not null,
+								description TEXT,
+								estimated_time integer,
+								priority integer,
+								due_offset integer not null default 0,
+								image_url varchar(100),
+								subtasks TEXT[] not null default '{}',
+								labels TEXT[] not null default '{}',
+								FOREIGN KEY(template_id) REFERENCES Template(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createTemplateLabelTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS TemplateLabel (
+								template_id integer not null,
+								name varchar(30) not null,
+								color varchar(7) not null,
+								PRIMARY KEY (template_id, name),
+								FOREIGN KEY(template_id) REFERENCES Template(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createTaskReminderTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS TaskReminder (
+								task_id integer not null,
+								kind varchar(30) not null,
+								due_date timestamp not null,
+								sent_at timestamp,
+								PRIMARY KEY (task_id, kind, due_date),
+								FOREIGN KEY(task_id) REFERENCES Task(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createNotificationTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS Notification (
+								id integer PRIMARY KEY generated always as identity, 
+								user_id integer not null,
+								event varchar(30) not null,
+								message TEXT not null,
+								is_read boolean not null default false,
+								created_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createNotificationPreferenceTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS NotificationPreference (
+								user_id integer not null,
+								event varchar(30) not null,
+								enabled boolean not null,
+								PRIMARY KEY (user_id, event),
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createEmailSettingsTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS EmailSettings (
+								user_id integer PRIMARY KEY,
+								mode varchar(30) not null,
+								unsubscribe_token varchar(64) not null,
+								last_digest_at timestamp,
+								UNIQUE (unsubscribe_token),
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createEmailOutboxTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS EmailOutbox (
+								id integer PRIMARY KEY generated always as identity, 
+								user_id integer not null,
+								recipient varchar(255) not null,
+								subject TEXT not null,
+								text_body TEXT not null,
+								html_body TEXT not null,
+								unsubscribe_url TEXT not null,
+								status varchar(30) not null,
+								attempts integer not null default 0,
+								next_attempt_at timestamp not null,
+								last_error TEXT not null default '',
+								created_at timestamp,
+								sent_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createWebhookTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS Webhook (
+								id integer PRIMARY KEY generated always as identity, 
+								workspace_id integer not null,
+								url TEXT not null,
+								secret varchar(64) not null,
+								events TEXT[] not null default '{}',
+								is_active boolean not null default true,
+								created_by integer,
+								created_at timestamp,
+								updated_at timestamp,
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE,
+								FOREIGN KEY(created_by) REFERENCES Users(id) ON DELETE SET NULL
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createWebhookDeliveryTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS WebhookDelivery (
+								id integer PRIMARY KEY generated always as identity, 
+								webhook_id integer not null,
+								event varchar(30) not null,
+								payload TEXT not null,
+								status varchar(30) not null,
+								attempts integer not null default 0,
+								next_attempt_at timestamp not null,
+								response_status integer not null default 0,
+								last_error TEXT not null default '',
+								created_at timestamp,
+								delivered_at timestamp,
+								FOREIGN KEY(webhook_id) REFERENCES Webhook(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createIncomingWebhookTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS IncomingWebhook (
+								id integer PRIMARY KEY generated always as identity, 
+								workspace_id integer not null,
+								name varchar(30) not null,
+								token varchar(64) not null UNIQUE,
+								field_mapping TEXT not null default '{}',
+								default_values TEXT not null default '{}',
+								created_by integer,
+								created_at timestamp,
+								updated_at timestamp,
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE,
+								FOREIGN KEY(created_by) REFERENCES Users(id) ON DELETE SET NULL
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createTaskSourceTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS TaskSource (
+								id integer PRIMARY KEY generated always as identity, 
+								incoming_webhook_id integer not null,
+								external_id varchar(100),
+								task_id integer,
+								source_url TEXT not null default '',
+								created_at timestamp,
+								UNIQUE(incoming_webhook_id, external_id),
+								FOREIGN KEY(incoming_webhook_id) REFERENCES IncomingWebhook(id) ON DELETE CASCADE,
+								FOREIGN KEY(task_id) REFERENCES Task(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createPersonalAccessTokenTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS PersonalAccessToken (
+								id integer PRIMARY KEY generated always as identity, 
+								user_id integer not null,
+								name varchar(30) not null,
+								token_hash BYTEA not null UNIQUE,
+								scopes TEXT[] not null default '{}',
+								expires_at timestamp,
+								last_used_at timestamp,
+								created_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createServiceAccountTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS ServiceAccount (
+								user_id integer PRIMARY KEY,
+								workspace_id integer not null,
+								name varchar(30) not null,
+								created_by integer,
+								created_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE,
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE,
+								FOREIGN KEY(created_by) REFERENCES Users(id) ON DELETE SET NULL
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createUserIdentityTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS UserIdentity (
+								id integer PRIMARY KEY generated always as identity, 
+								user_id integer not null,
+								issuer TEXT not null,
+								subject TEXT not null,
+								created_at timestamp,
+								UNIQUE(issuer, subject),
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createTwoFactorTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS TwoFactor (
+								user_id integer PRIMARY KEY,
+								secret varchar(64) not null,
+								is_enabled boolean not null default false,
+								last_used_step bigint not null default 0,
+								created_at timestamp,
+								enabled_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createRecoveryCodeTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS RecoveryCode (
+								id integer PRIMARY KEY generated always as identity, 
+								user_id integer not null,
+								code_hash BYTEA not null,
+								used_at timestamp,
+								created_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createWorkspaceSecurityTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS WorkspaceSecurity (
+								workspace_id integer PRIMARY KEY,
+								require_two_factor boolean not null default false,
+								updated_by integer,
+								updated_at timestamp,
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE,
+								FOREIGN KEY(updated_by) REFERENCES Users(id) ON DELETE SET NULL
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createEmailVerificationTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS EmailVerification (
+								user_id integer PRIMARY KEY,
+								is_verified boolean not null default false,
+								verified_at timestamp,
+								created_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createAccountTokenTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS AccountToken (
+								id integer PRIMARY KEY generated always as identity, 
+								user_id integer not null,
+								purpose varchar(30) not null,
+								token_hash BYTEA not null UNIQUE,
+								expires_at timestamp not null,
+								used_at timestamp,
+								created_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createSessionRevocationTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS SessionRevocation (
+								user_id integer PRIMARY KEY,
+								revoked_at timestamp not null,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createInvitationTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS Invitation (
+								id integer PRIMARY KEY generated always as identity, 
+								workspace_id integer not null,
+								kind varchar(10) not null,
+								invitee_user_id integer,
+								invitee_email varchar(255),
+								role varchar(30) not null,
+								token varchar(64) not null UNIQUE,
+								max_uses integer,
+								use_count integer not null default 0,
+								status varchar(20) not null,
+								invited_by integer,
+								expires_at timestamp not null,
+								created_at timestamp,
+								updated_at timestamp,
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE,
+								FOREIGN KEY(invitee_user_id) REFERENCES Users(id) ON DELETE CASCADE,
+								FOREIGN KEY(invited_by) REFERENCES Users(id) ON DELETE SET NULL
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createCustomRoleTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS CustomRole (
+								id integer PRIMARY KEY generated always as identity, 
+								workspace_id integer not null,
+								name varchar(30) not null,
+								permissions TEXT[] not null,
+								created_by integer,
+								created_at timestamp,
+								updated_at timestamp,
+								UNIQUE (workspace_id, name),
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE,
+								FOREIGN KEY(created_by) REFERENCES Users(id) ON DELETE SET NULL
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createOwnershipTransferTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS OwnershipTransfer (
+								id integer PRIMARY KEY generated always as identity, 
+								workspace_id integer not null,
+								from_user_id integer not null,
+								to_user_id integer not null,
+								stay_owner boolean not null default false,
+								status varchar(20) not null,
+								expires_at timestamp not null,
+								created_at timestamp,
+								updated_at timestamp,
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE,
+								FOREIGN KEY(from_user_id) REFERENCES Users(id) ON DELETE CASCADE,
+								FOREIGN KEY(to_user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createTrashTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS Trash (
+								id integer PRIMARY KEY generated always as identity, 
+								workspace_id integer not null,
+								item_type varchar(20) not null,
+								item_id integer not null,
+								parent_id integer,
+								deleted_by integer,
+								deleted_at timestamp not null,
+								UNIQUE (item_type, item_id),
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE,
+								FOREIGN KEY(deleted_by) REFERENCES Users(id) ON DELETE SET NULL
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createTaskArchiveTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS TaskArchive (
+								task_id integer PRIMARY KEY,
+								archived_by integer,
+								archived_at timestamp not null,
+								FOREIGN KEY(task_id) REFERENCES Task(id) ON DELETE CASCADE,
+								FOREIGN KEY(archived_by) REFERENCES Users(id) ON DELETE SET NULL
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createWorkspaceArchivePolicyTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS WorkspaceArchivePolicy (
+								workspace_id integer PRIMARY KEY,
+								auto_archive_days integer not null default 0,
+								updated_by integer,
+								updated_at timestamp,
+								FOREIGN KEY(workspace_id) REFERENCES Workspace(id) ON DELETE CASCADE,
+								FOREIGN KEY(updated_by) REFERENCES Users(id) ON DELETE SET NULL
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createImportJobTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS ImportJob (
+								id integer PRIMARY KEY generated always as identity, 
+								user_id integer not null,
+								source varchar(30) not null,
+								status varchar(30) not null,
+								dry_run boolean not null default false,
+								payload TEXT not null,
+								total integer not null default 0,
+								processed integer not null default 0,
+								result TEXT not null default '',
+								last_error TEXT not null default '',
+								created_at timestamp,
+								updated_at timestamp,
+								finished_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
+func createCalendarFeedTable() {
+	query, err := DB.Prepare(`CREATE TABLE IF NOT EXISTS CalendarFeed (
+								user_id integer PRIMARY KEY,
+								token varchar(64) not null UNIQUE,
+								created_at timestamp,
+								FOREIGN KEY(user_id) REFERENCES Users(id) ON DELETE CASCADE
+							)`)
+	if err != nil {
+		log.Fatal("Error:", err)
+	}
+	query.Exec()
+
+}
+
 func createTables() {
 	createWorkspaceTable()
 	createUserTable()
@@ -200,11 +873,48 @@ func createTables() {
 	createUserWorkspaceRoleTable()
 	createCommentTable()
 	createWatchTable()
+	createLabelTable()
+	createTaskLabelTable()
+	createTaskAssigneeTable()
+	createSubtaskAssigneeTable()
+	createTaskDependencyTable()
+	createRecurringTaskTable()
+	createRecurringTaskInstanceTable()
+	createTemplateTable()
+	createTemplateTaskTable()
+	createTemplateLabelTable()
+	createTaskReminderTable()
+	createNotificationTable()
+	createNotificationPreferenceTable()
+	createEmailSettingsTable()
+	createEmailOutboxTable()
+	createWebhookTable()
+	createWebhookDeliveryTable()
+	createIncomingWebhookTable()
+	createTaskSourceTable()
+	createPersonalAccessTokenTable()
+	createServiceAccountTable()
+	createUserIdentityTable()
+	createTwoFactorTable()
+	createRecoveryCodeTable()
+	createWorkspaceSecurityTable()
+	createEmailVerificationTable()
+	createAccountTokenTable()
+	createSessionRevocationTable()
+	createInvitationTable()
+	createCustomRoleTable()
+	createOwnershipTransferTable()
+	createTrashTable()
+	createTaskArchiveTable()
+	createWorkspaceArchivePolicyTable()
+	createImportJobTable()
+	createCalendarFeedTable()
 }
 
 func InitializeDatabase() {
 	loadDatabaseConnectionConfig()
 	connectToDatabase()
 	createTables()
+	adoptOrphanedWorkspaces()
 	RedisInitial()
 }
@@ -0,0 +1,276 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func scanTaskDependencies(rows *sql.Rows) ([]TaskDependency, error) {
+	dependencies := []TaskDependency{}
+
+	for rows.Next() {
+		var dependency TaskDependency
+
+		if err := rows.Scan(
+			&dependency.ID,
+			&dependency.TaskID,
+			&dependency.TargetTaskID,
+			&dependency.Type,
+			&dependency.CreatedAt); err != nil {
+			log.Println("Error:", err)
+			return []TaskDependency{}, custom_errors.ErrDatabaseFailure
+		} else {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	return dependencies, nil
+}
+
+func checkDependencyCycle(task_id uint, target_task_id uint) (bool, error) {
+	var creates_cycle bool
+
+	err := DB.QueryRow(`
+		WITH RECURSIVE Reachable(task_id) AS (
+			SELECT $1::integer
+			UNION
+			SELECT D.target_task_id
+			FROM TaskDependency D JOIN Reachable R
+			ON D.task_id = R.task_id
+			WHERE D.type = $3
+		)
+		SELECT EXISTS (
+			SELECT 1
+			FROM Reachable
+			WHERE task_id = $2
+		);`,
+		target_task_id, task_id, Blocks).
+		Scan(&creates_cycle)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return creates_cycle, nil
+}
+
+func GetUnfinishedBlockers(task_id uint) ([]Task, error) {
+	rows, err := DB.Query(`
+		SELECT A.*
+		FROM Task A JOIN TaskDependency B
+		ON A.id = B.task_id
+		WHERE B.target_task_id = $1 AND B.type = $2 AND A.status != $3 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $4 AND item_id = A.id
+		);`,
+		task_id, Blocks, Completed, TaskItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []Task{}, custom_errors.ErrDatabaseFailure
+	}
+
+	var blockers []Task
+
+	for rows.Next() {
+		if blocker, err := scanTask(rows); err != nil {
+			log.Println("Error:", err)
+			return []Task{}, custom_errors.ErrDatabaseFailure
+		} else {
+			blockers = append(blockers, blocker)
+		}
+	}
+
+	return blockers, nil
+}
+
+func GetDependenciesOfTask(requester_user_id uint, workspace_id uint, task_id uint) ([]TaskDependency, error) {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return []TaskDependency{}, err
+	} else if actual_workspace_id != workspace_id {
+		return []TaskDependency{}, custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return []TaskDependency{}, err
+	}
+
+	rows, err := DB.Query(`
+		SELECT *
+		FROM TaskDependency
+		WHERE (task_id = $1 OR target_task_id = $1) AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $2 AND item_id IN (task_id, target_task_id)
+		);`,
+		task_id, TaskItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []TaskDependency{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return scanTaskDependencies(rows)
+}
+
+func AddDependencyToTask(requester_user_id uint, workspace_id uint, task_id uint, target_task_id uint, dependency_type string) (TaskDependency, error) {
+	if task_id == target_task_id {
+		return TaskDependency{}, custom_errors.ErrInvalidArguments
+	}
+
+	task_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return TaskDependency{}, err
+	} else if task_workspace_id != workspace_id {
+		return TaskDependency{}, custom_errors.ErrInvalidArguments
+	}
+
+	target_workspace_id, err := getTaskWorkspaceID(target_task_id)
+	if err != nil {
+		return TaskDependency{}, err
+	} else if target_workspace_id != workspace_id {
+		return TaskDependency{}, custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, DependencyManagePermission)
+	if err != nil {
+		return TaskDependency{}, err
+	}
+
+	if dependency_type == BlockedBy {
+		task_id, target_task_id = target_task_id, task_id
+		dependency_type = Blocks
+	}
+
+	if dependency_type == Blocks {
+		creates_cycle, err := checkDependencyCycle(task_id, target_task_id)
+		if err != nil {
+			return TaskDependency{}, err
+		} else if creates_cycle {
+			return TaskDependency{}, custom_errors.ErrDependencyCycle
+		}
+	}
+
+	var dependency TaskDependency
+
+	err = DB.QueryRow(`
+		INSERT INTO
+		TaskDependency(task_id, target_task_id, type, created_at)
+		VALUES($1, $2, $3, $4)
+		RETURNING *;`,
+		task_id, target_task_id, dependency_type, time.Now()).
+		Scan(&dependency.ID,
+			&dependency.TaskID,
+			&dependency.TargetTaskID,
+			&dependency.Type,
+			&dependency.CreatedAt)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return TaskDependency{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return dependency, nil
+}
+
+func DeleteDependency(requester_user_id uint, workspace_id uint, task_id uint, dependency_id uint) error {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, DependencyManagePermission)
+	if err != nil {
+		return err
+	}
+
+	result, err := DB.Exec(`
+		DELETE FROM TaskDependency
+		WHERE id = $1 AND (task_id = $2 OR target_task_id = $2);`,
+		dependency_id, task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
+
+func GetDependencyGraph(requester_user_id uint, workspace_id uint) (DependencyGraph, error) {
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return DependencyGraph{}, err
+	}
+
+	rows, err := DB.Query(`
+		SELECT A.id, A.title, A.status, EXISTS (
+			SELECT 1
+			FROM TaskDependency B JOIN Task C
+			ON B.task_id = C.id
+			WHERE B.target_task_id = A.id AND B.type = $2 AND C.status != $3 AND NOT EXISTS (
+				SELECT 1 FROM Trash
+				WHERE item_type = $4 AND item_id = C.id
+			)
+		)
+		FROM Task A
+		WHERE A.workspace_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $4 AND item_id = A.id
+		);`,
+		workspace_id, Blocks, Completed, TaskItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return DependencyGraph{}, custom_errors.ErrDatabaseFailure
+	}
+
+	graph := DependencyGraph{
+		Nodes: []DependencyGraphNode{},
+		Edges: []TaskDependency{},
+	}
+
+	for rows.Next() {
+		var node DependencyGraphNode
+
+		if err := rows.Scan(&node.ID, &node.Title, &node.Status, &node.Blocked); err != nil {
+			log.Println("Error:", err)
+			return DependencyGraph{}, custom_errors.ErrDatabaseFailure
+		} else {
+			graph.Nodes = append(graph.Nodes, node)
+		}
+	}
+
+	rows, err = DB.Query(`
+		SELECT A.*
+		FROM TaskDependency A JOIN Task B
+		ON A.task_id = B.id
+		WHERE B.workspace_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $2 AND item_id IN (A.task_id, A.target_task_id)
+		);`,
+		workspace_id, TaskItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return DependencyGraph{}, custom_errors.ErrDatabaseFailure
+	}
+
+	graph.Edges, err = scanTaskDependencies(rows)
+	if err != nil {
+		return DependencyGraph{}, err
+	}
+
+	return graph, nil
+}
@@ -0,0 +1,387 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	email_utils "github.com/skye-tan/trello/backend/utils/email"
+)
+
+var EmailEvents = []string{AssignmentEvent, MentionEvent, ReminderEvent, InvitationEvent}
+
+func isEmailEvent(event string) bool {
+	for _, item := range EmailEvents {
+		if item == event {
+			return true
+		}
+	}
+	return false
+}
+
+func scanEmailSettings(row rowScanner) (EmailSettings, error) {
+	var email_settings EmailSettings
+	var last_digest_at sql.NullTime
+
+	err := row.Scan(
+		&email_settings.UserID,
+		&email_settings.Mode,
+		&email_settings.UnsubscribeToken,
+		&last_digest_at)
+
+	email_settings.LastDigestAt = last_digest_at.Time
+
+	return email_settings, err
+}
+
+func scanOutboxEmail(row rowScanner) (OutboxEmail, error) {
+	var outbox_email OutboxEmail
+	var sent_at sql.NullTime
+
+	err := row.Scan(
+		&outbox_email.ID,
+		&outbox_email.UserID,
+		&outbox_email.Recipient,
+		&outbox_email.Subject,
+		&outbox_email.TextBody,
+		&outbox_email.HTMLBody,
+		&outbox_email.UnsubscribeURL,
+		&outbox_email.Status,
+		&outbox_email.Attempts,
+		&outbox_email.NextAttemptAt,
+		&outbox_email.LastError,
+		&outbox_email.CreatedAt,
+		&sent_at)
+
+	outbox_email.SentAt = sent_at.Time
+
+	return outbox_email, err
+}
+
+func getEmailSettings(user_id uint) (EmailSettings, error) {
+	token, err := generateSecretToken()
+	if err != nil {
+		log.Println("Error:", err)
+		return EmailSettings{}, custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		EmailSettings(user_id, mode, unsubscribe_token)
+		VALUES($1, $2, $3)
+		ON CONFLICT (user_id) DO NOTHING;`,
+		user_id, InstantEmail, token)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return EmailSettings{}, custom_errors.ErrDatabaseFailure
+	}
+
+	email_settings, err := scanEmailSettings(DB.QueryRow(`
+		SELECT *
+		FROM EmailSettings
+		WHERE user_id = $1;`,
+		user_id))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return EmailSettings{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return email_settings, nil
+}
+
+func queueEmail(user_id uint, recipient string, template string, data email_utils.TemplateData) error {
+	subject, text_body, html_body, err := email_utils.Render(template, data)
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrInvalidArguments
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		EmailOutbox(user_id, recipient, subject, text_body, html_body, unsubscribe_url, status, next_attempt_at, created_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+		user_id, recipient, subject, text_body, html_body,
+		data.UnsubscribeURL, PendingEmail, time.Now(), time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetEmailSettings(requester_user_id uint) (EmailSettings, error) {
+	return getEmailSettings(requester_user_id)
+}
+
+func UpdateEmailSettings(requester_user_id uint, mode string) error {
+	if mode != InstantEmail && mode != DigestEmail && mode != NoEmail {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	if _, err := getEmailSettings(requester_user_id); err != nil {
+		return err
+	}
+
+	_, err := DB.Exec(`
+		UPDATE EmailSettings
+		SET mode = $1, last_digest_at = COALESCE(last_digest_at, $2)
+		WHERE user_id = $3;`,
+		mode, time.Now(), requester_user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func UnsubscribeByToken(token string) error {
+	result, err := DB.Exec(`
+		UPDATE EmailSettings
+		SET mode = $1
+		WHERE unsubscribe_token = $2;`,
+		NoEmail, token)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
+
+func QueueNotificationEmails(event string, user_ids []uint, message string) error {
+	if !isEmailEvent(event) || len(user_ids) == 0 {
+		return nil
+	}
+
+	rows, err := DB.Query(`
+		SELECT id, username, email
+		FROM Users
+		WHERE id = ANY($1::integer[]);`,
+		pq.Array(toInt64Array(user_ids)))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	var users []User
+
+	for rows.Next() {
+		var user User
+
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		users = append(users, user)
+	}
+
+	for _, user := range users {
+		email_settings, err := getEmailSettings(user.ID)
+		if err != nil {
+			return err
+		} else if email_settings.Mode != InstantEmail {
+			continue
+		}
+
+		err = queueEmail(user.ID, user.Email, event, email_utils.TemplateData{
+			Username:       user.Username,
+			Message:        message,
+			UnsubscribeURL: email_utils.UnsubscribeURL(email_settings.UnsubscribeToken),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func GetDigestRecipients(now time.Time) ([]uint, error) {
+	rows, err := DB.Query(`
+		SELECT user_id
+		FROM EmailSettings
+		WHERE mode = $1 AND (last_digest_at IS NULL OR last_digest_at <= $2);`,
+		DigestEmail, now.Add(-24*time.Hour))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	recipients := []uint{}
+
+	for rows.Next() {
+		var user_id uint
+
+		if err := rows.Scan(&user_id); err != nil {
+			log.Println("Error:", err)
+			return []uint{}, custom_errors.ErrDatabaseFailure
+		}
+
+		recipients = append(recipients, user_id)
+	}
+
+	return recipients, nil
+}
+
+func QueueDigestEmail(user_id uint, now time.Time) error {
+	email_settings, err := getEmailSettings(user_id)
+	if err != nil {
+		return err
+	}
+
+	since := email_settings.LastDigestAt
+	if since.IsZero() {
+		since = now.Add(-24 * time.Hour)
+	}
+
+	var user User
+
+	err = DB.QueryRow(`
+		SELECT username, email
+		FROM Users
+		WHERE id = $1;`,
+		user_id).Scan(&user.Username, &user.Email)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	rows, err := DB.Query(`
+		SELECT message
+		FROM Notification
+		WHERE user_id = $1 AND created_at > $2 AND created_at <= $3 AND event = ANY($4::varchar[])
+		ORDER BY created_at;`,
+		user_id, since, now, pq.Array(EmailEvents))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	messages := []string{}
+
+	for rows.Next() {
+		var message string
+
+		if err := rows.Scan(&message); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		messages = append(messages, message)
+	}
+
+	if len(messages) != 0 {
+		err = queueEmail(user_id, user.Email, "digest", email_utils.TemplateData{
+			Username:       user.Username,
+			Messages:       messages,
+			UnsubscribeURL: email_utils.UnsubscribeURL(email_settings.UnsubscribeToken),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = DB.Exec(`
+		UPDATE EmailSettings
+		SET last_digest_at = $1
+		WHERE user_id = $2;`,
+		now, user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func ClaimPendingEmails(now time.Time, limit int, lease time.Duration) ([]OutboxEmail, error) {
+	rows, err := DB.Query(`
+		UPDATE EmailOutbox
+		SET status = $1, attempts = attempts + 1, next_attempt_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM EmailOutbox
+			WHERE status IN ($3, $1) AND next_attempt_at <= $4
+			ORDER BY id
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *;`,
+		SendingEmail, now.Add(lease), PendingEmail, now, limit)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []OutboxEmail{}, custom_errors.ErrDatabaseFailure
+	}
+
+	outbox_emails := []OutboxEmail{}
+
+	for rows.Next() {
+		if outbox_email, err := scanOutboxEmail(rows); err != nil {
+			log.Println("Error:", err)
+			return []OutboxEmail{}, custom_errors.ErrDatabaseFailure
+		} else {
+			outbox_emails = append(outbox_emails, outbox_email)
+		}
+	}
+
+	return outbox_emails, nil
+}
+
+func MarkEmailSent(outbox_email_id uint) error {
+	_, err := DB.Exec(`
+		UPDATE EmailOutbox
+		SET status = $1, sent_at = $2, last_error = ''
+		WHERE id = $3;`,
+		SentEmail, time.Now(), outbox_email_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func MarkEmailFailed(outbox_email_id uint, give_up bool, next_attempt_at time.Time, last_error string) error {
+	status := PendingEmail
+	if give_up {
+		status = FailedEmail
+	}
+
+	_, err := DB.Exec(`
+		UPDATE EmailOutbox
+		SET status = $1, next_attempt_at = $2, last_error = $3
+		WHERE id = $4;`,
+		status, next_attempt_at, last_error, outbox_email_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
@@ -0,0 +1,380 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+// queryNames groups the names in the second column of the query by the id in
+// its first one.
+func queryNames(query string, args ...any) (map[uint][]string, error) {
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		log.Println("Error:", err)
+		return map[uint][]string{}, custom_errors.ErrDatabaseFailure
+	}
+
+	names := make(map[uint][]string)
+
+	for rows.Next() {
+		var id uint
+		var name string
+
+		if err := rows.Scan(&id, &name); err != nil {
+			log.Println("Error:", err)
+			return map[uint][]string{}, custom_errors.ErrDatabaseFailure
+		}
+
+		names[id] = append(names[id], name)
+	}
+
+	return names, nil
+}
+
+func exportWorkspaceDetails(export *WorkspaceExport, workspace_id uint) error {
+	err := DB.QueryRow(`
+		SELECT name, COALESCE(description, '')
+		FROM Workspace
+		WHERE id = $1;`,
+		workspace_id).
+		Scan(&export.Workspace.Name, &export.Workspace.Description)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	rows, err := DB.Query(`
+		SELECT name, permissions
+		FROM CustomRole
+		WHERE workspace_id = $1
+		ORDER BY id;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var role ExportedRole
+
+		if err := rows.Scan(&role.Name, pq.Array(&role.Permissions)); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		export.Roles = append(export.Roles, role)
+	}
+
+	rows, err = DB.Query(`
+		SELECT B.username, A.role
+		FROM UserWorkspaceRole A JOIN Users B
+		ON A.user_id = B.id
+		WHERE A.workspace_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM ServiceAccount
+			WHERE user_id = A.user_id
+		)
+		ORDER BY A.id;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var member ExportedMember
+
+		if err := rows.Scan(&member.Username, &member.Role); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		export.Members = append(export.Members, member)
+	}
+
+	rows, err = DB.Query(`
+		SELECT id, name, color
+		FROM Label
+		WHERE workspace_id = $1
+		ORDER BY id;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var label ExportedLabel
+
+		if err := rows.Scan(&label.ID, &label.Name, &label.Color); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		export.Labels = append(export.Labels, label)
+	}
+
+	return nil
+}
+
+func exportTasks(export *WorkspaceExport, workspace_id uint, tasks []Task) error {
+	task_indexes := make(map[uint]int)
+
+	assignees, err := queryNames(`
+		SELECT A.task_id, B.username
+		FROM TaskAssignee A JOIN Users B ON A.user_id = B.id
+		JOIN Task C ON A.task_id = C.id
+		WHERE C.workspace_id = $1;`,
+		workspace_id)
+	if err != nil {
+		return err
+	}
+
+	watchers, err := queryNames(`
+		SELECT A.task_id, B.username
+		FROM Watch A JOIN Users B ON A.user_id = B.id
+		JOIN Task C ON A.task_id = C.id
+		WHERE C.workspace_id = $1;`,
+		workspace_id)
+	if err != nil {
+		return err
+	}
+
+	task_ids := []uint{}
+	for _, task := range tasks {
+		task_ids = append(task_ids, task.ID)
+	}
+
+	pictures, err := getPictures(task_ids)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		task_indexes[task.ID] = len(export.Tasks)
+		export.Tasks = append(export.Tasks, ExportedTask{
+			ID:            task.ID,
+			Title:         task.Title,
+			Description:   task.Description,
+			Status:        task.Status,
+			EstimatedTime: task.EstimatedTime,
+			ActualTime:    task.ActualTime,
+			DueDate:       task.DueDate,
+			Priority:      task.Priority,
+			ImageURL:      task.ImageURL,
+			Picture:       pictures[task.ID],
+			IsArchived:    task.IsArchived,
+			Assignees:     append([]string{}, assignees[task.ID]...),
+			Watchers:      append([]string{}, watchers[task.ID]...),
+			LabelIDs:      []uint{},
+			Subtasks:      []ExportedSubtask{},
+			Comments:      []ExportedComment{},
+		})
+	}
+
+	rows, err := DB.Query(`
+		SELECT A.task_id, A.label_id
+		FROM TaskLabel A JOIN Task B
+		ON A.task_id = B.id
+		WHERE B.workspace_id = $1;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var task_id, label_id uint
+
+		if err := rows.Scan(&task_id, &label_id); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		if i, ok := task_indexes[task_id]; ok {
+			export.Tasks[i].LabelIDs = append(export.Tasks[i].LabelIDs, label_id)
+		}
+	}
+
+	subtask_assignees, err := queryNames(`
+		SELECT A.subtask_id, B.username
+		FROM SubtaskAssignee A JOIN Users B ON A.user_id = B.id
+		JOIN Subtask C ON A.subtask_id = C.id
+		JOIN Task D ON C.task_id = D.id
+		WHERE D.workspace_id = $1;`,
+		workspace_id)
+	if err != nil {
+		return err
+	}
+
+	rows, err = DB.Query(`
+		SELECT A.id, A.task_id, A.title, A.is_completed
+		FROM Subtask A JOIN Task B
+		ON A.task_id = B.id
+		WHERE B.workspace_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $2 AND item_id = A.id
+		)
+		ORDER BY A.id;`,
+		workspace_id, SubtaskItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var subtask ExportedSubtask
+		var task_id uint
+		var is_completed string
+
+		if err := rows.Scan(&subtask.ID, &task_id, &subtask.Title, &is_completed); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		subtask.IsCompleted = is_completed == Yes
+		subtask.Assignees = append([]string{}, subtask_assignees[subtask.ID]...)
+
+		if i, ok := task_indexes[task_id]; ok {
+			export.Tasks[i].Subtasks = append(export.Tasks[i].Subtasks, subtask)
+		}
+	}
+
+	rows, err = DB.Query(`
+		SELECT A.id, A.task_id, B.username, COALESCE(A.text, '')
+		FROM Comment A JOIN Users B ON A.user_id = B.id
+		JOIN Task C ON A.task_id = C.id
+		WHERE C.workspace_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $2 AND item_id = A.id
+		)
+		ORDER BY A.id;`,
+		workspace_id, CommentItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var comment ExportedComment
+		var task_id uint
+
+		if err := rows.Scan(&comment.ID, &task_id, &comment.Author, &comment.Text); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		if i, ok := task_indexes[task_id]; ok {
+			export.Tasks[i].Comments = append(export.Tasks[i].Comments, comment)
+		}
+	}
+
+	rows, err = DB.Query(`
+		SELECT A.task_id, A.target_task_id, A.type
+		FROM TaskDependency A JOIN Task B
+		ON A.task_id = B.id
+		WHERE B.workspace_id = $1
+		ORDER BY A.id;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var dependency ExportedDependency
+
+		if err := rows.Scan(&dependency.TaskID, &dependency.TargetTaskID, &dependency.Type); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		_, task_ok := task_indexes[dependency.TaskID]
+		_, target_ok := task_indexes[dependency.TargetTaskID]
+		if task_ok && target_ok {
+			export.Dependencies = append(export.Dependencies, dependency)
+		}
+	}
+
+	return nil
+}
+
+// ExportWorkspace gathers the workspace with everything in it that is not in
+// the trash, archived tasks included.
+func ExportWorkspace(requester_user_id uint, workspace_id uint) (WorkspaceExport, error) {
+	tasks, err := GetAllTasksInWorkspace(requester_user_id, workspace_id, []uint{}, IncludeArchived, "")
+	if err != nil {
+		return WorkspaceExport{}, err
+	}
+
+	export := WorkspaceExport{
+		Version:      WorkspaceExportVersion,
+		ExportedAt:   time.Now(),
+		Roles:        []ExportedRole{},
+		Members:      []ExportedMember{},
+		Labels:       []ExportedLabel{},
+		Tasks:        []ExportedTask{},
+		Dependencies: []ExportedDependency{},
+	}
+
+	if err := exportWorkspaceDetails(&export, workspace_id); err != nil {
+		return WorkspaceExport{}, err
+	}
+
+	if err := exportTasks(&export, workspace_id, tasks); err != nil {
+		return WorkspaceExport{}, err
+	}
+
+	return export, nil
+}
+
+// GetTaskReport lists the tasks of the workspace the way GetAllTasksInWorkspace
+// filters them, with the usernames of their assignees and their label names.
+func GetTaskReport(requester_user_id uint, workspace_id uint, label_ids []uint, archived string, search string) ([]TaskReportRow, error) {
+	tasks, err := GetAllTasksInWorkspace(requester_user_id, workspace_id, label_ids, archived, search)
+	if err != nil {
+		return []TaskReportRow{}, err
+	}
+
+	assignees, err := queryNames(`
+		SELECT A.task_id, B.username
+		FROM TaskAssignee A JOIN Users B ON A.user_id = B.id
+		JOIN Task C ON A.task_id = C.id
+		WHERE C.workspace_id = $1
+		ORDER BY B.username;`,
+		workspace_id)
+	if err != nil {
+		return []TaskReportRow{}, err
+	}
+
+	labels, err := queryNames(`
+		SELECT A.task_id, B.name
+		FROM TaskLabel A JOIN Label B ON A.label_id = B.id
+		WHERE B.workspace_id = $1
+		ORDER BY B.name;`,
+		workspace_id)
+	if err != nil {
+		return []TaskReportRow{}, err
+	}
+
+	rows := []TaskReportRow{}
+	for _, task := range tasks {
+		rows = append(rows, TaskReportRow{
+			Task:      task,
+			Assignees: append([]string{}, assignees[task.ID]...),
+			Labels:    append([]string{}, labels[task.ID]...),
+		})
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strconv"
+	"testing"
+)
+
+func TestExportImportKeepsPicture(t *testing.T) {
+	requireDatabase(t)
+
+	user := createTestUser(t)
+	workspace := createTestWorkspace(t, user.ID)
+	task := createTestTask(t, user.ID, workspace.ID)
+
+	picture := []byte("picture data")
+	if err := AddPictureToRedis(strconv.Itoa(int(task.ID)), base64.StdEncoding.EncodeToString(picture)); err != nil {
+		t.Fatalf("uploading picture: %v", err)
+	}
+	t.Cleanup(func() { DeleteKeys(strconv.Itoa(int(task.ID))) })
+
+	export, err := ExportWorkspace(user.ID, workspace.ID)
+	if err != nil {
+		t.Fatalf("exporting workspace: %v", err)
+	}
+
+	result, err := ImportWorkspace(user.ID, export)
+	if err != nil {
+		t.Fatalf("importing workspace: %v", err)
+	}
+
+	imported_task_id, ok := result.TaskIDs[task.ID]
+	if !ok {
+		t.Fatalf("task %d was not imported", task.ID)
+	}
+	t.Cleanup(func() { DeleteKeys(strconv.Itoa(int(imported_task_id))) })
+
+	imported, err := RetrieveFile(strconv.Itoa(int(imported_task_id)))
+	if err != nil {
+		t.Fatalf("retrieving picture of the imported task: %v", err)
+	} else if !bytes.Equal(imported, picture) {
+		t.Errorf("imported task has picture %q, want %q", imported, picture)
+	}
+}
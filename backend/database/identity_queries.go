@@ -0,0 +1,213 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+var usernameCleaner = regexp.MustCompile("[^A-Za-z0-9]")
+
+// deriveUsername turns an identity provider claim into a username that
+// satisfies the signup rules (4 to 12 alphanumeric characters) and is not
+// already taken.
+func deriveUsername(hint string) (string, error) {
+	base := usernameCleaner.ReplaceAllString(hint, "")
+	if len(base) > 12 {
+		base = base[:12]
+	}
+	if len(base) < 4 {
+		base = "user" + base
+	}
+
+	if checkDuplicateUsername(base) {
+		return base, nil
+	}
+
+	if len(base) > 8 {
+		base = base[:8]
+	}
+	for i := 0; i < 10; i++ {
+		username := fmt.Sprintf("%s%04d", base, rand.Intn(10000))
+		if checkDuplicateUsername(username) {
+			return username, nil
+		}
+	}
+
+	return "", custom_errors.ErrDuplicateUsername
+}
+
+func getUserByIdentity(issuer string, subject string) (User, error) {
+	var user User
+	err := DB.QueryRow(`
+		SELECT Users.*
+		FROM Users
+		JOIN UserIdentity ON UserIdentity.user_id = Users.id
+		WHERE UserIdentity.issuer = $1 AND UserIdentity.subject = $2;`,
+		issuer, subject).
+		Scan(&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.PasswordHash,
+			&user.CreatedAt,
+			&user.UpdatedAt)
+
+	return user, err
+}
+
+func linkUserIdentity(user_id uint, issuer string, subject string) error {
+	_, err := DB.Exec(`
+		INSERT INTO
+		UserIdentity(user_id, issuer, subject, created_at)
+		VALUES($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING;`,
+		user_id, issuer, subject, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+// clearAccountCredentials removes every way of signing in to the account
+// other than its identity provider links: the password, two-factor
+// authentication, sessions and personal access tokens.
+func clearAccountCredentials(user_id uint) error {
+	_, err := DB.Exec(`
+		UPDATE Users
+		SET password_hash = NULL, updated_at = $1
+		WHERE id = $2;`,
+		time.Now(), user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM TwoFactor
+		WHERE user_id = $1;`,
+		user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return RevokeSessions(user_id)
+}
+
+// ResolveOIDCUser returns the user behind an identity provider subject.
+// Unknown subjects are linked to an existing account with the same verified
+// email, or provisioned as a new password-less user when allowed. Accounts
+// that never confirmed that email lose their other credentials when linked.
+func ResolveOIDCUser(issuer string, subject string, email string, email_verified bool, username_hint string, auto_provision bool) (User, error) {
+	user, err := getUserByIdentity(issuer, subject)
+	if err == nil {
+		return user, nil
+	} else if err != sql.ErrNoRows {
+		log.Println("Error:", err)
+		return User{}, custom_errors.ErrDatabaseFailure
+	}
+
+	if email == "" || len(email) > 30 {
+		return User{}, custom_errors.ErrInvalidArguments
+	}
+
+	err = DB.QueryRow(`
+		SELECT * FROM Users
+		WHERE LOWER(email) = LOWER($1);`,
+		email).
+		Scan(&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.PasswordHash,
+			&user.CreatedAt,
+			&user.UpdatedAt)
+
+	if err == nil {
+		if !email_verified {
+			return User{}, custom_errors.ErrDuplicateEmail
+		}
+
+		verified, err := isEmailVerified(user.ID)
+		if err != nil {
+			return User{}, err
+		}
+
+		// Anyone can sign up with an address they do not own, so an
+		// unverified account is handed over to the identity provider's
+		// verified owner without the credentials it was created with.
+		if !verified {
+			if err := clearAccountCredentials(user.ID); err != nil {
+				return User{}, err
+			}
+			user.PasswordHash = nil
+
+			if err := setEmailVerified(user.ID, true); err != nil {
+				return User{}, err
+			}
+		}
+
+		if err := linkUserIdentity(user.ID, issuer, subject); err != nil {
+			return User{}, err
+		}
+		return user, nil
+	} else if err != sql.ErrNoRows {
+		log.Println("Error:", err)
+		return User{}, custom_errors.ErrDatabaseFailure
+	}
+
+	if !auto_provision {
+		return User{}, custom_errors.ErrAccessDenied
+	}
+
+	username, err := deriveUsername(username_hint)
+	if err != nil {
+		return User{}, err
+	}
+
+	err = DB.QueryRow(`
+		INSERT INTO
+		Users(username, email, created_at, updated_at)
+		VALUES($1, $2, $3, $4)
+		RETURNING *;`,
+		username, email, time.Now(), time.Now()).
+		Scan(&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.PasswordHash,
+			&user.CreatedAt,
+			&user.UpdatedAt)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return User{}, custom_errors.ErrDatabaseFailure
+	}
+
+	if err := linkUserIdentity(user.ID, issuer, subject); err != nil {
+		return User{}, err
+	}
+
+	if err := claimEmailInvitations(user); err != nil {
+		return User{}, err
+	}
+
+	if email_verified {
+		err = setEmailVerified(user.ID, true)
+	} else {
+		err = startEmailVerification(user)
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,224 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+// importJobPayload is what an import job stores until it runs: the document
+// to import and the notes taken while converting it from its source format.
+type importJobPayload struct {
+	Export WorkspaceExport `json:"export"`
+	Notes  []string        `json:"notes"`
+}
+
+const importJobColumns = `
+		id, user_id, source, status, dry_run, total, processed, result, last_error,
+		created_at, updated_at, finished_at`
+
+func scanImportJob(row rowScanner) (ImportJob, error) {
+	var import_job ImportJob
+	var result string
+	var finished_at sql.NullTime
+
+	err := row.Scan(
+		&import_job.ID,
+		&import_job.UserID,
+		&import_job.Source,
+		&import_job.Status,
+		&import_job.DryRun,
+		&import_job.Total,
+		&import_job.Processed,
+		&result,
+		&import_job.LastError,
+		&import_job.CreatedAt,
+		&import_job.UpdatedAt,
+		&finished_at)
+
+	if err == nil && result != "" {
+		import_job.Result = &WorkspaceImport{}
+		err = json.Unmarshal([]byte(result), import_job.Result)
+	}
+	import_job.FinishedAt = finished_at.Time
+
+	return import_job, err
+}
+
+// CreateImportJob queues the document to be imported in the background.
+func CreateImportJob(requester_user_id uint, source string, export WorkspaceExport, notes []string, dry_run bool) (ImportJob, error) {
+	if export.Version != WorkspaceExportVersion {
+		return ImportJob{}, custom_errors.ErrInvalidArguments
+	}
+
+	payload, err := json.Marshal(importJobPayload{Export: export, Notes: notes})
+	if err != nil {
+		log.Println("Error:", err)
+		return ImportJob{}, custom_errors.ErrInvalidArguments
+	}
+
+	import_job, err := scanImportJob(DB.QueryRow(`
+		INSERT INTO
+		ImportJob(user_id, source, status, dry_run, payload, total, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $7)
+		RETURNING`+importJobColumns+`;`,
+		requester_user_id, source, PendingImport, dry_run, string(payload), len(export.Tasks), time.Now()))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return ImportJob{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return import_job, nil
+}
+
+func GetImportJobs(requester_user_id uint) ([]ImportJob, error) {
+	rows, err := DB.Query(`
+		SELECT`+importJobColumns+`
+		FROM ImportJob
+		WHERE user_id = $1
+		ORDER BY id DESC;`,
+		requester_user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []ImportJob{}, custom_errors.ErrDatabaseFailure
+	}
+
+	import_jobs := []ImportJob{}
+
+	for rows.Next() {
+		if import_job, err := scanImportJob(rows); err != nil {
+			log.Println("Error:", err)
+			return []ImportJob{}, custom_errors.ErrDatabaseFailure
+		} else {
+			import_jobs = append(import_jobs, import_job)
+		}
+	}
+
+	return import_jobs, nil
+}
+
+func GetImportJob(requester_user_id uint, import_job_id uint) (ImportJob, error) {
+	import_job, err := scanImportJob(DB.QueryRow(`
+		SELECT`+importJobColumns+`
+		FROM ImportJob
+		WHERE id = $1 AND user_id = $2;`,
+		import_job_id, requester_user_id))
+
+	if err == sql.ErrNoRows {
+		return ImportJob{}, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return ImportJob{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return import_job, nil
+}
+
+// ClaimImportJob takes the oldest pending job, or a running one whose worker
+// has not reported progress within the lease, and marks it as running.
+func ClaimImportJob(now time.Time, lease time.Duration) (ImportJob, bool, error) {
+	import_job, err := scanImportJob(DB.QueryRow(`
+		UPDATE ImportJob
+		SET status = $1, processed = 0, updated_at = $2
+		WHERE id = (
+			SELECT id
+			FROM ImportJob
+			WHERE status = $3 OR (status = $1 AND updated_at < $4)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING`+importJobColumns+`;`,
+		RunningImport, now, PendingImport, now.Add(-lease)))
+
+	if err == sql.ErrNoRows {
+		return ImportJob{}, false, nil
+	} else if err != nil {
+		log.Println("Error:", err)
+		return ImportJob{}, false, custom_errors.ErrDatabaseFailure
+	}
+
+	return import_job, true, nil
+}
+
+func updateImportJobProgress(import_job_id uint, processed int) error {
+	_, err := DB.Exec(`
+		UPDATE ImportJob
+		SET processed = $1, updated_at = $2
+		WHERE id = $3;`,
+		processed, time.Now(), import_job_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func finishImportJob(import_job_id uint, status string, result string, last_error string) error {
+	_, err := DB.Exec(`
+		UPDATE ImportJob
+		SET status = $1, result = $2, last_error = $3, updated_at = $4, finished_at = $4, payload = ''
+		WHERE id = $5;`,
+		status, result, last_error, time.Now(), import_job_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+// RunImportJob imports the document of a claimed job, keeping its progress up
+// to date, and stores the outcome on the job.
+func RunImportJob(import_job ImportJob) (WorkspaceImport, error) {
+	var payload_text string
+
+	err := DB.QueryRow(`
+		SELECT payload
+		FROM ImportJob
+		WHERE id = $1;`,
+		import_job.ID).
+		Scan(&payload_text)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return WorkspaceImport{}, custom_errors.ErrDatabaseFailure
+	}
+
+	var payload importJobPayload
+	if err := json.Unmarshal([]byte(payload_text), &payload); err != nil {
+		log.Println("Error:", err)
+		finishImportJob(import_job.ID, FailedImport, "", custom_errors.ErrInvalidArguments.Error())
+		return WorkspaceImport{}, custom_errors.ErrInvalidArguments
+	}
+
+	// Trello usernames name accounts on another system, so Trello members
+	// are only matched by their email.
+	match_usernames := import_job.Source != TrelloImportSource
+
+	result, err := importWorkspaceExport(import_job.UserID, payload.Export, match_usernames, import_job.DryRun, func(processed int) {
+		updateImportJobProgress(import_job.ID, processed)
+	})
+	if err != nil {
+		finishImportJob(import_job.ID, FailedImport, "", err.Error())
+		return WorkspaceImport{}, err
+	}
+
+	result.Conflicts = append(payload.Notes, result.Conflicts...)
+
+	result_text, err := json.Marshal(result)
+	if err != nil {
+		log.Println("Error:", err)
+		return WorkspaceImport{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return result, finishImportJob(import_job.ID, CompletedImport, string(result_text), "")
+}
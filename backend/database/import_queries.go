@@ -0,0 +1,665 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	regex_utils "github.com/skye-tan/trello/backend/utils/regex"
+)
+
+const defaultImportedWorkspaceName = "Imported workspace"
+
+// workspaceImporter recreates an exported workspace inside a transaction. The
+// documents come from outside, so every entry is checked again and whatever
+// cannot be recreated as it was is adjusted or skipped and reported.
+type workspaceImporter struct {
+	tx                *sql.Tx
+	requester_user_id uint
+	user_ids          map[string]uint
+	member_ids        map[uint]bool
+	invitees          []importInvitee
+	pictures          map[uint]string
+	result            WorkspaceImport
+}
+
+// importInvitee is a member of the document that matched a local account. The
+// account is only invited once the workspace exists, never made a member.
+type importInvitee struct {
+	username string
+	user_id  uint
+	role     string
+}
+
+func (importer *workspaceImporter) conflict(format string, args ...any) {
+	importer.result.Conflicts = append(importer.result.Conflicts, fmt.Sprintf(format, args...))
+}
+
+// resolveUsernames looks up every user the document refers to. Members are
+// matched by a verified email first, as usernames from other systems may
+// belong to someone else here, and by username only when match_usernames is
+// set.
+func resolveUsernames(export WorkspaceExport, match_usernames bool) (map[string]uint, error) {
+	usernames := []string{}
+	emails := []string{}
+	for _, member := range export.Members {
+		usernames = append(usernames, member.Username)
+		if member.Email != "" {
+			emails = append(emails, strings.ToLower(member.Email))
+		}
+	}
+	for _, task := range export.Tasks {
+		usernames = append(usernames, task.Assignees...)
+		usernames = append(usernames, task.Watchers...)
+		for _, subtask := range task.Subtasks {
+			usernames = append(usernames, subtask.Assignees...)
+		}
+		for _, comment := range task.Comments {
+			usernames = append(usernames, comment.Author)
+		}
+	}
+
+	if !match_usernames {
+		usernames = []string{}
+	}
+
+	rows, err := DB.Query(`
+		SELECT id, username, COALESCE(LOWER(email), ''), NOT EXISTS (
+			SELECT 1
+			FROM EmailVerification
+			WHERE user_id = Users.id AND NOT is_verified
+		)
+		FROM Users
+		WHERE username = ANY($1) OR LOWER(email) = ANY($2);`,
+		pq.Array(usernames), pq.Array(emails))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return map[string]uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	user_ids := make(map[string]uint)
+	email_user_ids := make(map[string]uint)
+
+	for rows.Next() {
+		var user_id uint
+		var username, email string
+		var verified bool
+
+		if err := rows.Scan(&user_id, &username, &email, &verified); err != nil {
+			log.Println("Error:", err)
+			return map[string]uint{}, custom_errors.ErrDatabaseFailure
+		}
+
+		if match_usernames {
+			user_ids[username] = user_id
+		}
+		if verified && email != "" {
+			email_user_ids[email] = user_id
+		}
+	}
+
+	for _, member := range export.Members {
+		if user_id, ok := email_user_ids[strings.ToLower(member.Email)]; ok && member.Email != "" {
+			user_ids[member.Username] = user_id
+		}
+	}
+
+	return user_ids, nil
+}
+
+// memberIDs maps the usernames to members of the new workspace, reporting
+// the ones that are dropped on the way.
+func (importer *workspaceImporter) memberIDs(usernames []string, owner string) []uint {
+	user_ids := []uint{}
+	seen := make(map[uint]bool)
+
+	for _, username := range usernames {
+		user_id, ok := importer.user_ids[username]
+		if !ok || !importer.member_ids[user_id] {
+			importer.conflict("%s: %q is not a member of the workspace and was dropped", owner, username)
+			continue
+		}
+
+		if !seen[user_id] {
+			seen[user_id] = true
+			user_ids = append(user_ids, user_id)
+		}
+	}
+
+	return user_ids
+}
+
+func (importer *workspaceImporter) importWorkspace(workspace ExportedWorkspace) error {
+	name := strings.TrimSpace(workspace.Name)
+	if name == "" {
+		name = defaultImportedWorkspaceName
+	}
+
+	unique_name, err := uniqueName(name, 30, func(candidate string) bool {
+		return !checkDuplicateWorkspaceName(candidate)
+	})
+	if err != nil {
+		return custom_errors.ErrDuplicateWorspaceName
+	} else if unique_name != workspace.Name {
+		importer.conflict("workspace %q was renamed to %q", workspace.Name, unique_name)
+	}
+
+	err = importer.tx.QueryRow(`
+		INSERT INTO
+		Workspace(name, description, created_at, updated_at)
+		VALUES($1, $2, $3, $4)
+		RETURNING *;`,
+		unique_name, workspace.Description, time.Now(), time.Now()).
+		Scan(&importer.result.Workspace.ID,
+			&importer.result.Workspace.Name,
+			&importer.result.Workspace.Description,
+			&importer.result.Workspace.CreatedAt,
+			&importer.result.Workspace.UpdatedAt)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = importer.tx.Exec(`
+		INSERT INTO
+		UserWorkspaceRole(user_id, workspace_id, role, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5);`,
+		importer.requester_user_id, importer.result.Workspace.ID, Owner,
+		time.Now(), time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	importer.member_ids[importer.requester_user_id] = true
+
+	return nil
+}
+
+// importMembers adds the custom roles and picks the members to invite with
+// them. The requester owns the new workspace, so other owners are invited as
+// admins.
+func (importer *workspaceImporter) importMembers(roles []ExportedRole, members []ExportedMember) error {
+	workspace_id := importer.result.Workspace.ID
+	role_names := make(map[string]string)
+
+	for _, role := range roles {
+		permissions, ok := normalizePermissions(role.Permissions)
+		name := strings.TrimSpace(role.Name)
+
+		if !ok || name == "" || len([]rune(name)) > 30 || isBuiltinRole(name) {
+			importer.conflict("role %q is invalid and was skipped", role.Name)
+			continue
+		} else if _, ok := role_names[strings.ToLower(name)]; ok {
+			importer.conflict("role %q is a duplicate and was skipped", role.Name)
+			continue
+		}
+
+		_, err := importer.tx.Exec(`
+			INSERT INTO
+			CustomRole(workspace_id, name, permissions, created_by, created_at, updated_at)
+			VALUES($1, $2, $3, $4, $5, $6);`,
+			workspace_id, name, pq.Array(permissions), importer.requester_user_id,
+			time.Now(), time.Now())
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		role_names[strings.ToLower(name)] = name
+	}
+
+	for _, member := range members {
+		user_id, matched := importer.user_ids[member.Username]
+		if matched && importer.member_ids[user_id] {
+			continue
+		}
+
+		role := member.Role
+		if role == Owner {
+			role = Admin
+		} else if custom_role, ok := role_names[strings.ToLower(role)]; ok {
+			role = custom_role
+		} else if _, ok := BuiltinRoles[role]; !ok {
+			role = StandardUser
+		}
+
+		if !matched {
+			importer.conflict("member %q could not be invited", member.Username)
+			continue
+		}
+
+		importer.invitees = append(importer.invitees, importInvitee{
+			username: member.Username,
+			user_id:  user_id,
+			role:     role,
+		})
+	}
+
+	return nil
+}
+
+// inviteMembers invites the matched members once the workspace is committed,
+// or, on a dry run, checks whether they could be.
+func (importer *workspaceImporter) inviteMembers(dry_run bool) {
+	for _, invitee := range importer.invitees {
+		var err error
+		if dry_run {
+			err = checkInviteeEligibility(invitee.user_id, importer.result.Workspace.ID)
+		} else {
+			_, err = AddUserWorkspaceRole(importer.requester_user_id, invitee.user_id, importer.result.Workspace.ID, invitee.role)
+		}
+
+		if err != nil {
+			importer.conflict("member %q could not be invited", invitee.username)
+		} else {
+			importer.conflict("member %q was invited as %s instead of being added", invitee.username, invitee.role)
+		}
+	}
+}
+
+// storePictures writes the pictures of the imported tasks to Redis, which
+// only happens once the tasks they belong to are committed.
+func (importer *workspaceImporter) storePictures() {
+	for task_id, picture := range importer.pictures {
+		if err := AddPictureToRedis(strconv.Itoa(int(task_id)), picture); err != nil {
+			log.Println("Error:", err)
+			importer.conflict("picture of task %d could not be stored", task_id)
+		}
+	}
+}
+
+func (importer *workspaceImporter) importLabels(labels []ExportedLabel) error {
+	label_names := make(map[string]bool)
+
+	for _, label := range labels {
+		name := strings.TrimSpace(label.Name)
+
+		if name == "" || len([]rune(name)) > 30 || !regex_utils.ValidateColor(label.Color) {
+			importer.conflict("label %q is invalid and was skipped", label.Name)
+			continue
+		} else if label_names[name] {
+			importer.conflict("label %q is a duplicate and was skipped", label.Name)
+			continue
+		}
+
+		var label_id uint
+
+		err := importer.tx.QueryRow(`
+			INSERT INTO
+			Label(workspace_id, name, color, created_at, updated_at)
+			VALUES($1, $2, $3, $4, $5)
+			RETURNING id;`,
+			importer.result.Workspace.ID, name, label.Color, time.Now(), time.Now()).
+			Scan(&label_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		label_names[name] = true
+		importer.result.LabelIDs[label.ID] = label_id
+	}
+
+	return nil
+}
+
+func (importer *workspaceImporter) importTask(task ExportedTask, reserved_titles map[string]bool) error {
+	owner := fmt.Sprintf("task %q", task.Title)
+
+	title := strings.TrimSpace(task.Title)
+	if title == "" {
+		title = "Untitled task"
+	}
+
+	title, err := uniqueName(title, maxTaskTitleLength, func(candidate string) bool {
+		return reserved_titles[candidate]
+	})
+	if err != nil {
+		importer.conflict("%s could not be given a unique title and was skipped", owner)
+		return nil
+	} else if title != task.Title {
+		importer.conflict("%s was renamed to %q", owner, title)
+	}
+	reserved_titles[title] = true
+
+	status := task.Status
+	if status != Planned && status != InProgress && status != Completed {
+		status = Planned
+		importer.conflict("%s had unknown status %q and was set to %s", owner, task.Status, Planned)
+	}
+
+	image_url := task.ImageURL
+	if len(image_url) > 100 {
+		image_url = ""
+		importer.conflict("%s had an image url that is too long and it was dropped", owner)
+	}
+
+	picture := task.Picture
+	if _, err := base64.StdEncoding.DecodeString(picture); err != nil {
+		picture = ""
+		importer.conflict("%s had a picture that is not valid base64 and it was dropped", owner)
+	}
+
+	assignee_ids := importer.memberIDs(task.Assignees, owner)
+	assignee_id := uint(0)
+	if len(assignee_ids) != 0 {
+		assignee_id = assignee_ids[0]
+	}
+
+	var task_id uint
+
+	err = importer.tx.QueryRow(`
+		INSERT INTO
+		Task(title, description, status, estimated_time, actual_time, due_date, priority, workspace_id, assignee_id, created_at, updated_at, image_url)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10, $11)
+		RETURNING id;`,
+		title, task.Description, status, task.EstimatedTime, task.ActualTime, task.DueDate,
+		task.Priority, importer.result.Workspace.ID, nullableID(assignee_id), time.Now(), image_url).
+		Scan(&task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	importer.result.TaskIDs[task.ID] = task_id
+	if picture != "" {
+		importer.pictures[task_id] = picture
+	}
+
+	for _, user_id := range assignee_ids {
+		_, err := importer.tx.Exec(`
+			INSERT INTO
+			TaskAssignee(task_id, user_id)
+			VALUES($1, $2);`,
+			task_id, user_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	for _, user_id := range importer.memberIDs(task.Watchers, owner) {
+		_, err := importer.tx.Exec(`
+			INSERT INTO
+			Watch(task_id, user_id)
+			VALUES($1, $2);`,
+			task_id, user_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	for _, exported_label_id := range task.LabelIDs {
+		label_id, ok := importer.result.LabelIDs[exported_label_id]
+		if !ok {
+			importer.conflict("%s refers to unknown label %d and it was dropped", owner, exported_label_id)
+			continue
+		}
+
+		_, err := importer.tx.Exec(`
+			INSERT INTO
+			TaskLabel(task_id, label_id)
+			VALUES($1, $2)
+			ON CONFLICT DO NOTHING;`,
+			task_id, label_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	if task.IsArchived {
+		_, err := importer.tx.Exec(`
+			INSERT INTO
+			TaskArchive(task_id, archived_by, archived_at)
+			VALUES($1, $2, $3);`,
+			task_id, importer.requester_user_id, time.Now())
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	if err := importer.importSubtasks(task_id, task.Subtasks, owner); err != nil {
+		return err
+	}
+
+	return importer.importComments(task_id, task.Comments)
+}
+
+func (importer *workspaceImporter) importSubtasks(task_id uint, subtasks []ExportedSubtask, owner string) error {
+	reserved_titles := make(map[string]bool)
+
+	for _, subtask := range subtasks {
+		title := strings.TrimSpace(subtask.Title)
+		if title == "" {
+			title = "Untitled subtask"
+		}
+
+		title, err := uniqueName(title, 30, func(candidate string) bool {
+			return reserved_titles[candidate]
+		})
+		if err != nil {
+			importer.conflict("%s: subtask %q could not be given a unique title and was skipped", owner, subtask.Title)
+			continue
+		} else if title != subtask.Title {
+			importer.conflict("%s: subtask %q was renamed to %q", owner, subtask.Title, title)
+		}
+		reserved_titles[title] = true
+
+		is_completed := No
+		if subtask.IsCompleted {
+			is_completed = Yes
+		}
+
+		assignee_ids := importer.memberIDs(subtask.Assignees, fmt.Sprintf("%s: subtask %q", owner, subtask.Title))
+		assignee_id := uint(0)
+		if len(assignee_ids) != 0 {
+			assignee_id = assignee_ids[0]
+		}
+
+		var subtask_id uint
+
+		err = importer.tx.QueryRow(`
+			INSERT INTO
+			Subtask(task_id, title, is_completed, assignee_id, created_at, updated_at)
+			VALUES($1, $2, $3, $4, $5, $5)
+			RETURNING id;`,
+			task_id, title, is_completed, nullableID(assignee_id), time.Now()).
+			Scan(&subtask_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		importer.result.SubtaskIDs[subtask.ID] = subtask_id
+
+		for _, user_id := range assignee_ids {
+			_, err := importer.tx.Exec(`
+				INSERT INTO
+				SubtaskAssignee(subtask_id, user_id)
+				VALUES($1, $2);`,
+				subtask_id, user_id)
+
+			if err != nil {
+				log.Println("Error:", err)
+				return custom_errors.ErrDatabaseFailure
+			}
+		}
+	}
+
+	return nil
+}
+
+// importComments attributes every comment to the requester, as nobody else
+// wrote them here. The original author is kept in the text.
+func (importer *workspaceImporter) importComments(task_id uint, comments []ExportedComment) error {
+	for _, comment := range comments {
+		text := comment.Text
+		if user_id, ok := importer.user_ids[comment.Author]; !ok || user_id != importer.requester_user_id {
+			text = fmt.Sprintf("Originally by @%s: %s", comment.Author, comment.Text)
+		}
+
+		var comment_id uint
+
+		err := importer.tx.QueryRow(`
+			INSERT INTO
+			Comment(task_id, user_id, text)
+			VALUES($1, $2, $3)
+			RETURNING id;`,
+			task_id, importer.requester_user_id, text).
+			Scan(&comment_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		importer.result.CommentIDs[comment.ID] = comment_id
+	}
+
+	return nil
+}
+
+func (importer *workspaceImporter) importDependencies(dependencies []ExportedDependency) error {
+	for _, dependency := range dependencies {
+		task_id, task_ok := importer.result.TaskIDs[dependency.TaskID]
+		target_task_id, target_ok := importer.result.TaskIDs[dependency.TargetTaskID]
+
+		if !task_ok || !target_ok || task_id == target_task_id {
+			importer.conflict("dependency between tasks %d and %d refers to unknown tasks and was skipped", dependency.TaskID, dependency.TargetTaskID)
+			continue
+		}
+
+		switch dependency.Type {
+		case Blocks, BlockedBy, RelatesTo, Duplicates:
+		default:
+			importer.conflict("dependency between tasks %d and %d has unknown type %q and was skipped", dependency.TaskID, dependency.TargetTaskID, dependency.Type)
+			continue
+		}
+
+		_, err := importer.tx.Exec(`
+			INSERT INTO
+			TaskDependency(task_id, target_task_id, type, created_at)
+			VALUES($1, $2, $3, $4)
+			ON CONFLICT DO NOTHING;`,
+			task_id, target_task_id, dependency.Type, time.Now())
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	return nil
+}
+
+// importWorkspaceExport runs the whole import in one transaction. A dry run
+// goes through every step and reports the outcome, then rolls it all back.
+// The progress callback, when given, is told how many tasks are done.
+func importWorkspaceExport(requester_user_id uint, export WorkspaceExport, match_usernames bool, dry_run bool, progress func(int)) (WorkspaceImport, error) {
+	if export.Version != WorkspaceExportVersion {
+		return WorkspaceImport{}, custom_errors.ErrInvalidArguments
+	}
+
+	user_ids, err := resolveUsernames(export, match_usernames)
+	if err != nil {
+		return WorkspaceImport{}, err
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		log.Println("Error:", err)
+		return WorkspaceImport{}, custom_errors.ErrDatabaseFailure
+	}
+
+	importer := workspaceImporter{
+		tx:                tx,
+		requester_user_id: requester_user_id,
+		user_ids:          user_ids,
+		member_ids:        make(map[uint]bool),
+		pictures:          make(map[uint]string),
+		result: WorkspaceImport{
+			LabelIDs:   make(map[uint]uint),
+			TaskIDs:    make(map[uint]uint),
+			SubtaskIDs: make(map[uint]uint),
+			CommentIDs: make(map[uint]uint),
+			Conflicts:  []string{},
+			DryRun:     dry_run,
+		},
+	}
+
+	err = importer.importWorkspace(export.Workspace)
+	if err == nil {
+		err = importer.importMembers(export.Roles, export.Members)
+	}
+	if err == nil {
+		err = importer.importLabels(export.Labels)
+	}
+
+	reserved_titles := make(map[string]bool)
+	for i, task := range export.Tasks {
+		if err != nil {
+			break
+		}
+		err = importer.importTask(task, reserved_titles)
+
+		if err == nil && progress != nil {
+			progress(i + 1)
+		}
+	}
+
+	if err == nil {
+		err = importer.importDependencies(export.Dependencies)
+	}
+
+	if err != nil || dry_run {
+		tx.Rollback()
+	}
+	if err != nil {
+		return WorkspaceImport{}, err
+	}
+
+	if dry_run {
+		importer.result.Workspace.ID = 0
+		importer.inviteMembers(dry_run)
+		return importer.result, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error:", err)
+		return WorkspaceImport{}, custom_errors.ErrDatabaseFailure
+	}
+
+	importer.storePictures()
+	importer.inviteMembers(dry_run)
+
+	return importer.result, nil
+}
+
+// ImportWorkspace recreates an exported workspace as a new one owned by the
+// requester. Either the whole document is imported or nothing is, and the
+// result maps the ids of the document to the new ones. Members of the document
+// are only invited, so they join once they accept.
+func ImportWorkspace(requester_user_id uint, export WorkspaceExport) (WorkspaceImport, error) {
+	return importWorkspaceExport(requester_user_id, export, true, false, nil)
+}
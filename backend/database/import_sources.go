@@ -0,0 +1,496 @@
+package database
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+const defaultLabelColor = "#b3bac5"
+
+// Trello names its label colors instead of giving their values.
+var trelloLabelColors = map[string]string{
+	"green":  "#61bd4f",
+	"yellow": "#f2d600",
+	"orange": "#ff9f1a",
+	"red":    "#eb5a46",
+	"purple": "#c377e0",
+	"blue":   "#0079bf",
+	"sky":    "#00c2e0",
+	"lime":   "#51e898",
+	"pink":   "#ff78cb",
+	"black":  "#344563",
+}
+
+// The fields a CSV column can be mapped to.
+const (
+	TitleColumn         = "title"
+	DescriptionColumn   = "description"
+	StatusColumn        = "status"
+	DueDateColumn       = "due_date"
+	PriorityColumn      = "priority"
+	EstimatedTimeColumn = "estimated_time"
+	ActualTimeColumn    = "actual_time"
+	AssigneesColumn     = "assignees"
+	LabelsColumn        = "labels"
+	SubtasksColumn      = "subtasks"
+)
+
+var CSVColumns = []string{
+	TitleColumn,
+	DescriptionColumn,
+	StatusColumn,
+	DueDateColumn,
+	PriorityColumn,
+	EstimatedTimeColumn,
+	ActualTimeColumn,
+	AssigneesColumn,
+	LabelsColumn,
+	SubtasksColumn,
+}
+
+var csvDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006",
+}
+
+type trelloBoard struct {
+	Name        string `json:"name"`
+	Description string `json:"desc"`
+	Lists       []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID           string   `json:"id"`
+		Name         string   `json:"name"`
+		Description  string   `json:"desc"`
+		Closed       bool     `json:"closed"`
+		ListID       string   `json:"idList"`
+		Due          string   `json:"due"`
+		DueComplete  bool     `json:"dueComplete"`
+		MemberIDs    []string `json:"idMembers"`
+		LabelIDs     []string `json:"idLabels"`
+		ChecklistIDs []string `json:"idChecklists"`
+		Attachments  []struct {
+			URL      string `json:"url"`
+			MimeType string `json:"mimeType"`
+		} `json:"attachments"`
+	} `json:"cards"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Checklists []struct {
+		ID         string `json:"id"`
+		CardID     string `json:"idCard"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	} `json:"members"`
+	Memberships []struct {
+		MemberID   string `json:"idMember"`
+		MemberType string `json:"memberType"`
+	} `json:"memberships"`
+	Actions []struct {
+		Type          string `json:"type"`
+		MemberCreator struct {
+			ID string `json:"id"`
+		} `json:"memberCreator"`
+		Data struct {
+			Text string `json:"text"`
+			Card struct {
+				ID string `json:"id"`
+			} `json:"card"`
+		} `json:"data"`
+	} `json:"actions"`
+}
+
+// statusFromName guesses the task status a Trello list or a spreadsheet cell
+// stands for. Anything it does not recognize is planned work.
+func statusFromName(name string) (string, bool) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(name), ""))
+
+	switch {
+	case normalized == strings.ToLower(Planned), normalized == "todo", normalized == "backlog":
+		return Planned, true
+	case normalized == strings.ToLower(InProgress), strings.Contains(normalized, "progress"), strings.Contains(normalized, "doing"):
+		return InProgress, true
+	case normalized == strings.ToLower(Completed), strings.Contains(normalized, "done"), strings.Contains(normalized, "complete"):
+		return Completed, true
+	}
+
+	return Planned, false
+}
+
+func splitCell(cell string) []string {
+	values := []string{}
+	for _, value := range strings.FieldsFunc(cell, func(r rune) bool { return r == ';' || r == ',' }) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func newWorkspaceExport(name string, description string) WorkspaceExport {
+	return WorkspaceExport{
+		Version:      WorkspaceExportVersion,
+		ExportedAt:   time.Now(),
+		Workspace:    ExportedWorkspace{Name: name, Description: description},
+		Roles:        []ExportedRole{},
+		Members:      []ExportedMember{},
+		Labels:       []ExportedLabel{},
+		Tasks:        []ExportedTask{},
+		Dependencies: []ExportedDependency{},
+	}
+}
+
+// ConvertTrelloBoard turns a Trello board export into a workspace export.
+// Lists become statuses, either through list_statuses, which maps list names
+// to statuses, or by guessing from their names. Members are only matched to
+// local accounts by a verified email and are invited rather than added. It
+// also returns notes on what could not be carried over as it was.
+func ConvertTrelloBoard(data []byte, list_statuses map[string]string, name string) (WorkspaceExport, []string, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return WorkspaceExport{}, []string{}, custom_errors.ErrInvalidArguments
+	}
+
+	if name == "" {
+		name = board.Name
+	}
+
+	export := newWorkspaceExport(name, board.Description)
+	notes := []string{}
+
+	admins := make(map[string]bool)
+	for _, membership := range board.Memberships {
+		if membership.MemberType == "admin" {
+			admins[membership.MemberID] = true
+		}
+	}
+
+	usernames := make(map[string]string)
+	for _, member := range board.Members {
+		usernames[member.ID] = member.Username
+
+		role := StandardUser
+		if admins[member.ID] {
+			role = Admin
+		}
+
+		if member.Email == "" {
+			notes = append(notes, fmt.Sprintf("Trello member %q has no email and cannot be matched", member.Username))
+		}
+
+		export.Members = append(export.Members, ExportedMember{
+			Username: member.Username,
+			Email:    member.Email,
+			Role:     role,
+		})
+	}
+
+	label_ids := make(map[string]uint)
+	for i, label := range board.Labels {
+		color, ok := trelloLabelColors[label.Color]
+		if !ok {
+			color = defaultLabelColor
+		}
+
+		label_name := label.Name
+		if label_name == "" {
+			label_name = label.Color
+		}
+		if label_name == "" {
+			label_name = fmt.Sprintf("Label %d", i+1)
+		}
+
+		label_ids[label.ID] = uint(i + 1)
+		export.Labels = append(export.Labels, ExportedLabel{ID: uint(i + 1), Name: label_name, Color: color})
+	}
+
+	statuses := make(map[string]string)
+	closed_lists := make(map[string]bool)
+	for _, list := range board.Lists {
+		status, ok := list_statuses[list.Name]
+		if ok && status != Planned && status != InProgress && status != Completed {
+			return WorkspaceExport{}, []string{}, custom_errors.ErrInvalidArguments
+		} else if !ok {
+			status, ok = statusFromName(list.Name)
+			if !ok {
+				notes = append(notes, fmt.Sprintf("list %q was taken as %s", list.Name, status))
+			}
+		}
+
+		statuses[list.ID] = status
+		closed_lists[list.ID] = list.Closed
+	}
+
+	task_ids := make(map[string]int)
+	for i, card := range board.Cards {
+		task := ExportedTask{
+			ID:          uint(i + 1),
+			Title:       card.Name,
+			Description: card.Description,
+			Status:      statuses[card.ListID],
+			IsArchived:  card.Closed || closed_lists[card.ListID],
+			Assignees:   []string{},
+			Watchers:    []string{},
+			LabelIDs:    []uint{},
+			Subtasks:    []ExportedSubtask{},
+			Comments:    []ExportedComment{},
+		}
+
+		if task.Status == "" {
+			task.Status = Planned
+		}
+		if card.DueComplete {
+			task.Status = Completed
+		}
+
+		if card.Due != "" {
+			if due_date, err := time.Parse(time.RFC3339, card.Due); err == nil {
+				task.DueDate = due_date
+			} else {
+				notes = append(notes, fmt.Sprintf("card %q has an invalid due date and it was dropped", card.Name))
+			}
+		}
+
+		for _, member_id := range card.MemberIDs {
+			if username, ok := usernames[member_id]; ok {
+				task.Assignees = append(task.Assignees, username)
+			}
+		}
+
+		for _, label_id := range card.LabelIDs {
+			if id, ok := label_ids[label_id]; ok {
+				task.LabelIDs = append(task.LabelIDs, id)
+			}
+		}
+
+		for _, attachment := range card.Attachments {
+			if strings.HasPrefix(attachment.MimeType, "image/") {
+				task.ImageURL = attachment.URL
+				break
+			}
+		}
+
+		task_ids[card.ID] = len(export.Tasks)
+		export.Tasks = append(export.Tasks, task)
+	}
+
+	subtask_id := uint(0)
+	for _, checklist := range board.Checklists {
+		i, ok := task_ids[checklist.CardID]
+		if !ok {
+			continue
+		}
+
+		check_items := checklist.CheckItems
+		sort.SliceStable(check_items, func(a, b int) bool { return check_items[a].Pos < check_items[b].Pos })
+
+		for _, check_item := range check_items {
+			subtask_id++
+			export.Tasks[i].Subtasks = append(export.Tasks[i].Subtasks, ExportedSubtask{
+				ID:          subtask_id,
+				Title:       check_item.Name,
+				IsCompleted: check_item.State == "complete",
+				Assignees:   []string{},
+			})
+		}
+	}
+
+	// Trello lists actions newest first.
+	comment_id := uint(0)
+	for j := len(board.Actions) - 1; j >= 0; j-- {
+		action := board.Actions[j]
+		if action.Type != "commentCard" {
+			continue
+		}
+
+		i, ok := task_ids[action.Data.Card.ID]
+		if !ok {
+			continue
+		}
+
+		comment_id++
+		export.Tasks[i].Comments = append(export.Tasks[i].Comments, ExportedComment{
+			ID:     comment_id,
+			Author: usernames[action.MemberCreator.ID],
+			Text:   action.Data.Text,
+		})
+	}
+
+	return export, notes, nil
+}
+
+// ConvertCSV turns a spreadsheet into a workspace export, one task per row
+// after the header. columns maps the fields in CSVColumns to the headers they
+// are read from; fields left out are read from the header of the same name
+// when there is one. Assignees given by email are matched by it.
+func ConvertCSV(data string, columns map[string]string, name string) (WorkspaceExport, []string, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		return WorkspaceExport{}, []string{}, custom_errors.ErrInvalidArguments
+	}
+
+	headers := make(map[string]int)
+	for i, header := range records[0] {
+		headers[strings.TrimSpace(header)] = i
+	}
+
+	indexes := make(map[string]int)
+	for _, column := range CSVColumns {
+		header, ok := columns[column]
+		if !ok {
+			header = column
+		}
+
+		if index, found := headers[header]; found {
+			indexes[column] = index
+		} else if ok {
+			return WorkspaceExport{}, []string{}, custom_errors.ErrInvalidArguments
+		}
+	}
+
+	for column := range columns {
+		if _, ok := indexes[column]; !ok {
+			return WorkspaceExport{}, []string{}, custom_errors.ErrInvalidArguments
+		}
+	}
+
+	if _, ok := indexes[TitleColumn]; !ok {
+		return WorkspaceExport{}, []string{}, custom_errors.ErrInvalidArguments
+	}
+
+	export := newWorkspaceExport(name, "")
+	notes := []string{}
+
+	members := make(map[string]bool)
+	label_ids := make(map[string]uint)
+	subtask_id := uint(0)
+
+	for i, record := range records[1:] {
+		row := i + 2
+
+		cell := func(column string) string {
+			if index, ok := indexes[column]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+
+		number := func(column string) int {
+			value := cell(column)
+			if value == "" {
+				return 0
+			}
+
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				notes = append(notes, fmt.Sprintf("row %d: %s %q is not a number and was dropped", row, column, value))
+			}
+			return number
+		}
+
+		task := ExportedTask{
+			ID:            uint(len(export.Tasks) + 1),
+			Title:         cell(TitleColumn),
+			Description:   cell(DescriptionColumn),
+			Status:        Planned,
+			EstimatedTime: number(EstimatedTimeColumn),
+			ActualTime:    number(ActualTimeColumn),
+			Priority:      number(PriorityColumn),
+			Assignees:     []string{},
+			Watchers:      []string{},
+			LabelIDs:      []uint{},
+			Subtasks:      []ExportedSubtask{},
+			Comments:      []ExportedComment{},
+		}
+
+		if task.Title == "" {
+			notes = append(notes, fmt.Sprintf("row %d has no title and was skipped", row))
+			continue
+		}
+
+		if status := cell(StatusColumn); status != "" {
+			var ok bool
+			if task.Status, ok = statusFromName(status); !ok {
+				notes = append(notes, fmt.Sprintf("row %d: status %q was taken as %s", row, status, task.Status))
+			}
+		}
+
+		if due_date := cell(DueDateColumn); due_date != "" {
+			parsed := false
+			for _, layout := range csvDateLayouts {
+				if value, err := time.Parse(layout, due_date); err == nil {
+					task.DueDate, parsed = value, true
+					break
+				}
+			}
+
+			if !parsed {
+				notes = append(notes, fmt.Sprintf("row %d: due date %q is invalid and was dropped", row, due_date))
+			}
+		}
+
+		for _, assignee := range splitCell(cell(AssigneesColumn)) {
+			if !members[assignee] {
+				members[assignee] = true
+
+				member := ExportedMember{Username: assignee, Role: StandardUser}
+				if strings.Contains(assignee, "@") {
+					member.Email = assignee
+				}
+				export.Members = append(export.Members, member)
+			}
+
+			task.Assignees = append(task.Assignees, assignee)
+		}
+
+		for _, label := range splitCell(cell(LabelsColumn)) {
+			label_id, ok := label_ids[label]
+			if !ok {
+				label_id = uint(len(export.Labels) + 1)
+				label_ids[label] = label_id
+				export.Labels = append(export.Labels, ExportedLabel{ID: label_id, Name: label, Color: defaultLabelColor})
+			}
+
+			task.LabelIDs = append(task.LabelIDs, label_id)
+		}
+
+		for _, title := range strings.Split(cell(SubtasksColumn), ";") {
+			if title = strings.TrimSpace(title); title != "" {
+				subtask_id++
+				task.Subtasks = append(task.Subtasks, ExportedSubtask{ID: subtask_id, Title: title, Assignees: []string{}})
+			}
+		}
+
+		export.Tasks = append(export.Tasks, task)
+	}
+
+	return export, notes, nil
+}
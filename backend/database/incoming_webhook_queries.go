@@ -0,0 +1,329 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func scanIncomingWebhook(row rowScanner) (IncomingWebhook, error) {
+	var incoming_webhook IncomingWebhook
+	var field_mapping, default_values string
+	var created_by sql.NullInt64
+
+	err := row.Scan(
+		&incoming_webhook.ID,
+		&incoming_webhook.WorkspaceID,
+		&incoming_webhook.Name,
+		&incoming_webhook.Token,
+		&field_mapping,
+		&default_values,
+		&created_by,
+		&incoming_webhook.CreatedAt,
+		&incoming_webhook.UpdatedAt)
+	if err != nil {
+		return incoming_webhook, err
+	}
+
+	incoming_webhook.CreatedBy = uint(created_by.Int64)
+
+	if err := json.Unmarshal([]byte(field_mapping), &incoming_webhook.FieldMapping); err != nil {
+		return incoming_webhook, err
+	}
+	if err := json.Unmarshal([]byte(default_values), &incoming_webhook.DefaultValues); err != nil {
+		return incoming_webhook, err
+	}
+
+	return incoming_webhook, nil
+}
+
+func scanTaskSource(row rowScanner) (TaskSource, error) {
+	var task_source TaskSource
+	var external_id sql.NullString
+	var task_id sql.NullInt64
+
+	err := row.Scan(
+		&task_source.ID,
+		&task_source.IncomingWebhookID,
+		&external_id,
+		&task_id,
+		&task_source.SourceURL,
+		&task_source.CreatedAt)
+
+	task_source.ExternalID = external_id.String
+	task_source.TaskID = uint(task_id.Int64)
+
+	return task_source, err
+}
+
+func checkIncomingWebhookAccess(requester_user_id uint, workspace_id uint, incoming_webhook_id uint) error {
+	actual_workspace_id, err := getIncomingWebhookWorkspaceID(incoming_webhook_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, IntegrationManagePermission)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetIncomingWebhooksInWorkspace(requester_user_id uint, workspace_id uint) ([]IncomingWebhook, error) {
+	err := checkPermission(requester_user_id, workspace_id, IntegrationManagePermission)
+	if err != nil {
+		return []IncomingWebhook{}, err
+	}
+
+	rows, err := DB.Query(`
+		SELECT *
+		FROM IncomingWebhook
+		WHERE workspace_id = $1;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []IncomingWebhook{}, custom_errors.ErrDatabaseFailure
+	}
+
+	incoming_webhooks := []IncomingWebhook{}
+
+	for rows.Next() {
+		if incoming_webhook, err := scanIncomingWebhook(rows); err != nil {
+			log.Println("Error:", err)
+			return []IncomingWebhook{}, custom_errors.ErrDatabaseFailure
+		} else {
+			incoming_webhooks = append(incoming_webhooks, incoming_webhook)
+		}
+	}
+
+	return incoming_webhooks, nil
+}
+
+func CreateIncomingWebhookInWorkspace(requester_user_id uint, workspace_id uint, name string, field_mapping map[string]string, default_values map[string]string) (IncomingWebhook, error) {
+	err := checkPermission(requester_user_id, workspace_id, IntegrationManagePermission)
+	if err != nil {
+		return IncomingWebhook{}, err
+	}
+
+	token, err := generateSecretToken()
+	if err != nil {
+		log.Println("Error:", err)
+		return IncomingWebhook{}, custom_errors.ErrDatabaseFailure
+	}
+
+	encoded_field_mapping, _ := json.Marshal(field_mapping)
+	encoded_default_values, _ := json.Marshal(default_values)
+
+	incoming_webhook, err := scanIncomingWebhook(DB.QueryRow(`
+		INSERT INTO
+		IncomingWebhook(workspace_id, name, token, field_mapping, default_values, created_by, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING *;`,
+		workspace_id, name, token, string(encoded_field_mapping), string(encoded_default_values),
+		requester_user_id, time.Now(), time.Now()))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return IncomingWebhook{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return incoming_webhook, nil
+}
+
+func UpdateIncomingWebhook(requester_user_id uint, workspace_id uint, incoming_webhook_id uint, name string, field_mapping map[string]string, default_values map[string]string) error {
+	if err := checkIncomingWebhookAccess(requester_user_id, workspace_id, incoming_webhook_id); err != nil {
+		return err
+	}
+
+	encoded_field_mapping, _ := json.Marshal(field_mapping)
+	encoded_default_values, _ := json.Marshal(default_values)
+
+	_, err := DB.Exec(`
+		UPDATE IncomingWebhook
+		SET name = $1, field_mapping = $2, default_values = $3, updated_at = $4
+		WHERE id = $5;`,
+		name, string(encoded_field_mapping), string(encoded_default_values), time.Now(), incoming_webhook_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func RegenerateIncomingWebhookToken(requester_user_id uint, workspace_id uint, incoming_webhook_id uint) (IncomingWebhook, error) {
+	if err := checkIncomingWebhookAccess(requester_user_id, workspace_id, incoming_webhook_id); err != nil {
+		return IncomingWebhook{}, err
+	}
+
+	token, err := generateSecretToken()
+	if err != nil {
+		log.Println("Error:", err)
+		return IncomingWebhook{}, custom_errors.ErrDatabaseFailure
+	}
+
+	incoming_webhook, err := scanIncomingWebhook(DB.QueryRow(`
+		UPDATE IncomingWebhook
+		SET token = $1, updated_at = $2
+		WHERE id = $3
+		RETURNING *;`,
+		token, time.Now(), incoming_webhook_id))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return IncomingWebhook{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return incoming_webhook, nil
+}
+
+func DeleteIncomingWebhook(requester_user_id uint, workspace_id uint, incoming_webhook_id uint) error {
+	if err := checkIncomingWebhookAccess(requester_user_id, workspace_id, incoming_webhook_id); err != nil {
+		return err
+	}
+
+	_, err := DB.Exec(`
+		DELETE FROM IncomingWebhook
+		WHERE id = $1;`,
+		incoming_webhook_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetIncomingWebhookByToken(token string) (IncomingWebhook, error) {
+	incoming_webhook, err := scanIncomingWebhook(DB.QueryRow(`
+		SELECT *
+		FROM IncomingWebhook
+		WHERE token = $1;`,
+		token))
+
+	if err == sql.ErrNoRows {
+		return IncomingWebhook{}, custom_errors.ErrAccessDenied
+	} else if err != nil {
+		log.Println("Error:", err)
+		return IncomingWebhook{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return incoming_webhook, nil
+}
+
+// CreateTaskFromIncomingWebhook creates the task on behalf of the webhook's
+// creator. When an external id is given, a payload that was already turned
+// into a task returns the existing task instead of creating a new one.
+func CreateTaskFromIncomingWebhook(incoming_webhook IncomingWebhook, external_id string, source_url string, title string, description string, estimatedtime int, actualtime int, duedate time.Time, priority int, assignee_ids []uint, imageURL string) (Task, bool, error) {
+	if incoming_webhook.CreatedBy == 0 {
+		return Task{}, false, custom_errors.ErrAccessDenied
+	}
+
+	if external_id != "" {
+		var task_id sql.NullInt64
+
+		_, err := DB.Exec(`
+			DELETE FROM TaskSource
+			WHERE incoming_webhook_id = $1 AND external_id = $2 AND task_id IS NULL AND created_at < $3;`,
+			incoming_webhook.ID, external_id, time.Now().Add(-5*time.Minute))
+
+		if err != nil {
+			log.Println("Error:", err)
+			return Task{}, false, custom_errors.ErrDatabaseFailure
+		}
+
+		err = DB.QueryRow(`
+			SELECT task_id
+			FROM TaskSource
+			WHERE incoming_webhook_id = $1 AND external_id = $2;`,
+			incoming_webhook.ID, external_id).Scan(&task_id)
+
+		if err == nil && task_id.Valid {
+			task, err := GetDetailsOfTask(incoming_webhook.CreatedBy, incoming_webhook.WorkspaceID, uint(task_id.Int64))
+			return task, false, err
+		} else if err == nil {
+			return Task{}, false, custom_errors.ErrDuplicateExternalID
+		} else if err != sql.ErrNoRows {
+			log.Println("Error:", err)
+			return Task{}, false, custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	var task_source_id uint
+
+	err := DB.QueryRow(`
+		INSERT INTO
+		TaskSource(incoming_webhook_id, external_id, source_url, created_at)
+		VALUES($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+		RETURNING id;`,
+		incoming_webhook.ID, sql.NullString{String: external_id, Valid: external_id != ""},
+		source_url, time.Now()).Scan(&task_source_id)
+
+	if err == sql.ErrNoRows {
+		return Task{}, false, custom_errors.ErrDuplicateExternalID
+	} else if err != nil {
+		log.Println("Error:", err)
+		return Task{}, false, custom_errors.ErrDatabaseFailure
+	}
+
+	task, err := CreateTaskInWorkspace(incoming_webhook.CreatedBy, incoming_webhook.WorkspaceID, title, description, estimatedtime, actualtime, duedate, priority, assignee_ids, imageURL)
+	if err != nil {
+		DB.Exec(`
+			DELETE FROM TaskSource
+			WHERE id = $1;`,
+			task_source_id)
+		return Task{}, false, err
+	}
+
+	_, err = DB.Exec(`
+		UPDATE TaskSource
+		SET task_id = $1
+		WHERE id = $2;`,
+		task.ID, task_source_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Task{}, false, custom_errors.ErrDatabaseFailure
+	}
+
+	return task, true, nil
+}
+
+func GetTaskSource(requester_user_id uint, workspace_id uint, task_id uint) (TaskSource, error) {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return TaskSource{}, err
+	} else if actual_workspace_id != workspace_id {
+		return TaskSource{}, custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return TaskSource{}, err
+	}
+
+	task_source, err := scanTaskSource(DB.QueryRow(`
+		SELECT *
+		FROM TaskSource
+		WHERE task_id = $1;`,
+		task_id))
+
+	if err == sql.ErrNoRows {
+		return TaskSource{}, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return TaskSource{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return task_source, nil
+}
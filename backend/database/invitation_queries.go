@@ -0,0 +1,489 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	email_utils "github.com/skye-tan/trello/backend/utils/email"
+)
+
+const invitationValidPeriod = time.Hour * 24 * 7
+
+func scanInvitation(row rowScanner) (Invitation, error) {
+	var invitation Invitation
+	var invitee_user_id, max_uses, invited_by sql.NullInt64
+	var invitee_email sql.NullString
+
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.WorkspaceID,
+		&invitation.Kind,
+		&invitee_user_id,
+		&invitee_email,
+		&invitation.Role,
+		&invitation.Token,
+		&max_uses,
+		&invitation.UseCount,
+		&invitation.Status,
+		&invited_by,
+		&invitation.ExpiresAt,
+		&invitation.CreatedAt,
+		&invitation.UpdatedAt,
+		&invitation.WorkspaceName)
+
+	invitation.InviteeUserID = uint(invitee_user_id.Int64)
+	invitation.InviteeEmail = invitee_email.String
+	invitation.MaxUses = uint(max_uses.Int64)
+	invitation.InvitedBy = uint(invited_by.Int64)
+
+	// Only link invitations are redeemed by token, the others are answered
+	// by the invitee, so their token is never handed out.
+	if invitation.Kind != LinkInvitation {
+		invitation.Token = ""
+	}
+
+	return invitation, err
+}
+
+func getInvitation(invitation_id uint) (Invitation, error) {
+	invitation, err := scanInvitation(DB.QueryRow(`
+		SELECT Invitation.*, Workspace.name
+		FROM Invitation
+		JOIN Workspace ON Workspace.id = Invitation.workspace_id
+		WHERE Invitation.id = $1;`,
+		invitation_id))
+
+	if err == sql.ErrNoRows {
+		return Invitation{}, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return Invitation{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return invitation, nil
+}
+
+func getInvitations(query string, args ...any) ([]Invitation, error) {
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		log.Println("Error:", err)
+		return []Invitation{}, custom_errors.ErrDatabaseFailure
+	}
+
+	invitations := []Invitation{}
+
+	for rows.Next() {
+		if invitation, err := scanInvitation(rows); err != nil {
+			log.Println("Error:", err)
+			return []Invitation{}, custom_errors.ErrDatabaseFailure
+		} else {
+			invitations = append(invitations, invitation)
+		}
+	}
+
+	return invitations, nil
+}
+
+func isWorkspaceMember(user_id uint, workspace_id uint) (bool, error) {
+	var is_member bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM UserWorkspaceRole
+			WHERE user_id = $1 AND workspace_id = $2
+		);`,
+		user_id, workspace_id).
+		Scan(&is_member)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return is_member, nil
+}
+
+func addMember(user_id uint, workspace_id uint, role string) (UserWorkspaceRole, error) {
+	var user_workspace_role UserWorkspaceRole
+
+	err := DB.QueryRow(`
+		INSERT INTO
+		UserWorkspaceRole(user_id, workspace_id, role, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5)
+		RETURNING *;`,
+		user_id, workspace_id, role,
+		time.Now(), time.Now()).
+		Scan(&user_workspace_role.ID,
+			&user_workspace_role.UserID,
+			&user_workspace_role.WorkspaceID,
+			&user_workspace_role.Role,
+			&user_workspace_role.CreatedAt,
+			&user_workspace_role.UpdatedAt)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return UserWorkspaceRole{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return user_workspace_role, nil
+}
+
+// checkInviteeEligibility makes sure the user can join the workspace through
+// an invitation: the address is confirmed, they are not a member yet and they
+// are not a service account, which belongs to exactly one workspace.
+func checkInviteeEligibility(user_id uint, workspace_id uint) error {
+	verified, err := isEmailVerified(user_id)
+	if err != nil {
+		return err
+	} else if !verified {
+		return custom_errors.ErrEmailNotVerified
+	}
+
+	is_member, err := isWorkspaceMember(user_id, workspace_id)
+	if err != nil {
+		return err
+	} else if is_member {
+		return custom_errors.ErrAlreadyMember
+	}
+
+	var is_service_account bool
+
+	err = DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM ServiceAccount WHERE user_id = $1
+		);`,
+		user_id).
+		Scan(&is_service_account)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if is_service_account {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
+
+func findUserForInvitation(username string, email string) (User, error) {
+	var user User
+
+	err := DB.QueryRow(`
+		SELECT * FROM Users
+		WHERE ($1 <> '' AND username = $1) OR ($2 <> '' AND LOWER(email) = LOWER($2));`,
+		username, email).
+		Scan(&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.PasswordHash,
+			&user.CreatedAt,
+			&user.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return User{}, err
+	} else if err != nil {
+		log.Println("Error:", err)
+		return User{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return user, nil
+}
+
+func queueSignupInvitationEmail(inviter_user_id uint, invitation Invitation) error {
+	inviter, err := GetUserByID(inviter_user_id)
+	if err != nil {
+		return err
+	}
+
+	// The outbox needs an owning account and the invitee has none yet, so
+	// the email is filed under the inviter.
+	return queueEmail(inviter.ID, invitation.InviteeEmail, "invitation_signup", email_utils.TemplateData{
+		Message: fmt.Sprintf("'%s' has invited you to join workspace '%s' as %s.",
+			inviter.Username, invitation.WorkspaceName, invitation.Role),
+		ActionURL: email_utils.SignupURL(),
+	})
+}
+
+// claimEmailInvitations hands pending invitations sent to an address to the
+// account that has just been registered with it.
+func claimEmailInvitations(user User) error {
+	_, err := DB.Exec(`
+		UPDATE Invitation
+		SET invitee_user_id = $1, updated_at = $2
+		WHERE kind = $3 AND invitee_user_id IS NULL AND LOWER(invitee_email) = LOWER($4) AND status = $5;`,
+		user.ID, time.Now(), EmailInvitation, user.Email, PendingInvitation)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetInvitationsInWorkspace(requester_user_id uint, workspace_id uint) ([]Invitation, error) {
+	err := checkPermission(requester_user_id, workspace_id, MemberInvitePermission)
+	if err != nil {
+		return []Invitation{}, err
+	}
+
+	return getInvitations(`
+		SELECT Invitation.*, Workspace.name
+		FROM Invitation
+		JOIN Workspace ON Workspace.id = Invitation.workspace_id
+		WHERE Invitation.workspace_id = $1 AND Invitation.status = $2 AND Invitation.expires_at > $3
+		ORDER BY Invitation.id;`,
+		workspace_id, PendingInvitation, time.Now())
+}
+
+// CreateInvitation invites a registered user picked by username or email, an
+// email address that has no account yet, or, when neither is given, anyone
+// holding the returned link token up to max_uses times.
+func CreateInvitation(requester_user_id uint, workspace_id uint, username string, email string, role string, expires_at time.Time, max_uses uint) (Invitation, error) {
+	user_role, permissions, err := getUserPermissions(requester_user_id, workspace_id)
+	if err != nil {
+		return Invitation{}, err
+	} else if !permissions.has(MemberInvitePermission) {
+		return Invitation{}, custom_errors.ErrAccessDenied
+	}
+
+	if err := checkRoleAuthority(user_role, permissions, workspace_id, role); err != nil {
+		return Invitation{}, err
+	}
+
+	if expires_at.IsZero() {
+		expires_at = time.Now().Add(invitationValidPeriod)
+	}
+
+	kind := LinkInvitation
+	invitee_user_id := sql.NullInt64{}
+	invitee_email := sql.NullString{}
+
+	if username != "" || email != "" {
+		user, err := findUserForInvitation(username, email)
+		if err == sql.ErrNoRows && username == "" {
+			kind = EmailInvitation
+			invitee_email = sql.NullString{String: email, Valid: true}
+		} else if err == sql.ErrNoRows {
+			return Invitation{}, custom_errors.ErrInvalidArguments
+		} else if err != nil {
+			return Invitation{}, err
+		} else {
+			if err := checkInviteeEligibility(user.ID, workspace_id); err != nil {
+				return Invitation{}, err
+			}
+			kind = DirectInvitation
+			invitee_user_id = sql.NullInt64{Int64: int64(user.ID), Valid: true}
+		}
+
+		var duplicate bool
+
+		err = DB.QueryRow(`
+			SELECT EXISTS (
+				SELECT 1
+				FROM Invitation
+				WHERE workspace_id = $1 AND status = $2 AND expires_at > $3
+					AND (invitee_user_id = $4 OR LOWER(invitee_email) = LOWER($5))
+			);`,
+			workspace_id, PendingInvitation, time.Now(), invitee_user_id, invitee_email).
+			Scan(&duplicate)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return Invitation{}, custom_errors.ErrDatabaseFailure
+		} else if duplicate {
+			return Invitation{}, custom_errors.ErrDuplicateInvitation
+		}
+	}
+
+	token, err := generateSecretToken()
+	if err != nil {
+		log.Println("Error:", err)
+		return Invitation{}, custom_errors.ErrTokenFailure
+	}
+
+	var invitation_id uint
+
+	err = DB.QueryRow(`
+		INSERT INTO
+		Invitation(workspace_id, kind, invitee_user_id, invitee_email, role, token, max_uses, status, invited_by, expires_at, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id;`,
+		workspace_id, kind, invitee_user_id, invitee_email, role, token,
+		sql.NullInt64{Int64: int64(max_uses), Valid: kind == LinkInvitation && max_uses != 0},
+		PendingInvitation, requester_user_id, expires_at, time.Now(), time.Now()).
+		Scan(&invitation_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Invitation{}, custom_errors.ErrDatabaseFailure
+	}
+
+	invitation, err := getInvitation(invitation_id)
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	if kind == EmailInvitation {
+		if err := queueSignupInvitationEmail(requester_user_id, invitation); err != nil {
+			log.Println("Error: failed to queue invitation email for invitation", invitation.ID)
+		}
+	}
+
+	return invitation, nil
+}
+
+func RevokeInvitation(requester_user_id uint, workspace_id uint, invitation_id uint) error {
+	err := checkPermission(requester_user_id, workspace_id, MemberInvitePermission)
+	if err != nil {
+		return err
+	}
+
+	result, err := DB.Exec(`
+		UPDATE Invitation
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND workspace_id = $4 AND status = $5;`,
+		RevokedInvitation, time.Now(), invitation_id, workspace_id, PendingInvitation)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
+
+func GetInvitationsOfUser(requester_user_id uint) ([]Invitation, error) {
+	return getInvitations(`
+		SELECT Invitation.*, Workspace.name
+		FROM Invitation
+		JOIN Workspace ON Workspace.id = Invitation.workspace_id
+		WHERE Invitation.invitee_user_id = $1 AND Invitation.status = $2 AND Invitation.expires_at > $3
+		ORDER BY Invitation.id;`,
+		requester_user_id, PendingInvitation, time.Now())
+}
+
+func GetInvitation(requester_user_id uint, invitation_id uint) (Invitation, error) {
+	invitation, err := getInvitation(invitation_id)
+	if err != nil {
+		return Invitation{}, err
+	} else if invitation.InviteeUserID != requester_user_id {
+		return Invitation{}, custom_errors.ErrAccessDenied
+	}
+
+	return invitation, nil
+}
+
+func DeclineInvitation(requester_user_id uint, invitation_id uint) error {
+	result, err := DB.Exec(`
+		UPDATE Invitation
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND invitee_user_id = $4 AND status = $5;`,
+		DeclinedInvitation, time.Now(), invitation_id, requester_user_id, PendingInvitation)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
+
+func AcceptInvitation(requester_user_id uint, invitation_id uint) (UserWorkspaceRole, error) {
+	invitation, err := GetInvitation(requester_user_id, invitation_id)
+	if err != nil {
+		return UserWorkspaceRole{}, err
+	}
+
+	err = checkInviteeEligibility(requester_user_id, invitation.WorkspaceID)
+	if err != nil {
+		return UserWorkspaceRole{}, err
+	}
+
+	result, err := DB.Exec(`
+		UPDATE Invitation
+		SET status = $1, use_count = use_count + 1, updated_at = $2
+		WHERE id = $3 AND invitee_user_id = $4 AND status = $5 AND expires_at > $2;`,
+		AcceptedInvitation, time.Now(), invitation_id, requester_user_id, PendingInvitation)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return UserWorkspaceRole{}, custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return UserWorkspaceRole{}, custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return UserWorkspaceRole{}, custom_errors.ErrInvalidArguments
+	}
+
+	return addMember(requester_user_id, invitation.WorkspaceID, invitation.Role)
+}
+
+// AcceptInvitationLink redeems a shareable invitation. The use is counted in
+// the same statement that checks the cap so concurrent redemptions cannot
+// exceed it.
+func AcceptInvitationLink(requester_user_id uint, token string) (UserWorkspaceRole, error) {
+	var workspace_id uint
+
+	err := DB.QueryRow(`
+		SELECT workspace_id
+		FROM Invitation
+		WHERE token = $1 AND kind = $2;`,
+		token, LinkInvitation).
+		Scan(&workspace_id)
+
+	if err == sql.ErrNoRows {
+		return UserWorkspaceRole{}, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return UserWorkspaceRole{}, custom_errors.ErrDatabaseFailure
+	}
+
+	err = checkInviteeEligibility(requester_user_id, workspace_id)
+	if err != nil {
+		return UserWorkspaceRole{}, err
+	}
+
+	var role string
+
+	err = DB.QueryRow(`
+		UPDATE Invitation
+		SET use_count = use_count + 1, updated_at = $1
+		WHERE token = $2 AND kind = $3 AND status = $4 AND expires_at > $1
+			AND (max_uses IS NULL OR use_count < max_uses)
+		RETURNING role;`,
+		time.Now(), token, LinkInvitation, PendingInvitation).
+		Scan(&role)
+
+	if err == sql.ErrNoRows {
+		return UserWorkspaceRole{}, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return UserWorkspaceRole{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return addMember(requester_user_id, workspace_id, role)
+}
@@ -0,0 +1,281 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func GetAllLabelsInWorkspace(requester_user_id uint, workspace_id uint) ([]Label, error) {
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return []Label{}, err
+	}
+
+	rows, err := DB.Query(`
+		SELECT *
+		FROM Label
+		WHERE workspace_id = $1;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []Label{}, custom_errors.ErrDatabaseFailure
+	}
+
+	var labels []Label
+
+	for rows.Next() {
+		var label Label
+
+		if err := rows.Scan(
+			&label.ID,
+			&label.WorkspaceID,
+			&label.Name,
+			&label.Color,
+			&label.CreatedAt,
+			&label.UpdatedAt); err != nil {
+			log.Println("Error:", err)
+			return []Label{}, custom_errors.ErrDatabaseFailure
+		} else {
+			labels = append(labels, label)
+		}
+	}
+
+	return labels, nil
+}
+
+func CreateLabelInWorkspace(requester_user_id uint, workspace_id uint, name string, color string) (Label, error) {
+	err := checkPermission(requester_user_id, workspace_id, LabelManagePermission)
+	if err != nil {
+		return Label{}, err
+	}
+
+	if ok := checkDuplicateLabelName(workspace_id, name); !ok {
+		return Label{}, custom_errors.ErrDuplicateLabelName
+	}
+
+	var label Label
+
+	err = DB.QueryRow(`
+		INSERT INTO
+		Label(workspace_id, name, color, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5)
+		RETURNING *;`,
+		workspace_id, name, color,
+		time.Now(), time.Now()).
+		Scan(&label.ID,
+			&label.WorkspaceID,
+			&label.Name,
+			&label.Color,
+			&label.CreatedAt,
+			&label.UpdatedAt)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Label{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return label, nil
+}
+
+func GetDetailsOfLabel(requester_user_id uint, workspace_id uint, label_id uint) (Label, error) {
+	actual_workspace_id, err := getLabelWorkspaceID(label_id)
+	if err != nil {
+		return Label{}, err
+	} else if actual_workspace_id != workspace_id {
+		return Label{}, custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return Label{}, err
+	}
+
+	var label Label
+
+	err = DB.QueryRow(`
+		SELECT *
+		FROM Label
+		WHERE id = $1;`,
+		label_id).
+		Scan(&label.ID,
+			&label.WorkspaceID,
+			&label.Name,
+			&label.Color,
+			&label.CreatedAt,
+			&label.UpdatedAt)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Label{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return label, nil
+}
+
+func UpdateDetailsOfLabel(requester_user_id uint, workspace_id uint, label_id uint, name string, color string) error {
+	actual_workspace_id, err := getLabelWorkspaceID(label_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, LabelManagePermission)
+	if err != nil {
+		return err
+	}
+
+	if ok := checkDuplicateLabelNameWithoutSelf(workspace_id, name, label_id); !ok {
+		return custom_errors.ErrDuplicateLabelName
+	}
+
+	_, err = DB.Exec(`
+		UPDATE Label
+		SET name = $1, color = $2, updated_at = $3
+		WHERE id = $4;`,
+		name, color, time.Now(), label_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func DeleteLabel(requester_user_id uint, workspace_id uint, label_id uint) error {
+	actual_workspace_id, err := getLabelWorkspaceID(label_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, LabelManagePermission)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM Label
+		WHERE id = $1;`,
+		label_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetLabelsOfTask(requester_user_id uint, workspace_id uint, task_id uint) ([]Label, error) {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return []Label{}, err
+	} else if actual_workspace_id != workspace_id {
+		return []Label{}, custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return []Label{}, err
+	}
+
+	rows, err := DB.Query(`
+		SELECT A.*
+		FROM Label A JOIN TaskLabel B
+		ON A.id = B.label_id
+		WHERE B.task_id = $1;`,
+		task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []Label{}, custom_errors.ErrDatabaseFailure
+	}
+
+	var labels []Label
+
+	for rows.Next() {
+		var label Label
+
+		if err := rows.Scan(
+			&label.ID,
+			&label.WorkspaceID,
+			&label.Name,
+			&label.Color,
+			&label.CreatedAt,
+			&label.UpdatedAt); err != nil {
+			log.Println("Error:", err)
+			return []Label{}, custom_errors.ErrDatabaseFailure
+		} else {
+			labels = append(labels, label)
+		}
+	}
+
+	return labels, nil
+}
+
+func AddLabelToTask(requester_user_id uint, workspace_id uint, task_id uint, label_id uint) error {
+	task_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return err
+	} else if task_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	label_workspace_id, err := getLabelWorkspaceID(label_id)
+	if err != nil {
+		return err
+	} else if label_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, LabelApplyPermission)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		TaskLabel(task_id, label_id)
+		VALUES($1, $2)
+		ON CONFLICT DO NOTHING;`,
+		task_id, label_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func RemoveLabelFromTask(requester_user_id uint, workspace_id uint, task_id uint, label_id uint) error {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, LabelApplyPermission)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM TaskLabel
+		WHERE task_id = $1 AND label_id = $2;`,
+		task_id, label_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Tests that need Postgres and Redis run against the services configured
+// through the same environment variables as the server, and are skipped
+// when PQ_HOST is not set.
+func TestMain(m *testing.M) {
+	if _, ok := os.LookupEnv("PQ_HOST"); ok {
+		InitializeDatabase()
+	}
+
+	os.Exit(m.Run())
+}
+
+func requireDatabase(t *testing.T) {
+	t.Helper()
+
+	if DB == nil {
+		t.Skip("PQ_HOST is not set")
+	}
+}
+
+var testNameCounter atomic.Int64
+
+// testName returns a short alphanumeric name that is unique across test runs.
+func testName(prefix string) string {
+	return fmt.Sprintf("%s%d%d", prefix, time.Now().Unix()%100000, testNameCounter.Add(1))
+}
+
+func createTestUser(t *testing.T) User {
+	t.Helper()
+
+	username := testName("u")
+	if err := CreateUser(username, username+"@test.local", []byte("password")); err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+
+	user, err := GetUserByUsername(username)
+	if err != nil {
+		t.Fatalf("getting user: %v", err)
+	}
+
+	t.Cleanup(func() { DeleteUser(user.ID) })
+
+	return user
+}
+
+func createTestWorkspace(t *testing.T, user_id uint) Workspace {
+	t.Helper()
+
+	workspace, err := CreateWorkSpace(user_id, testName("w"), "", 0)
+	if err != nil {
+		t.Fatalf("creating workspace: %v", err)
+	}
+
+	return workspace
+}
+
+func createTestTask(t *testing.T, user_id uint, workspace_id uint) Task {
+	t.Helper()
+
+	task, err := CreateTaskInWorkspace(user_id, workspace_id, testName("t"), "", 0, 0,
+		time.Now().AddDate(0, 0, 7), 0, []uint{}, "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+
+	return task
+}
@@ -0,0 +1,404 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+const maxTaskTitleLength = 30
+
+// uniqueName returns the name itself when it is free, or the first free
+// "name (n)" otherwise, shortened to fit the column it is stored in.
+func uniqueName(name string, max_length int, taken func(string) bool) (string, error) {
+	if base := []rune(name); len(base) > max_length {
+		name = string(base[:max_length])
+	}
+
+	candidate := name
+	for n := 2; taken(candidate); n++ {
+		if n > 100 {
+			return "", custom_errors.ErrInvalidArguments
+		}
+
+		suffix := fmt.Sprintf(" (%d)", n)
+		base := []rune(name)
+		if len(base)+len(suffix) > max_length {
+			base = base[:max_length-len(suffix)]
+		}
+		candidate = string(base) + suffix
+	}
+
+	return candidate, nil
+}
+
+// uniqueTaskTitle picks a free title for the task in the workspace. Titles
+// handed out earlier in the same batch are passed in reserved so they are not
+// given out twice.
+func uniqueTaskTitle(workspace_id uint, title string, reserved map[string]bool) (string, error) {
+	candidate, err := uniqueName(title, maxTaskTitleLength, func(candidate string) bool {
+		return reserved[candidate] || !checkDuplicateTaskTitle(workspace_id, candidate)
+	})
+	if err != nil {
+		return "", custom_errors.ErrDuplicateTaskTitle
+	}
+
+	reserved[candidate] = true
+	return candidate, nil
+}
+
+// checkTaskMove makes sure tasks may leave the workspace for the target one,
+// which takes deleting them from the first and creating them in the second.
+func checkTaskMove(requester_user_id uint, workspace_id uint, target_workspace_id uint) error {
+	if target_workspace_id == workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	if err := checkPermission(requester_user_id, workspace_id, TaskDeletePermission); err != nil {
+		return err
+	}
+
+	return checkPermission(requester_user_id, target_workspace_id, TaskCreatePermission)
+}
+
+func getTaskTitle(task_id uint) (string, error) {
+	var title string
+
+	err := DB.QueryRow(`
+		SELECT title
+		FROM Task
+		WHERE id = $1;`,
+		task_id).
+		Scan(&title)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return "", custom_errors.ErrDatabaseFailure
+	}
+
+	return title, nil
+}
+
+// moveTask hands the task over to the target workspace under the given title.
+// Subtasks, comments and watchers travel with it, while everything that only
+// makes sense in the old workspace is dropped: assignees and watchers who are
+// not members of the target, labels without a namesake there and dependencies
+// on tasks left behind.
+func moveTask(executor queryExecutor, task_id uint, target_workspace_id uint, title string) error {
+	_, err := executor.Exec(`
+		UPDATE Task
+		SET workspace_id = $2, title = $3, updated_at = $4, assignee_id = (
+			SELECT Task.assignee_id
+			FROM UserWorkspaceRole
+			WHERE user_id = Task.assignee_id AND workspace_id = $2
+		)
+		WHERE id = $1;`,
+		task_id, target_workspace_id, title, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	statements := []string{`
+		DELETE FROM TaskAssignee
+		WHERE task_id = $1 AND user_id NOT IN (
+			SELECT user_id FROM UserWorkspaceRole WHERE workspace_id = $2
+		);`, `
+		UPDATE Subtask
+		SET assignee_id = NULL
+		WHERE task_id = $1 AND assignee_id NOT IN (
+			SELECT user_id FROM UserWorkspaceRole WHERE workspace_id = $2
+		);`, `
+		DELETE FROM SubtaskAssignee
+		WHERE subtask_id IN (SELECT id FROM Subtask WHERE task_id = $1) AND user_id NOT IN (
+			SELECT user_id FROM UserWorkspaceRole WHERE workspace_id = $2
+		);`, `
+		DELETE FROM Watch
+		WHERE task_id = $1 AND user_id NOT IN (
+			SELECT user_id FROM UserWorkspaceRole WHERE workspace_id = $2
+		);`, `
+		INSERT INTO
+		TaskLabel(task_id, label_id)
+		SELECT A.task_id, C.id
+		FROM TaskLabel A JOIN Label B ON A.label_id = B.id
+		JOIN Label C ON C.workspace_id = $2 AND C.name = B.name
+		WHERE A.task_id = $1
+		ON CONFLICT DO NOTHING;`, `
+		DELETE FROM TaskLabel
+		WHERE task_id = $1 AND label_id IN (
+			SELECT id FROM Label WHERE workspace_id != $2
+		);`, `
+		DELETE FROM TaskDependency
+		WHERE (task_id = $1 OR target_task_id = $1) AND EXISTS (
+			SELECT 1 FROM Task
+			WHERE Task.id IN (TaskDependency.task_id, TaskDependency.target_task_id) AND Task.workspace_id != $2
+		);`, `
+		UPDATE RecurringTask
+		SET workspace_id = $2
+		WHERE template_task_id = $1;`, `
+		UPDATE Trash
+		SET workspace_id = $2
+		WHERE parent_id = $1;`,
+	}
+
+	for _, statement := range statements {
+		_, err := executor.Exec(statement, task_id, target_workspace_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	return nil
+}
+
+// copyTask creates a copy of the task in the target workspace under the given
+// title and returns its id. Subtasks, comments, watchers and the image url
+// come along; assignees and labels follow the same rules as when moving. The
+// uploaded picture is copied separately with CopyPicture.
+func copyTask(executor queryExecutor, task_id uint, target_workspace_id uint, title string) (uint, error) {
+	var copy_id uint
+
+	err := executor.QueryRow(`
+		INSERT INTO
+		Task(title, description, status, estimated_time, actual_time, due_date, priority, workspace_id, assignee_id, created_at, updated_at, image_url)
+		SELECT $3, description, status, estimated_time, actual_time, due_date, priority, $2, (
+			SELECT Task.assignee_id
+			FROM UserWorkspaceRole
+			WHERE user_id = Task.assignee_id AND workspace_id = $2
+		), $4, $4, image_url
+		FROM Task
+		WHERE id = $1
+		RETURNING id;`,
+		task_id, target_workspace_id, title, time.Now()).
+		Scan(&copy_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	rows, err := executor.Query(`
+		SELECT id
+		FROM Subtask
+		WHERE task_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $2 AND item_id = Subtask.id
+		)
+		ORDER BY id;`,
+		task_id, SubtaskItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+	defer rows.Close()
+
+	subtask_ids := []uint{}
+
+	for rows.Next() {
+		var subtask_id uint
+
+		if err := rows.Scan(&subtask_id); err != nil {
+			log.Println("Error:", err)
+			return 0, custom_errors.ErrDatabaseFailure
+		} else {
+			subtask_ids = append(subtask_ids, subtask_id)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	for _, subtask_id := range subtask_ids {
+		var subtask_copy_id uint
+
+		err := executor.QueryRow(`
+			INSERT INTO
+			Subtask(task_id, title, is_completed, assignee_id, created_at, updated_at)
+			SELECT $2, title, is_completed, (
+				SELECT Subtask.assignee_id
+				FROM UserWorkspaceRole
+				WHERE user_id = Subtask.assignee_id AND workspace_id = $3
+			), $4, $4
+			FROM Subtask
+			WHERE id = $1
+			RETURNING id;`,
+			subtask_id, copy_id, target_workspace_id, time.Now()).
+			Scan(&subtask_copy_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return 0, custom_errors.ErrDatabaseFailure
+		}
+
+		_, err = executor.Exec(`
+			INSERT INTO
+			SubtaskAssignee(subtask_id, user_id)
+			SELECT $2, user_id
+			FROM SubtaskAssignee
+			WHERE subtask_id = $1 AND user_id IN (
+				SELECT user_id FROM UserWorkspaceRole WHERE workspace_id = $3
+			);`,
+			subtask_id, subtask_copy_id, target_workspace_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return 0, custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	statements := []string{`
+		INSERT INTO
+		TaskAssignee(task_id, user_id)
+		SELECT $2, user_id
+		FROM TaskAssignee
+		WHERE task_id = $1 AND user_id IN (
+			SELECT user_id FROM UserWorkspaceRole WHERE workspace_id = $3
+		);`, `
+		INSERT INTO
+		Watch(task_id, user_id)
+		SELECT $2, user_id
+		FROM Watch
+		WHERE task_id = $1 AND user_id IN (
+			SELECT user_id FROM UserWorkspaceRole WHERE workspace_id = $3
+		);`, `
+		INSERT INTO
+		TaskLabel(task_id, label_id)
+		SELECT $2, C.id
+		FROM TaskLabel A JOIN Label B ON A.label_id = B.id
+		JOIN Label C ON C.workspace_id = $3 AND C.name = B.name
+		WHERE A.task_id = $1
+		ON CONFLICT DO NOTHING;`,
+	}
+
+	for _, statement := range statements {
+		_, err := executor.Exec(statement, task_id, copy_id, target_workspace_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return 0, custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	_, err = executor.Exec(`
+		INSERT INTO
+		Comment(task_id, user_id, text)
+		SELECT $2, user_id, text
+		FROM Comment
+		WHERE task_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $3 AND item_id = Comment.id
+		)
+		ORDER BY id;`,
+		task_id, copy_id, CommentItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	return copy_id, nil
+}
+
+func MoveTaskToWorkspace(requester_user_id uint, workspace_id uint, task_id uint, target_workspace_id uint) (Task, error) {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return Task{}, err
+	} else if actual_workspace_id != workspace_id {
+		return Task{}, custom_errors.ErrInvalidArguments
+	}
+
+	if err := checkTaskMove(requester_user_id, workspace_id, target_workspace_id); err != nil {
+		return Task{}, err
+	}
+
+	title, err := getTaskTitle(task_id)
+	if err != nil {
+		return Task{}, err
+	}
+
+	title, err = uniqueTaskTitle(target_workspace_id, title, make(map[string]bool))
+	if err != nil {
+		return Task{}, err
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		log.Println("Error:", err)
+		return Task{}, custom_errors.ErrDatabaseFailure
+	}
+
+	if err := moveTask(tx, task_id, target_workspace_id, title); err != nil {
+		tx.Rollback()
+		return Task{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error:", err)
+		return Task{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return GetDetailsOfTask(requester_user_id, target_workspace_id, task_id)
+}
+
+// CopyTaskToWorkspace copies the task into the target workspace, which may be
+// its own one, and returns the copy.
+func CopyTaskToWorkspace(requester_user_id uint, workspace_id uint, task_id uint, target_workspace_id uint) (Task, error) {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return Task{}, err
+	} else if actual_workspace_id != workspace_id {
+		return Task{}, custom_errors.ErrInvalidArguments
+	}
+
+	if err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission); err != nil {
+		return Task{}, err
+	}
+
+	if err := checkPermission(requester_user_id, target_workspace_id, TaskCreatePermission); err != nil {
+		return Task{}, err
+	}
+
+	title, err := getTaskTitle(task_id)
+	if err != nil {
+		return Task{}, err
+	}
+
+	title, err = uniqueTaskTitle(target_workspace_id, title, make(map[string]bool))
+	if err != nil {
+		return Task{}, err
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		log.Println("Error:", err)
+		return Task{}, custom_errors.ErrDatabaseFailure
+	}
+
+	copy_id, err := copyTask(tx, task_id, target_workspace_id, title)
+	if err != nil {
+		tx.Rollback()
+		return Task{}, err
+	}
+
+	// The picture lives in Redis under the task id. It is copied before the
+	// commit so that a failure leaves no copy without it behind.
+	if err := CopyPicture(task_id, copy_id); err != nil {
+		tx.Rollback()
+		return Task{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error:", err)
+		return Task{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return GetDetailsOfTask(requester_user_id, target_workspace_id, copy_id)
+}
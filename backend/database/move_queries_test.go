@@ -0,0 +1,36 @@
+package database
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strconv"
+	"testing"
+)
+
+func TestCopyTaskToWorkspaceCopiesPicture(t *testing.T) {
+	requireDatabase(t)
+
+	user := createTestUser(t)
+	source := createTestWorkspace(t, user.ID)
+	target := createTestWorkspace(t, user.ID)
+	task := createTestTask(t, user.ID, source.ID)
+
+	picture := []byte("picture data")
+	if err := AddPictureToRedis(strconv.Itoa(int(task.ID)), base64.StdEncoding.EncodeToString(picture)); err != nil {
+		t.Fatalf("uploading picture: %v", err)
+	}
+	t.Cleanup(func() { DeleteKeys(strconv.Itoa(int(task.ID))) })
+
+	copied_task, err := CopyTaskToWorkspace(user.ID, source.ID, task.ID, target.ID)
+	if err != nil {
+		t.Fatalf("copying task: %v", err)
+	}
+	t.Cleanup(func() { DeleteKeys(strconv.Itoa(int(copied_task.ID))) })
+
+	copied, err := RetrieveFile(strconv.Itoa(int(copied_task.ID)))
+	if err != nil {
+		t.Fatalf("retrieving picture of the copy: %v", err)
+	} else if !bytes.Equal(copied, picture) {
+		t.Errorf("copy has picture %q, want %q", copied, picture)
+	}
+}
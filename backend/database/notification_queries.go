@@ -0,0 +1,207 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func isNotificationEvent(event string) bool {
+	for _, item := range NotificationEvents {
+		if item == event {
+			return true
+		}
+	}
+	return false
+}
+
+func CreateNotifications(event string, user_ids []uint, message string) ([]uint, error) {
+	rows, err := DB.Query(`
+		INSERT INTO
+		Notification(user_id, event, message, is_read, created_at)
+		SELECT A.id, $2, $3, false, $4
+		FROM Users A
+		WHERE A.id = ANY($1::integer[]) AND NOT EXISTS (
+			SELECT 1
+			FROM NotificationPreference B
+			WHERE B.user_id = A.id AND B.event = $2 AND B.enabled = false
+		)
+		RETURNING user_id;`,
+		pq.Array(toInt64Array(uniqueIDs(user_ids))), event, message, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	recipients := []uint{}
+
+	for rows.Next() {
+		var user_id uint
+
+		if err := rows.Scan(&user_id); err != nil {
+			log.Println("Error:", err)
+			return []uint{}, custom_errors.ErrDatabaseFailure
+		}
+
+		recipients = append(recipients, user_id)
+	}
+
+	return recipients, nil
+}
+
+func GetNotificationsOfUser(requester_user_id uint, unread_only bool, page uint, per_page uint) (NotificationPage, error) {
+	notification_page := NotificationPage{
+		Notifications: []Notification{},
+		Page:          page,
+		PerPage:       per_page,
+	}
+
+	err := DB.QueryRow(`
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE is_read = false)
+		FROM Notification
+		WHERE user_id = $1;`,
+		requester_user_id).Scan(&notification_page.Total, &notification_page.UnreadCount)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return NotificationPage{}, custom_errors.ErrDatabaseFailure
+	}
+
+	if unread_only {
+		notification_page.Total = notification_page.UnreadCount
+	}
+
+	rows, err := DB.Query(`
+		SELECT *
+		FROM Notification
+		WHERE user_id = $1 AND ($2 = false OR is_read = false)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4;`,
+		requester_user_id, unread_only, per_page, (page-1)*per_page)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return NotificationPage{}, custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var notification Notification
+
+		if err := rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Event,
+			&notification.Message,
+			&notification.IsRead,
+			&notification.CreatedAt); err != nil {
+			log.Println("Error:", err)
+			return NotificationPage{}, custom_errors.ErrDatabaseFailure
+		}
+
+		notification_page.Notifications = append(notification_page.Notifications, notification)
+	}
+
+	return notification_page, nil
+}
+
+func MarkNotificationAsRead(requester_user_id uint, notification_id uint) error {
+	result, err := DB.Exec(`
+		UPDATE Notification
+		SET is_read = true
+		WHERE id = $1 AND user_id = $2;`,
+		notification_id, requester_user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
+
+func MarkAllNotificationsAsRead(requester_user_id uint) error {
+	_, err := DB.Exec(`
+		UPDATE Notification
+		SET is_read = true
+		WHERE user_id = $1 AND is_read = false;`,
+		requester_user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetNotificationPreferences(requester_user_id uint) ([]NotificationPreference, error) {
+	rows, err := DB.Query(`
+		SELECT event, enabled
+		FROM NotificationPreference
+		WHERE user_id = $1;`,
+		requester_user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []NotificationPreference{}, custom_errors.ErrDatabaseFailure
+	}
+
+	enabled_events := make(map[string]bool)
+	for _, event := range NotificationEvents {
+		enabled_events[event] = true
+	}
+
+	for rows.Next() {
+		var event string
+		var enabled bool
+
+		if err := rows.Scan(&event, &enabled); err != nil {
+			log.Println("Error:", err)
+			return []NotificationPreference{}, custom_errors.ErrDatabaseFailure
+		}
+
+		enabled_events[event] = enabled
+	}
+
+	preferences := []NotificationPreference{}
+	for _, event := range NotificationEvents {
+		preferences = append(preferences, NotificationPreference{Event: event, Enabled: enabled_events[event]})
+	}
+
+	return preferences, nil
+}
+
+func UpdateNotificationPreferences(requester_user_id uint, preferences []NotificationPreference) error {
+	for _, preference := range preferences {
+		if !isNotificationEvent(preference.Event) {
+			return custom_errors.ErrInvalidArguments
+		}
+	}
+
+	for _, preference := range preferences {
+		_, err := DB.Exec(`
+			INSERT INTO
+			NotificationPreference(user_id, event, enabled)
+			VALUES($1, $2, $3)
+			ON CONFLICT (user_id, event) DO UPDATE SET enabled = EXCLUDED.enabled;`,
+			requester_user_id, preference.Event, preference.Enabled)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	return nil
+}
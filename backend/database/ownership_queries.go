@@ -0,0 +1,353 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+const ownershipTransferValidPeriod = time.Hour * 24 * 7
+
+func scanOwnershipTransfer(row rowScanner) (OwnershipTransfer, error) {
+	var ownership_transfer OwnershipTransfer
+
+	err := row.Scan(
+		&ownership_transfer.ID,
+		&ownership_transfer.WorkspaceID,
+		&ownership_transfer.FromUserID,
+		&ownership_transfer.ToUserID,
+		&ownership_transfer.StayOwner,
+		&ownership_transfer.Status,
+		&ownership_transfer.ExpiresAt,
+		&ownership_transfer.CreatedAt,
+		&ownership_transfer.UpdatedAt,
+		&ownership_transfer.WorkspaceName)
+
+	return ownership_transfer, err
+}
+
+func getOwnershipTransfer(query string, args ...any) (OwnershipTransfer, error) {
+	ownership_transfer, err := scanOwnershipTransfer(DB.QueryRow(query, args...))
+
+	if err == sql.ErrNoRows {
+		return OwnershipTransfer{}, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return OwnershipTransfer{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return ownership_transfer, nil
+}
+
+func checkOwner(user_id uint, workspace_id uint) error {
+	user_role, err := getUserWorkspaceRole(user_id, workspace_id)
+	if err != nil {
+		return err
+	} else if user_role != Owner {
+		return custom_errors.ErrAccessDenied
+	}
+
+	return nil
+}
+
+// checkLastOwner refuses to let an owner go while nobody else owns the
+// workspace.
+func checkLastOwner(user_id uint, workspace_id uint) error {
+	var last_owner bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM UserWorkspaceRole
+			WHERE user_id = $1 AND workspace_id = $2 AND role = $3
+		) AND NOT EXISTS (
+			SELECT 1 FROM UserWorkspaceRole
+			WHERE user_id != $1 AND workspace_id = $2 AND role = $3
+		);`,
+		user_id, workspace_id, Owner).
+		Scan(&last_owner)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if last_owner {
+		return custom_errors.ErrLastOwner
+	}
+
+	return nil
+}
+
+// adoptOrphanedWorkspaces gives workspaces left without an owner, for example
+// by accounts deleted before ownership was safeguarded, a new one: the
+// longest-standing admin, or else the longest-standing member. Service
+// accounts never become owners.
+func adoptOrphanedWorkspaces() {
+	rows, err := DB.Query(`
+		UPDATE UserWorkspaceRole
+		SET role = $1, updated_at = $2
+		WHERE id IN (
+			SELECT DISTINCT ON (Candidate.workspace_id) Candidate.id
+			FROM UserWorkspaceRole AS Candidate
+			WHERE NOT EXISTS (
+				SELECT 1 FROM UserWorkspaceRole
+				WHERE workspace_id = Candidate.workspace_id AND role = $1
+			) AND NOT EXISTS (
+				SELECT 1 FROM ServiceAccount WHERE user_id = Candidate.user_id
+			)
+			ORDER BY Candidate.workspace_id, Candidate.role = $3 DESC, Candidate.created_at, Candidate.id
+		)
+		RETURNING workspace_id, user_id;`,
+		Owner, time.Now(), Admin)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return
+	}
+
+	for rows.Next() {
+		var workspace_id, user_id uint
+
+		if err := rows.Scan(&workspace_id, &user_id); err != nil {
+			log.Println("Error:", err)
+			return
+		}
+		log.Println("Warn: Workspace", workspace_id, "had no owner.", "Promoted user", user_id, "to owner.")
+	}
+}
+
+func GetOwnershipTransferInWorkspace(requester_user_id uint, workspace_id uint) (OwnershipTransfer, error) {
+	if err := checkOwner(requester_user_id, workspace_id); err != nil {
+		return OwnershipTransfer{}, err
+	}
+
+	return getOwnershipTransfer(`
+		SELECT OwnershipTransfer.*, Workspace.name
+		FROM OwnershipTransfer
+		JOIN Workspace ON Workspace.id = OwnershipTransfer.workspace_id
+		WHERE OwnershipTransfer.workspace_id = $1 AND OwnershipTransfer.status = $2 AND OwnershipTransfer.expires_at > $3;`,
+		workspace_id, PendingTransfer, time.Now())
+}
+
+// CreateOwnershipTransfer offers ownership of the workspace to another member,
+// who has to accept it. Only one offer is open per workspace, so a new one
+// replaces the previous. Unless stay_owner is set, the requester becomes an
+// Admin once the offer is accepted.
+func CreateOwnershipTransfer(requester_user_id uint, workspace_id uint, user_id uint, stay_owner bool) (OwnershipTransfer, error) {
+	if err := checkOwner(requester_user_id, workspace_id); err != nil {
+		return OwnershipTransfer{}, err
+	}
+
+	is_member, err := isWorkspaceMember(user_id, workspace_id)
+	if err != nil {
+		return OwnershipTransfer{}, err
+	} else if !is_member || user_id == requester_user_id {
+		return OwnershipTransfer{}, custom_errors.ErrInvalidArguments
+	}
+
+	target_user_role, err := getMembershipRole(user_id, workspace_id)
+	if err != nil {
+		return OwnershipTransfer{}, err
+	} else if target_user_role == Owner {
+		return OwnershipTransfer{}, custom_errors.ErrInvalidArguments
+	}
+
+	var is_service_account bool
+
+	err = DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM ServiceAccount WHERE user_id = $1
+		);`,
+		user_id).
+		Scan(&is_service_account)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return OwnershipTransfer{}, custom_errors.ErrDatabaseFailure
+	} else if is_service_account {
+		return OwnershipTransfer{}, custom_errors.ErrInvalidArguments
+	}
+
+	_, err = DB.Exec(`
+		UPDATE OwnershipTransfer
+		SET status = $1, updated_at = $2
+		WHERE workspace_id = $3 AND status = $4;`,
+		CancelledTransfer, time.Now(), workspace_id, PendingTransfer)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return OwnershipTransfer{}, custom_errors.ErrDatabaseFailure
+	}
+
+	var transfer_id uint
+
+	err = DB.QueryRow(`
+		INSERT INTO
+		OwnershipTransfer(workspace_id, from_user_id, to_user_id, stay_owner, status, expires_at, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id;`,
+		workspace_id, requester_user_id, user_id, stay_owner, PendingTransfer,
+		time.Now().Add(ownershipTransferValidPeriod), time.Now(), time.Now()).
+		Scan(&transfer_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return OwnershipTransfer{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return getOwnershipTransfer(`
+		SELECT OwnershipTransfer.*, Workspace.name
+		FROM OwnershipTransfer
+		JOIN Workspace ON Workspace.id = OwnershipTransfer.workspace_id
+		WHERE OwnershipTransfer.id = $1;`,
+		transfer_id)
+}
+
+func CancelOwnershipTransfer(requester_user_id uint, workspace_id uint) error {
+	if err := checkOwner(requester_user_id, workspace_id); err != nil {
+		return err
+	}
+
+	result, err := DB.Exec(`
+		UPDATE OwnershipTransfer
+		SET status = $1, updated_at = $2
+		WHERE workspace_id = $3 AND status = $4;`,
+		CancelledTransfer, time.Now(), workspace_id, PendingTransfer)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
+
+func GetOwnershipTransfersOfUser(requester_user_id uint) ([]OwnershipTransfer, error) {
+	rows, err := DB.Query(`
+		SELECT OwnershipTransfer.*, Workspace.name
+		FROM OwnershipTransfer
+		JOIN Workspace ON Workspace.id = OwnershipTransfer.workspace_id
+		WHERE OwnershipTransfer.to_user_id = $1 AND OwnershipTransfer.status = $2 AND OwnershipTransfer.expires_at > $3
+		ORDER BY OwnershipTransfer.id;`,
+		requester_user_id, PendingTransfer, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []OwnershipTransfer{}, custom_errors.ErrDatabaseFailure
+	}
+
+	ownership_transfers := []OwnershipTransfer{}
+
+	for rows.Next() {
+		if ownership_transfer, err := scanOwnershipTransfer(rows); err != nil {
+			log.Println("Error:", err)
+			return []OwnershipTransfer{}, custom_errors.ErrDatabaseFailure
+		} else {
+			ownership_transfers = append(ownership_transfers, ownership_transfer)
+		}
+	}
+
+	return ownership_transfers, nil
+}
+
+// AcceptOwnershipTransfer makes the requester an owner. The offer only holds
+// while its sender still owns the workspace.
+func AcceptOwnershipTransfer(requester_user_id uint, transfer_id uint) (OwnershipTransfer, error) {
+	ownership_transfer, err := getOwnershipTransfer(`
+		SELECT OwnershipTransfer.*, Workspace.name
+		FROM OwnershipTransfer
+		JOIN Workspace ON Workspace.id = OwnershipTransfer.workspace_id
+		WHERE OwnershipTransfer.id = $1 AND OwnershipTransfer.to_user_id = $2;`,
+		transfer_id, requester_user_id)
+
+	if err != nil {
+		return OwnershipTransfer{}, err
+	}
+
+	if _, err := getUserWorkspaceRole(requester_user_id, ownership_transfer.WorkspaceID); err != nil {
+		return OwnershipTransfer{}, err
+	}
+
+	sender_role, err := getMembershipRole(ownership_transfer.FromUserID, ownership_transfer.WorkspaceID)
+	if err != nil || sender_role != Owner {
+		return OwnershipTransfer{}, custom_errors.ErrInvalidArguments
+	}
+
+	result, err := DB.Exec(`
+		UPDATE OwnershipTransfer
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND expires_at > $2;`,
+		AcceptedTransfer, time.Now(), transfer_id, PendingTransfer)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return OwnershipTransfer{}, custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return OwnershipTransfer{}, custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return OwnershipTransfer{}, custom_errors.ErrInvalidArguments
+	}
+
+	_, err = DB.Exec(`
+		UPDATE UserWorkspaceRole
+		SET role = $1, updated_at = $2
+		WHERE user_id = $3 AND workspace_id = $4;`,
+		Owner, time.Now(), requester_user_id, ownership_transfer.WorkspaceID)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return OwnershipTransfer{}, custom_errors.ErrDatabaseFailure
+	}
+
+	if !ownership_transfer.StayOwner {
+		_, err = DB.Exec(`
+			UPDATE UserWorkspaceRole
+			SET role = $1, updated_at = $2
+			WHERE user_id = $3 AND workspace_id = $4;`,
+			Admin, time.Now(), ownership_transfer.FromUserID, ownership_transfer.WorkspaceID)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return OwnershipTransfer{}, custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	ownership_transfer.Status = AcceptedTransfer
+
+	return ownership_transfer, nil
+}
+
+func DeclineOwnershipTransfer(requester_user_id uint, transfer_id uint) error {
+	result, err := DB.Exec(`
+		UPDATE OwnershipTransfer
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND to_user_id = $4 AND status = $5;`,
+		DeclinedTransfer, time.Now(), transfer_id, requester_user_id, PendingTransfer)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
@@ -1,25 +1,36 @@
 package database
 
 import (
+	"database/sql"
 	"log"
 
+	"github.com/lib/pq"
 	"github.com/skye-tan/trello/backend/utils/custom_errors"
 )
 
+// GetComments lists the comments of the task. The comments of a trashed task
+// are hidden along with it.
 func GetComments(requester_user_id uint, task_id uint, workspace_id uint) ([]Comment, error) {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return []Comment{}, err
+	} else if actual_workspace_id != workspace_id {
+		return []Comment{}, custom_errors.ErrInvalidArguments
+	}
 
+	err = checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
 	if err != nil {
 		return []Comment{}, err
-	} else if user_role == NoRole {
-		return []Comment{}, custom_errors.ErrAccessDenied
 	}
 
 	rows, err := DB.Query(`
 		SELECT *
 		FROM Comment
-		WHERE task_id = $1;`,
-		task_id)
+		WHERE task_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $2 AND item_id = Comment.id
+		);`,
+		task_id, CommentItem)
 
 	if err != nil {
 		log.Println("Error:", err)
@@ -43,12 +54,9 @@ func GetComments(requester_user_id uint, task_id uint, workspace_id uint) ([]Com
 }
 
 func AddComment(requester_user_id uint, task_id uint, workspace_id uint, text string) (Comment, error) {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
-
+	err := checkPermission(requester_user_id, workspace_id, CommentCreatePermission)
 	if err != nil {
 		return Comment{}, err
-	} else if user_role == NoRole {
-		return Comment{}, custom_errors.ErrAccessDenied
 	}
 
 	var comment Comment
@@ -69,6 +77,43 @@ func AddComment(requester_user_id uint, task_id uint, workspace_id uint, text st
 	return comment, nil
 }
 
+func DeleteComment(requester_user_id uint, task_id uint, workspace_id uint, comment_id uint) error {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	var author_id uint
+
+	err = DB.QueryRow(`
+		SELECT user_id
+		FROM Comment
+		WHERE id = $1 AND task_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $3 AND item_id = Comment.id
+		);`,
+		comment_id, task_id, CommentItem).
+		Scan(&author_id)
+
+	if err == sql.ErrNoRows {
+		return custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	err = checkOwnPermission(requester_user_id, workspace_id, CommentDeleteAnyPermission, CommentDeleteOwnPermission, func() (bool, error) {
+		return author_id == requester_user_id, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return trashItem(requester_user_id, workspace_id, CommentItem, comment_id, task_id)
+}
+
 func GetAssociatedUsersWithTask(task_id uint) ([]uint, error) {
 	rows, err := DB.Query(`
 		SELECT B.user_id
@@ -156,12 +201,9 @@ func GetWorkspaceMembers(workspace_id uint) ([]uint, error) {
 }
 
 func GetWatch(requester_user_id uint, task_id uint, workspace_id uint) (string, error) {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
-
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
 	if err != nil {
 		return "", err
-	} else if user_role == NoRole {
-		return "", custom_errors.ErrAccessDenied
 	}
 
 	rows, err := DB.Query(`
@@ -187,12 +229,9 @@ func GetWatch(requester_user_id uint, task_id uint, workspace_id uint) (string,
 }
 
 func AddWatch(requester_user_id uint, task_id uint, workspace_id uint) error {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
-
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
 	if err != nil {
 		return err
-	} else if user_role == NoRole {
-		return custom_errors.ErrAccessDenied
 	}
 
 	_, err = DB.Exec(`
@@ -210,12 +249,9 @@ func AddWatch(requester_user_id uint, task_id uint, workspace_id uint) error {
 }
 
 func DeleteWatch(requester_user_id uint, task_id uint, workspace_id uint) error {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
-
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
 	if err != nil {
 		return err
-	} else if user_role == NoRole {
-		return custom_errors.ErrAccessDenied
 	}
 
 	_, err = DB.Exec(`
@@ -258,3 +294,32 @@ func GetWatchers(task_id uint) ([]uint, error) {
 
 	return watchers, nil
 }
+
+func GetMentionedUsersInWorkspace(workspace_id uint, usernames []string) ([]uint, error) {
+	rows, err := DB.Query(`
+		SELECT A.id
+		FROM Users A JOIN UserWorkspaceRole B
+		ON A.id = B.user_id
+		WHERE B.workspace_id = $1 AND A.username = ANY($2::varchar[]);`,
+		workspace_id, pq.Array(usernames))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	mentioned_users := []uint{}
+
+	for rows.Next() {
+		var mentioned_user uint
+
+		if err := rows.Scan(&mentioned_user); err != nil {
+			log.Println("Error:", err)
+			return []uint{}, custom_errors.ErrDatabaseFailure
+		} else {
+			mentioned_users = append(mentioned_users, mentioned_user)
+		}
+	}
+
+	return mentioned_users, nil
+}
@@ -0,0 +1,339 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	recurrence_utils "github.com/skye-tan/trello/backend/utils/recurrence"
+)
+
+func scanRecurringTask(row rowScanner) (RecurringTask, error) {
+	var recurring_task RecurringTask
+
+	err := row.Scan(
+		&recurring_task.ID,
+		&recurring_task.WorkspaceID,
+		&recurring_task.TemplateTaskID,
+		&recurring_task.Rule,
+		&recurring_task.StartAt,
+		&recurring_task.NextRunAt,
+		&recurring_task.IsPaused,
+		&recurring_task.CreatedBy,
+		&recurring_task.CreatedAt,
+		&recurring_task.UpdatedAt)
+
+	return recurring_task, err
+}
+
+func scanRecurringTasks(rows *sql.Rows) ([]RecurringTask, error) {
+	recurring_tasks := []RecurringTask{}
+
+	for rows.Next() {
+		if recurring_task, err := scanRecurringTask(rows); err != nil {
+			log.Println("Error:", err)
+			return []RecurringTask{}, custom_errors.ErrDatabaseFailure
+		} else {
+			recurring_tasks = append(recurring_tasks, recurring_task)
+		}
+	}
+
+	return recurring_tasks, nil
+}
+
+func GetRecurringTasksInWorkspace(requester_user_id uint, workspace_id uint) ([]RecurringTask, error) {
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return []RecurringTask{}, err
+	}
+
+	rows, err := DB.Query(`
+		SELECT *
+		FROM RecurringTask
+		WHERE workspace_id = $1;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []RecurringTask{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return scanRecurringTasks(rows)
+}
+
+func GetDetailsOfRecurringTask(requester_user_id uint, workspace_id uint, recurring_task_id uint) (RecurringTask, error) {
+	actual_workspace_id, err := getRecurringTaskWorkspaceID(recurring_task_id)
+	if err != nil {
+		return RecurringTask{}, err
+	} else if actual_workspace_id != workspace_id {
+		return RecurringTask{}, custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return RecurringTask{}, err
+	}
+
+	recurring_task, err := scanRecurringTask(DB.QueryRow(`
+		SELECT *
+		FROM RecurringTask
+		WHERE id = $1;`,
+		recurring_task_id))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return RecurringTask{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return recurring_task, nil
+}
+
+func CreateRecurringTask(requester_user_id uint, workspace_id uint, task_id uint, rule string, start_at time.Time) (RecurringTask, error) {
+	actual_workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return RecurringTask{}, err
+	} else if actual_workspace_id != workspace_id {
+		return RecurringTask{}, custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, RecurrenceManagePermission)
+	if err != nil {
+		return RecurringTask{}, err
+	}
+
+	parsed_rule, err := recurrence_utils.ParseRule(rule)
+	if err != nil {
+		return RecurringTask{}, custom_errors.ErrInvalidArguments
+	}
+
+	next_run_at := parsed_rule.Next(start_at, start_at.Add(-time.Nanosecond))
+
+	recurring_task, err := scanRecurringTask(DB.QueryRow(`
+		INSERT INTO
+		RecurringTask(workspace_id, template_task_id, rule, start_at, next_run_at, is_paused, created_by, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING *;`,
+		workspace_id, task_id, parsed_rule.String(), start_at, next_run_at,
+		false, requester_user_id, time.Now(), time.Now()))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return RecurringTask{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return recurring_task, nil
+}
+
+func UpdateRuleOfRecurringTask(requester_user_id uint, workspace_id uint, recurring_task_id uint, rule string) error {
+	recurring_task, err := GetDetailsOfRecurringTask(requester_user_id, workspace_id, recurring_task_id)
+	if err != nil {
+		return err
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, RecurrenceManagePermission)
+	if err != nil {
+		return err
+	}
+
+	parsed_rule, err := recurrence_utils.ParseRule(rule)
+	if err != nil {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	next_run_at := parsed_rule.Next(recurring_task.StartAt, time.Now())
+
+	_, err = DB.Exec(`
+		UPDATE RecurringTask
+		SET rule = $1, next_run_at = $2, updated_at = $3
+		WHERE id = $4;`,
+		parsed_rule.String(), next_run_at, time.Now(), recurring_task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func UpdatePauseOfRecurringTask(requester_user_id uint, workspace_id uint, recurring_task_id uint, is_paused bool) error {
+	recurring_task, err := GetDetailsOfRecurringTask(requester_user_id, workspace_id, recurring_task_id)
+	if err != nil {
+		return err
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, RecurrenceManagePermission)
+	if err != nil {
+		return err
+	}
+
+	next_run_at := recurring_task.NextRunAt
+	if !is_paused && recurring_task.IsPaused {
+		parsed_rule, err := recurrence_utils.ParseRule(recurring_task.Rule)
+		if err != nil {
+			return custom_errors.ErrInvalidArguments
+		}
+		next_run_at = parsed_rule.Next(recurring_task.StartAt, time.Now())
+	}
+
+	_, err = DB.Exec(`
+		UPDATE RecurringTask
+		SET is_paused = $1, next_run_at = $2, updated_at = $3
+		WHERE id = $4;`,
+		is_paused, next_run_at, time.Now(), recurring_task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func DeleteRecurringTask(requester_user_id uint, workspace_id uint, recurring_task_id uint) error {
+	actual_workspace_id, err := getRecurringTaskWorkspaceID(recurring_task_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, RecurrenceManagePermission)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM RecurringTask
+		WHERE id = $1;`,
+		recurring_task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetDueRecurringTasks(now time.Time) ([]RecurringTask, error) {
+	rows, err := DB.Query(`
+		SELECT *
+		FROM RecurringTask
+		WHERE is_paused = false AND next_run_at <= $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE (item_type = $2 AND item_id = RecurringTask.template_task_id) OR (item_type = $3 AND item_id = RecurringTask.workspace_id)
+		);`,
+		now, TaskItem, WorkspaceItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []RecurringTask{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return scanRecurringTasks(rows)
+}
+
+func ClaimRecurringTaskOccurrence(recurring_task_id uint, occurrence time.Time) (bool, error) {
+	result, err := DB.Exec(`
+		INSERT INTO
+		RecurringTaskInstance(recurring_task_id, occurrence)
+		VALUES($1, $2)
+		ON CONFLICT DO NOTHING;`,
+		recurring_task_id, occurrence)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return affected == 1, nil
+}
+
+func ReleaseRecurringTaskOccurrence(recurring_task_id uint, occurrence time.Time) error {
+	_, err := DB.Exec(`
+		DELETE FROM RecurringTaskInstance
+		WHERE recurring_task_id = $1 AND occurrence = $2 AND task_id IS NULL;`,
+		recurring_task_id, occurrence)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func CompleteRecurringTaskOccurrence(recurring_task_id uint, occurrence time.Time, task_id uint, next_run_at time.Time) error {
+	_, err := DB.Exec(`
+		UPDATE RecurringTaskInstance
+		SET task_id = $1
+		WHERE recurring_task_id = $2 AND occurrence = $3;`,
+		nullableID(task_id), recurring_task_id, occurrence)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return AdvanceRecurringTask(recurring_task_id, next_run_at)
+}
+
+func AdvanceRecurringTask(recurring_task_id uint, next_run_at time.Time) error {
+	_, err := DB.Exec(`
+		UPDATE RecurringTask
+		SET next_run_at = $1
+		WHERE id = $2;`,
+		next_run_at, recurring_task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func PauseRecurringTask(recurring_task_id uint) error {
+	_, err := DB.Exec(`
+		UPDATE RecurringTask
+		SET is_paused = true, updated_at = $1
+		WHERE id = $2;`,
+		time.Now(), recurring_task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+// IsRecurringTaskCreatorMember reports whether the creator of the recurring
+// task still belongs to its workspace, as occurrences are created on their
+// behalf.
+func IsRecurringTaskCreatorMember(recurring_task RecurringTask) (bool, error) {
+	var is_member bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM UserWorkspaceRole
+			WHERE user_id = $1 AND workspace_id = $2
+		);`,
+		recurring_task.CreatedBy, recurring_task.WorkspaceID).
+		Scan(&is_member)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return is_member, nil
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/base64"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/skye-tan/trello/backend/utils/custom_errors"
@@ -24,6 +26,46 @@ func AddPictureToRedis(task_id string, encoded string) error {
 	return nil
 }
 
+// CopyPicture gives the target task the picture uploaded for the source task,
+// if there is one.
+func CopyPicture(source_task_id uint, target_task_id uint) error {
+	err := rdb.Copy(ctx, strconv.Itoa(int(source_task_id)), strconv.Itoa(int(target_task_id)), 0, true).Err()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+// getPictures returns the base64 encoded pictures uploaded for the tasks that
+// have one.
+func getPictures(task_ids []uint) (map[uint]string, error) {
+	pictures := make(map[uint]string)
+	if len(task_ids) == 0 {
+		return pictures, nil
+	}
+
+	keys := []string{}
+	for _, task_id := range task_ids {
+		keys = append(keys, strconv.Itoa(int(task_id)))
+	}
+
+	values, err := rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Println("Error:", err)
+		return map[uint]string{}, custom_errors.ErrDatabaseFailure
+	}
+
+	for i, value := range values {
+		if encoded, ok := value.(string); ok && encoded != "" {
+			pictures[task_ids[i]] = encoded
+		}
+	}
+
+	return pictures, nil
+}
+
 func RetrieveFile(task_id string) ([]byte, error) {
 	encoded, err := rdb.Get(ctx, task_id).Result()
 	if err != nil {
@@ -38,6 +80,86 @@ func RetrieveFile(task_id string) ([]byte, error) {
 	return decoded, nil
 }
 
+func SaveOIDCState(state string, value string, ttl time.Duration) error {
+	err := rdb.Set(ctx, "oidc_state:"+state, value, ttl).Err()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func TakeOIDCState(state string) (string, error) {
+	value, err := rdb.GetDel(ctx, "oidc_state:"+state).Result()
+	if err == redis.Nil {
+		return "", custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return "", custom_errors.ErrDatabaseFailure
+	}
+
+	return value, nil
+}
+
+// IncrementRateCounter counts a hit in a fixed window starting at the first
+// hit and returns the count so far together with the time left in the window.
+func IncrementRateCounter(key string, window time.Duration) (int64, time.Duration, error) {
+	count, err := rdb.Incr(ctx, key).Result()
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, 0, custom_errors.ErrDatabaseFailure
+	}
+
+	if count == 1 {
+		rdb.PExpire(ctx, key, window)
+		return count, window, nil
+	}
+
+	ttl, err := rdb.PTTL(ctx, key).Result()
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, 0, custom_errors.ErrDatabaseFailure
+	} else if ttl < 0 {
+		rdb.PExpire(ctx, key, window)
+		ttl = window
+	}
+
+	return count, ttl, nil
+}
+
+func SetExpiringKey(key string, ttl time.Duration) error {
+	err := rdb.Set(ctx, key, 1, ttl).Err()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetKeyTTL(key string) (time.Duration, error) {
+	ttl, err := rdb.PTTL(ctx, key).Result()
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	} else if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func DeleteKeys(keys ...string) error {
+	err := rdb.Del(ctx, keys...).Err()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
 func RedisInitial() {
 	redisAddr := os.Getenv("REDIS_ADDR")
 	if redisAddr == "" {
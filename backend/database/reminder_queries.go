@@ -0,0 +1,139 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func GetOverdueTasks(requester_user_id uint) ([]Task, error) {
+	rows, err := DB.Query(`
+		SELECT *
+		FROM Task
+		WHERE due_date <= $1 AND status != $2 AND id IN (
+			SELECT task_id
+			FROM TaskAssignee
+			WHERE user_id = $3
+		) AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE (item_type = $4 AND item_id = Task.id) OR (item_type = $5 AND item_id = Task.workspace_id)
+		) AND NOT EXISTS (
+			SELECT 1 FROM TaskArchive
+			WHERE task_id = Task.id
+		)
+		ORDER BY due_date;`,
+		time.Now(), Completed, requester_user_id, TaskItem, WorkspaceItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []Task{}, custom_errors.ErrDatabaseFailure
+	}
+
+	tasks := []Task{}
+
+	for rows.Next() {
+		if task, err := scanTask(rows); err != nil {
+			log.Println("Error:", err)
+			return []Task{}, custom_errors.ErrDatabaseFailure
+		} else {
+			tasks = append(tasks, task)
+		}
+	}
+
+	if err := attachTaskAssignees(tasks); err != nil {
+		return []Task{}, err
+	}
+
+	return tasks, nil
+}
+
+func GetTasksForReminder(kind string, from time.Time, to time.Time) ([]Task, error) {
+	rows, err := DB.Query(`
+		SELECT *
+		FROM Task A
+		WHERE A.due_date > $1 AND A.due_date <= $2 AND A.status != $3 AND NOT EXISTS (
+			SELECT 1
+			FROM TaskReminder B
+			WHERE B.task_id = A.id AND B.kind = $4 AND B.due_date = A.due_date
+		) AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE (item_type = $5 AND item_id = A.id) OR (item_type = $6 AND item_id = A.workspace_id)
+		) AND NOT EXISTS (
+			SELECT 1 FROM TaskArchive
+			WHERE task_id = A.id
+		);`,
+		from, to, Completed, kind, TaskItem, WorkspaceItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []Task{}, custom_errors.ErrDatabaseFailure
+	}
+
+	tasks := []Task{}
+
+	for rows.Next() {
+		if task, err := scanTask(rows); err != nil {
+			log.Println("Error:", err)
+			return []Task{}, custom_errors.ErrDatabaseFailure
+		} else {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, nil
+}
+
+func ClaimTaskReminder(task_id uint, kind string, due_date time.Time) (bool, error) {
+	result, err := DB.Exec(`
+		INSERT INTO
+		TaskReminder(task_id, kind, due_date, sent_at)
+		VALUES($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING;`,
+		task_id, kind, due_date, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return affected == 1, nil
+}
+
+func GetTaskReminderRecipients(task_id uint) ([]uint, error) {
+	rows, err := DB.Query(`
+		SELECT user_id
+		FROM TaskAssignee
+		WHERE task_id = $1
+		UNION
+		SELECT user_id
+		FROM Watch
+		WHERE task_id = $1;`,
+		task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []uint{}, custom_errors.ErrDatabaseFailure
+	}
+
+	recipients := []uint{}
+
+	for rows.Next() {
+		var user_id uint
+
+		if err := rows.Scan(&user_id); err != nil {
+			log.Println("Error:", err)
+			return []uint{}, custom_errors.ErrDatabaseFailure
+		}
+
+		recipients = append(recipients, user_id)
+	}
+
+	return recipients, nil
+}
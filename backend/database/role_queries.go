@@ -0,0 +1,394 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+type permissionSet map[string]bool
+
+func newPermissionSet(permissions []string) permissionSet {
+	permission_set := make(permissionSet)
+	for _, permission := range permissions {
+		permission_set[permission] = true
+	}
+	return permission_set
+}
+
+func (permission_set permissionSet) has(permission string) bool {
+	return permission_set[permission]
+}
+
+func (permission_set permissionSet) includes(other permissionSet) bool {
+	for permission := range other {
+		if !permission_set[permission] {
+			return false
+		}
+	}
+	return true
+}
+
+// exceeds reports whether the set holds every permission of the other one
+// and at least one more.
+func (permission_set permissionSet) exceeds(other permissionSet) bool {
+	return len(permission_set) > len(other) && permission_set.includes(other)
+}
+
+func isPermission(permission string) bool {
+	for _, item := range Permissions {
+		if item == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePermissions validates the permissions and returns them without
+// duplicates, in the order they are declared in.
+func normalizePermissions(permissions []string) ([]string, bool) {
+	permission_set := newPermissionSet(permissions)
+	for permission := range permission_set {
+		if !isPermission(permission) {
+			return []string{}, false
+		}
+	}
+
+	normalized := []string{}
+	for _, permission := range Permissions {
+		if permission_set.has(permission) {
+			normalized = append(normalized, permission)
+		}
+	}
+	return normalized, true
+}
+
+func isBuiltinRole(role string) bool {
+	for builtin_role := range BuiltinRoles {
+		if strings.EqualFold(builtin_role, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func scanCustomRole(row rowScanner) (CustomRole, error) {
+	var custom_role CustomRole
+	var created_by sql.NullInt64
+
+	err := row.Scan(
+		&custom_role.ID,
+		&custom_role.WorkspaceID,
+		&custom_role.Name,
+		pq.Array(&custom_role.Permissions),
+		&created_by,
+		&custom_role.CreatedAt,
+		&custom_role.UpdatedAt)
+
+	custom_role.CreatedBy = uint(created_by.Int64)
+
+	return custom_role, err
+}
+
+func getCustomRole(workspace_id uint, role_id uint) (CustomRole, error) {
+	custom_role, err := scanCustomRole(DB.QueryRow(`
+		SELECT *
+		FROM CustomRole
+		WHERE id = $1 AND workspace_id = $2;`,
+		role_id, workspace_id))
+
+	if err == sql.ErrNoRows {
+		return CustomRole{}, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return CustomRole{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return custom_role, nil
+}
+
+// getRolePermissions resolves a built-in role or a custom role defined in the
+// workspace to its permissions.
+func getRolePermissions(workspace_id uint, role string) (permissionSet, error) {
+	if permissions, ok := BuiltinRoles[role]; ok {
+		return newPermissionSet(permissions), nil
+	}
+
+	var permissions []string
+
+	err := DB.QueryRow(`
+		SELECT permissions
+		FROM CustomRole
+		WHERE workspace_id = $1 AND name = $2;`,
+		workspace_id, role).
+		Scan(pq.Array(&permissions))
+
+	if err == sql.ErrNoRows {
+		return permissionSet{}, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return permissionSet{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return newPermissionSet(permissions), nil
+}
+
+// getUserPermissions returns the role the user acts with in the workspace
+// together with the permissions it grants. Workspaces in the trash grant
+// nothing until they are restored.
+func getUserPermissions(user_id uint, workspace_id uint) (string, permissionSet, error) {
+	trashed, err := isTrashed(WorkspaceItem, workspace_id)
+	if err != nil {
+		return NoRole, permissionSet{}, err
+	} else if trashed {
+		return NoRole, permissionSet{}, custom_errors.ErrInvalidArguments
+	}
+
+	user_role, err := getUserWorkspaceRole(user_id, workspace_id)
+	if err != nil {
+		return NoRole, permissionSet{}, err
+	} else if user_role == NoRole {
+		return NoRole, permissionSet{}, nil
+	}
+
+	permissions, err := getRolePermissions(workspace_id, user_role)
+	if err == custom_errors.ErrInvalidArguments {
+		return user_role, permissionSet{}, nil
+	} else if err != nil {
+		return NoRole, permissionSet{}, err
+	}
+
+	return user_role, permissions, nil
+}
+
+func checkPermission(user_id uint, workspace_id uint, permission string) error {
+	_, permissions, err := getUserPermissions(user_id, workspace_id)
+	if err != nil {
+		return err
+	} else if !permissions.has(permission) {
+		return custom_errors.ErrAccessDenied
+	}
+
+	return nil
+}
+
+// checkOwnPermission allows the action with the permission covering any
+// resource, or with the narrower one when is_own confirms the resource
+// belongs to the user.
+func checkOwnPermission(user_id uint, workspace_id uint, any_permission string, own_permission string, is_own func() (bool, error)) error {
+	_, permissions, err := getUserPermissions(user_id, workspace_id)
+	if err != nil {
+		return err
+	} else if permissions.has(any_permission) {
+		return nil
+	} else if !permissions.has(own_permission) {
+		return custom_errors.ErrAccessDenied
+	}
+
+	own, err := is_own()
+	if err != nil {
+		return err
+	} else if !own {
+		return custom_errors.ErrAccessDenied
+	}
+
+	return nil
+}
+
+// checkRoleAuthority decides whether a member may grant the target role or act
+// on members holding it. The Owner role is never handed out this way, and
+// everyone but the Owner is limited to roles strictly weaker than their own.
+func checkRoleAuthority(requester_role string, requester_permissions permissionSet, workspace_id uint, target_role string) error {
+	if target_role == Owner {
+		return custom_errors.ErrAccessDenied
+	}
+
+	target_permissions, err := getRolePermissions(workspace_id, target_role)
+	if err != nil {
+		return err
+	}
+
+	if requester_role != Owner && !requester_permissions.exceeds(target_permissions) {
+		return custom_errors.ErrAccessDenied
+	}
+
+	return nil
+}
+
+func GetRolesInWorkspace(requester_user_id uint, workspace_id uint) ([]CustomRole, error) {
+	if err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission); err != nil {
+		return []CustomRole{}, err
+	}
+
+	roles := []CustomRole{}
+	for _, role := range []string{Owner, Admin, StandardUser} {
+		roles = append(roles, CustomRole{
+			WorkspaceID: workspace_id,
+			Name:        role,
+			Permissions: BuiltinRoles[role],
+			IsBuiltin:   true,
+		})
+	}
+
+	rows, err := DB.Query(`
+		SELECT *
+		FROM CustomRole
+		WHERE workspace_id = $1
+		ORDER BY id;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []CustomRole{}, custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		if custom_role, err := scanCustomRole(rows); err != nil {
+			log.Println("Error:", err)
+			return []CustomRole{}, custom_errors.ErrDatabaseFailure
+		} else {
+			roles = append(roles, custom_role)
+		}
+	}
+
+	return roles, nil
+}
+
+// CreateCustomRole defines a workspace role from a set of permissions. Members
+// cannot define roles holding permissions they lack themselves.
+func CreateCustomRole(requester_user_id uint, workspace_id uint, name string, permissions []string) (CustomRole, error) {
+	_, requester_permissions, err := getUserPermissions(requester_user_id, workspace_id)
+	if err != nil {
+		return CustomRole{}, err
+	} else if !requester_permissions.has(RoleManagePermission) {
+		return CustomRole{}, custom_errors.ErrAccessDenied
+	}
+
+	permissions, ok := normalizePermissions(permissions)
+	if !ok || isBuiltinRole(name) {
+		return CustomRole{}, custom_errors.ErrInvalidArguments
+	} else if !requester_permissions.includes(newPermissionSet(permissions)) {
+		return CustomRole{}, custom_errors.ErrAccessDenied
+	}
+
+	var duplicate bool
+
+	err = DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM CustomRole
+			WHERE workspace_id = $1 AND LOWER(name) = LOWER($2)
+		);`,
+		workspace_id, name).
+		Scan(&duplicate)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return CustomRole{}, custom_errors.ErrDatabaseFailure
+	} else if duplicate {
+		return CustomRole{}, custom_errors.ErrDuplicateRoleName
+	}
+
+	custom_role, err := scanCustomRole(DB.QueryRow(`
+		INSERT INTO
+		CustomRole(workspace_id, name, permissions, created_by, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6)
+		RETURNING *;`,
+		workspace_id, name, pq.Array(permissions), requester_user_id, time.Now(), time.Now()))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return CustomRole{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return custom_role, nil
+}
+
+func UpdateCustomRole(requester_user_id uint, workspace_id uint, role_id uint, permissions []string) error {
+	_, requester_permissions, err := getUserPermissions(requester_user_id, workspace_id)
+	if err != nil {
+		return err
+	} else if !requester_permissions.has(RoleManagePermission) {
+		return custom_errors.ErrAccessDenied
+	}
+
+	custom_role, err := getCustomRole(workspace_id, role_id)
+	if err != nil {
+		return err
+	}
+
+	permissions, ok := normalizePermissions(permissions)
+	if !ok {
+		return custom_errors.ErrInvalidArguments
+	} else if !requester_permissions.includes(newPermissionSet(custom_role.Permissions)) ||
+		!requester_permissions.includes(newPermissionSet(permissions)) {
+		return custom_errors.ErrAccessDenied
+	}
+
+	_, err = DB.Exec(`
+		UPDATE CustomRole
+		SET permissions = $1, updated_at = $2
+		WHERE id = $3;`,
+		pq.Array(permissions), time.Now(), role_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+// DeleteCustomRole removes a role nobody holds anymore, including through
+// pending invitations, so no member is left with an unknown role.
+func DeleteCustomRole(requester_user_id uint, workspace_id uint, role_id uint) error {
+	_, requester_permissions, err := getUserPermissions(requester_user_id, workspace_id)
+	if err != nil {
+		return err
+	} else if !requester_permissions.has(RoleManagePermission) {
+		return custom_errors.ErrAccessDenied
+	}
+
+	custom_role, err := getCustomRole(workspace_id, role_id)
+	if err != nil {
+		return err
+	} else if !requester_permissions.includes(newPermissionSet(custom_role.Permissions)) {
+		return custom_errors.ErrAccessDenied
+	}
+
+	var in_use bool
+
+	err = DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM UserWorkspaceRole
+			WHERE workspace_id = $1 AND role = $2
+		) OR EXISTS (
+			SELECT 1 FROM Invitation
+			WHERE workspace_id = $1 AND role = $2 AND status = $3 AND expires_at > $4
+		);`,
+		workspace_id, custom_role.Name, PendingInvitation, time.Now()).
+		Scan(&in_use)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if in_use {
+		return custom_errors.ErrRoleInUse
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM CustomRole
+		WHERE id = $1;`,
+		role_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func scanServiceAccount(row rowScanner) (ServiceAccount, error) {
+	var service_account ServiceAccount
+	var created_by sql.NullInt64
+
+	err := row.Scan(
+		&service_account.UserID,
+		&service_account.WorkspaceID,
+		&service_account.Name,
+		&created_by,
+		&service_account.CreatedAt,
+		&service_account.Username,
+		&service_account.Role)
+
+	service_account.CreatedBy = uint(created_by.Int64)
+
+	return service_account, err
+}
+
+func checkServiceAccountOwnership(requester_user_id uint, workspace_id uint, service_account_id uint) error {
+	actual_workspace_id, err := getServiceAccountWorkspaceID(service_account_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, ServiceAccountManagePermission)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetServiceAccountsInWorkspace(requester_user_id uint, workspace_id uint) ([]ServiceAccount, error) {
+	err := checkPermission(requester_user_id, workspace_id, ServiceAccountViewPermission)
+	if err != nil {
+		return []ServiceAccount{}, err
+	}
+
+	rows, err := DB.Query(`
+		SELECT ServiceAccount.*, Users.username, COALESCE(UserWorkspaceRole.role, '')
+		FROM ServiceAccount
+		JOIN Users ON Users.id = ServiceAccount.user_id
+		LEFT JOIN UserWorkspaceRole ON UserWorkspaceRole.user_id = ServiceAccount.user_id
+			AND UserWorkspaceRole.workspace_id = ServiceAccount.workspace_id
+		WHERE ServiceAccount.workspace_id = $1
+		ORDER BY ServiceAccount.user_id;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []ServiceAccount{}, custom_errors.ErrDatabaseFailure
+	}
+
+	service_accounts := []ServiceAccount{}
+
+	for rows.Next() {
+		if service_account, err := scanServiceAccount(rows); err != nil {
+			log.Println("Error:", err)
+			return []ServiceAccount{}, custom_errors.ErrDatabaseFailure
+		} else {
+			service_accounts = append(service_accounts, service_account)
+		}
+	}
+
+	return service_accounts, nil
+}
+
+// CreateServiceAccountInWorkspace registers a password-less user that can
+// only authenticate with personal access tokens and joins it to the workspace.
+func CreateServiceAccountInWorkspace(requester_user_id uint, workspace_id uint, name string, role string) (ServiceAccount, error) {
+	user_role, permissions, err := getUserPermissions(requester_user_id, workspace_id)
+	if err != nil {
+		return ServiceAccount{}, err
+	} else if !permissions.has(ServiceAccountManagePermission) {
+		return ServiceAccount{}, custom_errors.ErrAccessDenied
+	}
+
+	if err := checkRoleAuthority(user_role, permissions, workspace_id, role); err != nil {
+		return ServiceAccount{}, err
+	}
+
+	suffix, err := generateSecretToken()
+	if err != nil {
+		log.Println("Error:", err)
+		return ServiceAccount{}, custom_errors.ErrDatabaseFailure
+	}
+
+	username := "svc_" + suffix[:8]
+	email := username + "@service.invalid"
+
+	var user_id uint
+
+	err = DB.QueryRow(`
+		INSERT INTO
+		Users(username, email, created_at, updated_at)
+		VALUES($1, $2, $3, $4)
+		RETURNING id;`,
+		username, email, time.Now(), time.Now()).Scan(&user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return ServiceAccount{}, custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		UserWorkspaceRole(user_id, workspace_id, role, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5);`,
+		user_id, workspace_id, role, time.Now(), time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return ServiceAccount{}, custom_errors.ErrDatabaseFailure
+	}
+
+	service_account, err := scanServiceAccount(DB.QueryRow(`
+		INSERT INTO
+		ServiceAccount(user_id, workspace_id, name, created_by, created_at)
+		VALUES($1, $2, $3, $4, $5)
+		RETURNING *, $6::varchar, $7::varchar;`,
+		user_id, workspace_id, name, requester_user_id, time.Now(), username, role))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return ServiceAccount{}, custom_errors.ErrDatabaseFailure
+	}
+
+	if err := UpdateEmailSettings(user_id, NoEmail); err != nil {
+		return ServiceAccount{}, err
+	}
+
+	return service_account, nil
+}
+
+func DeleteServiceAccount(requester_user_id uint, workspace_id uint, service_account_id uint) error {
+	if err := checkServiceAccountOwnership(requester_user_id, workspace_id, service_account_id); err != nil {
+		return err
+	}
+
+	_, err := DB.Exec(`
+		DELETE FROM Users
+		WHERE id = $1;`,
+		service_account_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetServiceAccountTokens(requester_user_id uint, workspace_id uint, service_account_id uint) ([]PersonalAccessToken, error) {
+	if err := checkServiceAccountOwnership(requester_user_id, workspace_id, service_account_id); err != nil {
+		return []PersonalAccessToken{}, err
+	}
+
+	return getPersonalAccessTokens(service_account_id)
+}
+
+func CreateServiceAccountToken(requester_user_id uint, workspace_id uint, service_account_id uint, name string, scopes []string, expires_at time.Time) (PersonalAccessToken, error) {
+	if err := checkServiceAccountOwnership(requester_user_id, workspace_id, service_account_id); err != nil {
+		return PersonalAccessToken{}, err
+	}
+
+	return createPersonalAccessToken(service_account_id, name, scopes, expires_at)
+}
+
+func DeleteServiceAccountToken(requester_user_id uint, workspace_id uint, service_account_id uint, token_id uint) error {
+	if err := checkServiceAccountOwnership(requester_user_id, workspace_id, service_account_id); err != nil {
+		return err
+	}
+
+	return deletePersonalAccessToken(service_account_id, token_id)
+}
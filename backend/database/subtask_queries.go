@@ -1,30 +1,86 @@
 package database
 
 import (
+	"database/sql"
 	"log"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/skye-tan/trello/backend/utils/custom_errors"
 )
 
+func scanSubtask(row rowScanner) (Subtask, error) {
+	var subtask Subtask
+	var assignee_id sql.NullInt64
+
+	err := row.Scan(
+		&subtask.ID,
+		&subtask.TaskID,
+		&subtask.Title,
+		&subtask.IsCompleted,
+		&assignee_id,
+		&subtask.CreatedAt,
+		&subtask.UpdatedAt)
+
+	subtask.AssigneeID = uint(assignee_id.Int64)
+	subtask.AssigneeIDs = []uint{}
+
+	return subtask, err
+}
+
+func attachSubtaskAssignees(subtasks []Subtask) error {
+	subtask_ids := make([]int64, len(subtasks))
+	subtask_indexes := make(map[uint]int)
+	for i, subtask := range subtasks {
+		subtask_ids[i] = int64(subtask.ID)
+		subtask_indexes[subtask.ID] = i
+	}
+
+	rows, err := DB.Query(`
+		SELECT subtask_id, user_id
+		FROM SubtaskAssignee
+		WHERE subtask_id = ANY($1::integer[]);`,
+		pq.Array(subtask_ids))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var subtask_id, user_id uint
+
+		if err := rows.Scan(&subtask_id, &user_id); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		i := subtask_indexes[subtask_id]
+		subtasks[i].AssigneeIDs = append(subtasks[i].AssigneeIDs, user_id)
+	}
+
+	return nil
+}
+
 func GetAllSubtasksInTask(requester_user_id uint, task_id uint) ([]Subtask, error) {
 	workspace_id, err := getTaskWorkspaceID(task_id)
 	if err != nil {
 		return []Subtask{}, custom_errors.ErrDatabaseFailure
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err = checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
 	if err != nil {
 		return []Subtask{}, err
-	} else if user_role == NoRole {
-		return []Subtask{}, custom_errors.ErrAccessDenied
 	}
 
 	rows, err := DB.Query(`
 		SELECT * 
 		FROM Subtask
-		WHERE task_id = $1;`,
-		task_id)
+		WHERE task_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $2 AND item_id = Subtask.id
+		);`,
+		task_id, SubtaskItem)
 
 	if err != nil {
 		log.Println("Error:", err)
@@ -34,16 +90,7 @@ func GetAllSubtasksInTask(requester_user_id uint, task_id uint) ([]Subtask, erro
 	var subtasks []Subtask
 
 	for rows.Next() {
-		var subtask Subtask
-
-		if err := rows.Scan(
-			&subtask.ID,
-			&subtask.TaskID,
-			&subtask.Title,
-			&subtask.IsCompleted,
-			&subtask.AssigneeID,
-			&subtask.CreatedAt,
-			&subtask.UpdatedAt); err != nil {
+		if subtask, err := scanSubtask(rows); err != nil {
 			log.Println("Error:", err)
 			return []Subtask{}, custom_errors.ErrDatabaseFailure
 		} else {
@@ -51,48 +98,70 @@ func GetAllSubtasksInTask(requester_user_id uint, task_id uint) ([]Subtask, erro
 		}
 	}
 
+	if err := attachSubtaskAssignees(subtasks); err != nil {
+		return []Subtask{}, err
+	}
+
 	return subtasks, nil
 }
 
-func CreateSubtaskInTask(requester_user_id uint, task_id uint, title string, assignee_id uint) (Subtask, error) {
+func CreateSubtaskInTask(requester_user_id uint, task_id uint, title string, assignee_ids []uint) (Subtask, error) {
 	workspace_id, err := getTaskWorkspaceID(task_id)
 	if err != nil {
 		return Subtask{}, custom_errors.ErrDatabaseFailure
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err = checkPermission(requester_user_id, workspace_id, SubtaskCreatePermission)
 	if err != nil {
 		return Subtask{}, err
-	} else if user_role != Admin && user_role != Owner {
-		return Subtask{}, custom_errors.ErrAccessDenied
+	}
+
+	assignee_ids = uniqueIDs(assignee_ids)
+	for _, assignee_id := range assignee_ids {
+		assignee_user_role, err := getMembershipRole(assignee_id, workspace_id)
+		if err != nil {
+			return Subtask{}, err
+		} else if assignee_user_role == NoRole {
+			return Subtask{}, custom_errors.ErrInvalidArguments
+		}
 	}
 
 	if ok := checkDuplicateSubtaskTitle(task_id, title); !ok {
 		return Subtask{}, custom_errors.ErrDuplicateSubtaskTitle
 	}
 
-	var subtask Subtask
+	var primary_assignee_id uint
+	if len(assignee_ids) != 0 {
+		primary_assignee_id = assignee_ids[0]
+	}
 
-	err = DB.QueryRow(`
+	subtask, err := scanSubtask(DB.QueryRow(`
 		INSERT INTO
 		Subtask(task_id,title,is_completed,assignee_id,created_at,updated_at)
 		VALUES($1, $2, $3, $4, $5, $6)
 		RETURNING *;`,
-		task_id, title, No, assignee_id,
-		time.Now(), time.Now()).
-		Scan(&subtask.ID,
-			&subtask.TaskID,
-			&subtask.Title,
-			&subtask.IsCompleted,
-			&subtask.AssigneeID,
-			&subtask.CreatedAt,
-			&subtask.UpdatedAt)
+		task_id, title, No, nullableID(primary_assignee_id),
+		time.Now(), time.Now()))
 
 	if nil != err {
 		log.Println("Error:", err)
 		return Subtask{}, custom_errors.ErrDatabaseFailure
 	}
 
+	_, err = DB.Exec(`
+		INSERT INTO
+		SubtaskAssignee(subtask_id, user_id)
+		SELECT $1, unnest($2::integer[])
+		ON CONFLICT DO NOTHING;`,
+		subtask.ID, pq.Array(toInt64Array(assignee_ids)))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Subtask{}, custom_errors.ErrDatabaseFailure
+	}
+
+	subtask.AssigneeIDs = assignee_ids
+
 	return subtask, nil
 }
 
@@ -102,33 +171,30 @@ func GetDetailsOfSubtask(requester_user_id uint, task_id uint, subtask_id uint)
 		return Subtask{}, custom_errors.ErrDatabaseFailure
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err = checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
 	if err != nil {
 		return Subtask{}, err
-	} else if user_role == NoRole {
-		return Subtask{}, custom_errors.ErrAccessDenied
 	}
 
-	var subtask Subtask
-
-	err = DB.QueryRow(`
+	subtask, err := scanSubtask(DB.QueryRow(`
 		SELECT * 
 		FROM Subtask
-		WHERE id = $1;`,
-		subtask_id).
-		Scan(&subtask.ID,
-			&subtask.TaskID,
-			&subtask.Title,
-			&subtask.IsCompleted,
-			&subtask.AssigneeID,
-			&subtask.CreatedAt,
-			&subtask.UpdatedAt)
+		WHERE id = $1 AND task_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $3 AND item_id = Subtask.id
+		);`,
+		subtask_id, task_id, SubtaskItem))
 
 	if err != nil {
 		log.Println("Error:", err)
 		return Subtask{}, custom_errors.ErrDatabaseFailure
 	}
 
+	subtask.AssigneeIDs, err = GetSubtaskAssignees(subtask_id)
+	if err != nil {
+		return Subtask{}, err
+	}
+
 	return subtask, nil
 }
 
@@ -138,11 +204,17 @@ func UpdateDetailsOfSubtask(requester_user_id uint, task_id uint, subtask_id uin
 		return custom_errors.ErrDatabaseFailure
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	if err := checkSubtaskInTask(subtask_id, task_id); err != nil {
+		return err
+	}
+
+	err = checkSubtaskPermission(requester_user_id, workspace_id, subtask_id, SubtaskUpdateAnyPermission, SubtaskUpdateOwnPermission)
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
-		return custom_errors.ErrAccessDenied
+	}
+
+	if err := checkSubtaskReassignment(requester_user_id, workspace_id, subtask_id, assignee_id); err != nil {
+		return err
 	}
 
 	if ok := checkDuplicateSubtaskTitle(task_id, title); !ok {
@@ -161,6 +233,18 @@ func UpdateDetailsOfSubtask(requester_user_id uint, task_id uint, subtask_id uin
 		return custom_errors.ErrDatabaseFailure
 	}
 
+	_, err = DB.Exec(`
+		INSERT INTO
+		SubtaskAssignee(subtask_id, user_id)
+		VALUES($1, $2)
+		ON CONFLICT DO NOTHING;`,
+		subtask_id, assignee_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
 	return nil
 }
 func UpdateDetailsOfSubtaskAssigneeID(requester_user_id uint, task_id uint, subtask_id uint, assignee_id uint) error {
@@ -169,11 +253,13 @@ func UpdateDetailsOfSubtaskAssigneeID(requester_user_id uint, task_id uint, subt
 		return custom_errors.ErrDatabaseFailure
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	if err := checkSubtaskInTask(subtask_id, task_id); err != nil {
+		return err
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, SubtaskAssignPermission)
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
-		return custom_errors.ErrAccessDenied
 	}
 
 	_, err = DB.Exec(`
@@ -187,6 +273,18 @@ func UpdateDetailsOfSubtaskAssigneeID(requester_user_id uint, task_id uint, subt
 		return custom_errors.ErrDatabaseFailure
 	}
 
+	_, err = DB.Exec(`
+		INSERT INTO
+		SubtaskAssignee(subtask_id, user_id)
+		VALUES($1, $2)
+		ON CONFLICT DO NOTHING;`,
+		subtask_id, assignee_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
 	return nil
 }
 
@@ -196,11 +294,13 @@ func UpdateDetailsOfSubtaskTitle(requester_user_id uint, task_id uint, subtask_i
 		return custom_errors.ErrDatabaseFailure
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	if err := checkSubtaskInTask(subtask_id, task_id); err != nil {
+		return err
+	}
+
+	err = checkSubtaskPermission(requester_user_id, workspace_id, subtask_id, SubtaskUpdateAnyPermission, SubtaskUpdateOwnPermission)
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
-		return custom_errors.ErrAccessDenied
 	}
 
 	if ok := checkDuplicateSubtaskTitle(task_id, title); !ok {
@@ -227,11 +327,13 @@ func UpdateDetailsOfSubtaskStatus(requester_user_id uint, task_id uint, subtask_
 		return custom_errors.ErrDatabaseFailure
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	if err := checkSubtaskInTask(subtask_id, task_id); err != nil {
+		return err
+	}
+
+	err = checkSubtaskPermission(requester_user_id, workspace_id, subtask_id, SubtaskUpdateAnyPermission, SubtaskUpdateOwnPermission)
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
-		return custom_errors.ErrAccessDenied
 	}
 
 	_, err = DB.Exec(`
@@ -254,22 +356,14 @@ func DeleteSubtask(requester_user_id uint, task_id uint, subtask_id uint) error
 		return custom_errors.ErrDatabaseFailure
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err = checkPermission(requester_user_id, workspace_id, SubtaskDeletePermission)
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
-		return custom_errors.ErrAccessDenied
 	}
 
-	_, err = DB.Exec(`
-		DELETE FROM Subtask
-		WHERE id = $1;;`,
-		subtask_id)
-
-	if err != nil {
-		log.Println("Error:", err)
-		return custom_errors.ErrDatabaseFailure
+	if err := checkSubtaskInTask(subtask_id, task_id); err != nil {
+		return err
 	}
 
-	return nil
+	return trashItem(requester_user_id, workspace_id, SubtaskItem, subtask_id, task_id)
 }
@@ -1,19 +1,90 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/skye-tan/trello/backend/utils/custom_errors"
 )
 
+func scanTask(row rowScanner) (Task, error) {
+	var task Task
+	var assignee_id sql.NullInt64
+
+	err := row.Scan(
+		&task.ID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.EstimatedTime,
+		&task.ActualTime,
+		&task.DueDate,
+		&task.Priority,
+		&task.WorkspaceID,
+		&assignee_id,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.ImageURL)
+
+	task.AssigneeID = uint(assignee_id.Int64)
+	task.AssigneeIDs = []uint{}
+
+	return task, err
+}
+
+func attachTaskAssignees(tasks []Task) error {
+	task_ids := make([]int64, len(tasks))
+	task_indexes := make(map[uint]int)
+	for i, task := range tasks {
+		task_ids[i] = int64(task.ID)
+		task_indexes[task.ID] = i
+	}
+
+	rows, err := DB.Query(`
+		SELECT task_id, user_id
+		FROM TaskAssignee
+		WHERE task_id = ANY($1::integer[]);`,
+		pq.Array(task_ids))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var task_id, user_id uint
+
+		if err := rows.Scan(&task_id, &user_id); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		i := task_indexes[task_id]
+		tasks[i].AssigneeIDs = append(tasks[i].AssigneeIDs, user_id)
+	}
+
+	return nil
+}
+
 func GetAssignedTasks(requester_user_id uint) ([]Task, error) {
 	rows, err := DB.Query(`
 		SELECT * 
 		FROM Task
-		WHERE assignee_id = $1;`,
-		requester_user_id)
+		WHERE id IN (
+			SELECT task_id
+			FROM TaskAssignee
+			WHERE user_id = $1
+		) AND EXISTS (
+			SELECT 1 FROM UserWorkspaceRole
+			WHERE user_id = $1 AND workspace_id = Task.workspace_id
+		) AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE (item_type = $2 AND item_id = Task.id) OR (item_type = $3 AND item_id = Task.workspace_id)
+		);`,
+		requester_user_id, TaskItem, WorkspaceItem)
 
 	if err != nil {
 		log.Println("Error:", err)
@@ -23,23 +94,7 @@ func GetAssignedTasks(requester_user_id uint) ([]Task, error) {
 	var tasks []Task
 
 	for rows.Next() {
-		var task Task
-
-		if err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.EstimatedTime,
-			&task.ActualTime,
-			&task.DueDate,
-			&task.Priority,
-			&task.WorkspaceID,
-			&task.AssigneeID,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.ImageURL,
-		); err != nil {
+		if task, err := scanTask(rows); err != nil {
 			log.Println("Error:", err)
 			return []Task{}, custom_errors.ErrDatabaseFailure
 		} else {
@@ -47,22 +102,50 @@ func GetAssignedTasks(requester_user_id uint) ([]Task, error) {
 		}
 	}
 
+	if err := attachTaskAssignees(tasks); err != nil {
+		return []Task{}, err
+	}
+
 	return tasks, nil
 }
 
-func GetAllTasksInWorkspace(requester_user_id uint, workspace_id uint) ([]Task, error) {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+// GetAllTasksInWorkspace lists the tasks of the workspace carrying all the
+// given labels. Archived tasks are left out unless the archived filter asks
+// for them, and a non-empty search narrows the list by title and description.
+func GetAllTasksInWorkspace(requester_user_id uint, workspace_id uint, label_ids []uint, archived string, search string) ([]Task, error) {
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
 	if err != nil {
 		return []Task{}, err
-	} else if user_role == NoRole {
-		return []Task{}, custom_errors.ErrAccessDenied
 	}
 
+	// Tasks are matched by counting their labels, so each label must be
+	// counted once.
+	label_ids = uniqueIDs(label_ids)
+
 	rows, err := DB.Query(`
 		SELECT * 
 		FROM Task
-		WHERE workspace_id = $1;`,
-		workspace_id)
+		WHERE workspace_id = $1 AND (
+			cardinality($2::integer[]) = 0 OR id IN (
+				SELECT task_id
+				FROM TaskLabel
+				WHERE label_id = ANY($2::integer[])
+				GROUP BY task_id
+				HAVING COUNT(*) = cardinality($2::integer[])
+			)
+		) AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $3 AND item_id = Task.id
+		) AND (
+			$4 = $5 OR EXISTS (
+				SELECT 1 FROM TaskArchive
+				WHERE task_id = Task.id
+			) = ($4 = $6)
+		) AND (
+			$7 = '' OR title ILIKE '%' || $7 || '%' OR description ILIKE '%' || $7 || '%'
+		);`,
+		workspace_id, pq.Array(toInt64Array(label_ids)), TaskItem,
+		archived, IncludeArchived, OnlyArchived, search)
 
 	if err != nil {
 		log.Println("Error:", err)
@@ -72,23 +155,7 @@ func GetAllTasksInWorkspace(requester_user_id uint, workspace_id uint) ([]Task,
 	var tasks []Task
 
 	for rows.Next() {
-		var task Task
-
-		if err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.EstimatedTime,
-			&task.ActualTime,
-			&task.DueDate,
-			&task.Priority,
-			&task.WorkspaceID,
-			&task.AssigneeID,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.ImageURL,
-		); err != nil {
+		if task, err := scanTask(rows); err != nil {
 			log.Println("Error:", err)
 			return []Task{}, custom_errors.ErrDatabaseFailure
 		} else {
@@ -96,58 +163,71 @@ func GetAllTasksInWorkspace(requester_user_id uint, workspace_id uint) ([]Task,
 		}
 	}
 
+	if err := attachTaskAssignees(tasks); err != nil {
+		return []Task{}, err
+	}
+
+	if err := attachTaskArchives(tasks); err != nil {
+		return []Task{}, err
+	}
+
 	return tasks, nil
 }
 
-func CreateTaskInWorkspace(requester_user_id uint, workspace_id uint, title string, description string, estimatedtime int, actualtime int, duedate time.Time, priority int, assigneeID uint, imageURL string) (Task, error) {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+func CreateTaskInWorkspace(requester_user_id uint, workspace_id uint, title string, description string, estimatedtime int, actualtime int, duedate time.Time, priority int, assignee_ids []uint, imageURL string) (Task, error) {
+	err := checkPermission(requester_user_id, workspace_id, TaskCreatePermission)
 	if err != nil {
 		return Task{}, err
-	} else if user_role != Admin && user_role != Owner {
-		return Task{}, custom_errors.ErrAccessDenied
 	}
 
-	assignee_user_role, err := getUserWorkspaceRole(assigneeID, workspace_id)
-	if err != nil {
-		return Task{}, err
-	} else if assignee_user_role == NoRole {
-		return Task{}, custom_errors.ErrInvalidArguments
+	assignee_ids = uniqueIDs(assignee_ids)
+	for _, assignee_id := range assignee_ids {
+		assignee_user_role, err := getMembershipRole(assignee_id, workspace_id)
+		if err != nil {
+			return Task{}, err
+		} else if assignee_user_role == NoRole {
+			return Task{}, custom_errors.ErrInvalidArguments
+		}
 	}
 
 	if ok := checkDuplicateTaskTitle(workspace_id, title); !ok {
 		return Task{}, custom_errors.ErrDuplicateTaskTitle
 	}
 
-	var task Task
+	var primary_assignee_id uint
+	if len(assignee_ids) != 0 {
+		primary_assignee_id = assignee_ids[0]
+	}
 
-	err = DB.QueryRow(`
+	task, err := scanTask(DB.QueryRow(`
 		INSERT INTO
 		Task(title,description,status,estimated_time,actual_time,due_date,priority,workspace_id,assignee_id,created_at,updated_at,image_url)
 		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING *;`,
 		title, description, Planned,
 		estimatedtime, actualtime, duedate,
-		priority, workspace_id, assigneeID,
-		time.Now(), time.Now(), imageURL).
-		Scan(&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.EstimatedTime,
-			&task.ActualTime,
-			&task.DueDate,
-			&task.Priority,
-			&task.WorkspaceID,
-			&task.AssigneeID,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.ImageURL)
+		priority, workspace_id, nullableID(primary_assignee_id),
+		time.Now(), time.Now(), imageURL))
 
 	if nil != err {
 		log.Println("Error:", err)
 		return Task{}, custom_errors.ErrDatabaseFailure
 	}
 
+	_, err = DB.Exec(`
+		INSERT INTO
+		TaskAssignee(task_id, user_id)
+		SELECT $1, unnest($2::integer[])
+		ON CONFLICT DO NOTHING;`,
+		task.ID, pq.Array(toInt64Array(assignee_ids)))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Task{}, custom_errors.ErrDatabaseFailure
+	}
+
+	task.AssigneeIDs = assignee_ids
+
 	return task, nil
 }
 
@@ -160,43 +240,36 @@ func GetDetailsOfTask(requester_user_id uint, workspace_id uint, task_id uint) (
 		return Task{}, custom_errors.ErrInvalidArguments
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err = checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
 	if err != nil {
 		return Task{}, err
-	} else if user_role == NoRole {
-		return Task{}, custom_errors.ErrAccessDenied
 	}
 
-	var task Task
-
-	err = DB.QueryRow(`
+	task, err := scanTask(DB.QueryRow(`
 		SELECT * 
 		FROM Task
 		WHERE id = $1;`,
-		task_id).
-		Scan(&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.EstimatedTime,
-			&task.ActualTime,
-			&task.DueDate,
-			&task.Priority,
-			&task.WorkspaceID,
-			&task.AssigneeID,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.ImageURL)
+		task_id))
 
 	if err != nil {
 		log.Println("Error:", err)
 		return Task{}, custom_errors.ErrDatabaseFailure
 	}
 
+	task.AssigneeIDs, err = GetTaskAssignees(task_id)
+	if err != nil {
+		return Task{}, err
+	}
+
+	task.IsArchived, err = isTaskArchived(task_id)
+	if err != nil {
+		return Task{}, err
+	}
+
 	return task, nil
 }
 
-func UpdateStatusOfTask(requester_user_id uint, workspace_id uint, task_id uint, status string) error {
+func UpdateStatusOfTask(requester_user_id uint, workspace_id uint, task_id uint, status string, force bool) error {
 	actual_workspace_id, err := getTaskWorkspaceID(task_id)
 	if err != nil {
 		log.Println("Error:", err)
@@ -205,18 +278,25 @@ func UpdateStatusOfTask(requester_user_id uint, workspace_id uint, task_id uint,
 		return custom_errors.ErrInvalidArguments
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err = checkTaskPermission(requester_user_id, workspace_id, task_id, TaskUpdateAnyPermission, TaskUpdateOwnPermission)
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
-		return custom_errors.ErrAccessDenied
+	}
+
+	if !force && (status == InProgress || status == Completed) {
+		blockers, err := GetUnfinishedBlockers(task_id)
+		if err != nil {
+			return err
+		} else if len(blockers) != 0 {
+			return custom_errors.ErrTaskBlocked
+		}
 	}
 
 	_, err = DB.Exec(`
 		UPDATE Task 
-		SET status = $1
-		WHERE id = $2;`,
-		status, task_id)
+		SET status = $1, updated_at = $2
+		WHERE id = $3;`,
+		status, time.Now(), task_id)
 
 	if err != nil {
 		log.Println("Error:", err)
@@ -237,16 +317,14 @@ func UpdateDetailsOfTask(requester_user_id uint, workspace_id uint, task_id uint
 		return custom_errors.ErrInvalidArguments
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err = checkTaskPermission(requester_user_id, workspace_id, task_id, TaskUpdateAnyPermission, TaskUpdateOwnPermission)
 	fmt.Print("h2", err)
 
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
-		return custom_errors.ErrAccessDenied
 	}
 
-	assignee_user_role, err := getUserWorkspaceRole(assigneeID, workspace_id)
+	assignee_user_role, err := getMembershipRole(assigneeID, workspace_id)
 	fmt.Print("h3", err)
 	if err != nil {
 		return err
@@ -254,10 +332,28 @@ func UpdateDetailsOfTask(requester_user_id uint, workspace_id uint, task_id uint
 		return custom_errors.ErrInvalidArguments
 	}
 
+	if err := checkTaskReassignment(requester_user_id, workspace_id, task_id, assigneeID); err != nil {
+		return err
+	}
+
 	if ok := checkDuplicateTaskTitleWithoutSelf(workspace_id, title, task_id); !ok {
 		return custom_errors.ErrDuplicateTaskTitle
 	}
 
+	// The previous primary assignee is replaced rather than kept alongside
+	// the new one.
+	_, err = DB.Exec(`
+		DELETE FROM TaskAssignee
+		WHERE task_id = $1 AND user_id != $2 AND user_id = (
+			SELECT assignee_id FROM Task WHERE id = $1
+		);`,
+		task_id, assigneeID)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
 	_, err = DB.Exec(`
 		UPDATE Task 
 		SET title = $1, description = $2, actual_time = $3, due_date = $4, priority = $5, updated_at = $6, assignee_id = $7, image_url = $8
@@ -271,6 +367,18 @@ func UpdateDetailsOfTask(requester_user_id uint, workspace_id uint, task_id uint
 		return custom_errors.ErrDatabaseFailure
 	}
 
+	_, err = DB.Exec(`
+		INSERT INTO
+		TaskAssignee(task_id, user_id)
+		VALUES($1, $2)
+		ON CONFLICT DO NOTHING;`,
+		task_id, assigneeID)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
 	return nil
 }
 
@@ -283,22 +391,10 @@ func DeleteTask(requester_user_id uint, workspace_id uint, task_id uint) error {
 		return custom_errors.ErrInvalidArguments
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err = checkPermission(requester_user_id, workspace_id, TaskDeletePermission)
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
-		return custom_errors.ErrAccessDenied
 	}
 
-	_, err = DB.Exec(`
-		DELETE FROM Task
-		WHERE id = $1;`,
-		task_id)
-
-	if err != nil {
-		log.Println("Error:", err)
-		return custom_errors.ErrDatabaseFailure
-	}
-
-	return nil
+	return trashItem(requester_user_id, workspace_id, TaskItem, task_id, 0)
 }
@@ -0,0 +1,406 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func scanTemplate(row rowScanner) (Template, error) {
+	var template Template
+
+	err := row.Scan(
+		&template.ID,
+		&template.UserID,
+		&template.Name,
+		&template.Type,
+		&template.CreatedAt,
+		&template.UpdatedAt)
+
+	return template, err
+}
+
+func scanTemplateTask(row rowScanner) (TemplateTask, error) {
+	var template_task TemplateTask
+
+	err := row.Scan(
+		&template_task.ID,
+		&template_task.TemplateID,
+		&template_task.Title,
+		&template_task.Description,
+		&template_task.EstimatedTime,
+		&template_task.Priority,
+		&template_task.DueOffset,
+		&template_task.ImageURL,
+		pq.Array(&template_task.Subtasks),
+		pq.Array(&template_task.Labels))
+
+	return template_task, err
+}
+
+func dueOffsetOfTask(task Task) int {
+	created_at := task.CreatedAt.Truncate(24 * time.Hour)
+	due_date := task.DueDate.Truncate(24 * time.Hour)
+
+	if due_date.Before(created_at) {
+		return 0
+	}
+
+	return int(due_date.Sub(created_at).Hours() / 24)
+}
+
+func GetTemplatesOfUser(requester_user_id uint) ([]Template, error) {
+	rows, err := DB.Query(`
+		SELECT *
+		FROM Template
+		WHERE user_id = $1;`,
+		requester_user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []Template{}, custom_errors.ErrDatabaseFailure
+	}
+
+	templates := []Template{}
+
+	for rows.Next() {
+		if template, err := scanTemplate(rows); err != nil {
+			log.Println("Error:", err)
+			return []Template{}, custom_errors.ErrDatabaseFailure
+		} else {
+			templates = append(templates, template)
+		}
+	}
+
+	return templates, nil
+}
+
+func GetDetailsOfTemplate(requester_user_id uint, template_id uint) (Template, error) {
+	user_id, err := getTemplateUserID(template_id)
+	if err != nil {
+		return Template{}, err
+	} else if user_id != requester_user_id {
+		return Template{}, custom_errors.ErrAccessDenied
+	}
+
+	template, err := scanTemplate(DB.QueryRow(`
+		SELECT *
+		FROM Template
+		WHERE id = $1;`,
+		template_id))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Template{}, custom_errors.ErrDatabaseFailure
+	}
+
+	rows, err := DB.Query(`
+		SELECT *
+		FROM TemplateTask
+		WHERE template_id = $1
+		ORDER BY id;`,
+		template_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Template{}, custom_errors.ErrDatabaseFailure
+	}
+
+	template.Tasks = []TemplateTask{}
+
+	for rows.Next() {
+		if template_task, err := scanTemplateTask(rows); err != nil {
+			log.Println("Error:", err)
+			return Template{}, custom_errors.ErrDatabaseFailure
+		} else {
+			template.Tasks = append(template.Tasks, template_task)
+		}
+	}
+
+	rows, err = DB.Query(`
+		SELECT *
+		FROM TemplateLabel
+		WHERE template_id = $1;`,
+		template_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Template{}, custom_errors.ErrDatabaseFailure
+	}
+
+	template.Labels = []TemplateLabel{}
+
+	for rows.Next() {
+		var template_label TemplateLabel
+
+		if err := rows.Scan(&template_label.TemplateID, &template_label.Name, &template_label.Color); err != nil {
+			log.Println("Error:", err)
+			return Template{}, custom_errors.ErrDatabaseFailure
+		}
+
+		template.Labels = append(template.Labels, template_label)
+	}
+
+	return template, nil
+}
+
+func createTemplate(requester_user_id uint, name string, template_type string) (Template, error) {
+	template, err := scanTemplate(DB.QueryRow(`
+		INSERT INTO
+		Template(user_id, name, type, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5)
+		RETURNING *;`,
+		requester_user_id, name, template_type,
+		time.Now(), time.Now()))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Template{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return template, nil
+}
+
+func snapshotTask(template_id uint, task Task) error {
+	subtasks := []string{}
+
+	rows, err := DB.Query(`
+		SELECT title
+		FROM Subtask
+		WHERE task_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $2 AND item_id = Subtask.id
+		)
+		ORDER BY id;`,
+		task.ID, SubtaskItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var title string
+
+		if err := rows.Scan(&title); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		subtasks = append(subtasks, title)
+	}
+
+	labels := []string{}
+
+	rows, err = DB.Query(`
+		SELECT name
+		FROM Label
+		WHERE id IN (
+			SELECT label_id
+			FROM TaskLabel
+			WHERE task_id = $1
+		);`,
+		task.ID)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	for rows.Next() {
+		var name string
+
+		if err := rows.Scan(&name); err != nil {
+			log.Println("Error:", err)
+			return custom_errors.ErrDatabaseFailure
+		}
+
+		labels = append(labels, name)
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		TemplateTask(template_id, title, description, estimated_time, priority, due_offset, image_url, subtasks, labels)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+		template_id, task.Title, task.Description,
+		task.EstimatedTime, task.Priority, dueOffsetOfTask(task),
+		task.ImageURL, pq.Array(subtasks), pq.Array(labels))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func CreateTaskTemplate(requester_user_id uint, workspace_id uint, task_id uint, name string) (Template, error) {
+	task, err := GetDetailsOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		return Template{}, err
+	}
+
+	template, err := createTemplate(requester_user_id, name, TaskTemplate)
+	if err != nil {
+		return Template{}, err
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		TemplateLabel(template_id, name, color)
+		SELECT $1, name, color
+		FROM Label
+		WHERE id IN (
+			SELECT label_id
+			FROM TaskLabel
+			WHERE task_id = $2
+		);`,
+		template.ID, task_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Template{}, custom_errors.ErrDatabaseFailure
+	}
+
+	if err := snapshotTask(template.ID, task); err != nil {
+		return Template{}, err
+	}
+
+	return GetDetailsOfTemplate(requester_user_id, template.ID)
+}
+
+func CreateBoardTemplate(requester_user_id uint, workspace_id uint, name string) (Template, error) {
+	tasks, err := GetAllTasksInWorkspace(requester_user_id, workspace_id, []uint{}, ExcludeArchived, "")
+	if err != nil {
+		return Template{}, err
+	}
+
+	template, err := createTemplate(requester_user_id, name, BoardTemplate)
+	if err != nil {
+		return Template{}, err
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		TemplateLabel(template_id, name, color)
+		SELECT $1, name, color
+		FROM Label
+		WHERE workspace_id = $2;`,
+		template.ID, workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Template{}, custom_errors.ErrDatabaseFailure
+	}
+
+	for _, task := range tasks {
+		if err := snapshotTask(template.ID, task); err != nil {
+			return Template{}, err
+		}
+	}
+
+	return GetDetailsOfTemplate(requester_user_id, template.ID)
+}
+
+func DeleteTemplate(requester_user_id uint, template_id uint) error {
+	user_id, err := getTemplateUserID(template_id)
+	if err != nil {
+		return err
+	} else if user_id != requester_user_id {
+		return custom_errors.ErrAccessDenied
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM Template
+		WHERE id = $1;`,
+		template_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func InstantiateTemplate(requester_user_id uint, workspace_id uint, template_id uint, base time.Time) ([]Task, error) {
+	template, err := GetDetailsOfTemplate(requester_user_id, template_id)
+	if err != nil {
+		return []Task{}, err
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, TemplateApplyPermission)
+	if err != nil {
+		return []Task{}, err
+	}
+
+	for _, template_task := range template.Tasks {
+		if ok := checkDuplicateTaskTitle(workspace_id, template_task.Title); !ok {
+			return []Task{}, custom_errors.ErrDuplicateTaskTitle
+		}
+	}
+
+	label_ids := make(map[string]uint)
+	for _, template_label := range template.Labels {
+		var label_id uint
+
+		err := DB.QueryRow(`
+			INSERT INTO
+			Label(workspace_id, name, color, created_at, updated_at)
+			VALUES($1, $2, $3, $4, $5)
+			ON CONFLICT (workspace_id, name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id;`,
+			workspace_id, template_label.Name, template_label.Color,
+			time.Now(), time.Now()).Scan(&label_id)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return []Task{}, custom_errors.ErrDatabaseFailure
+		}
+
+		label_ids[template_label.Name] = label_id
+	}
+
+	tasks := []Task{}
+
+	for _, template_task := range template.Tasks {
+		task, err := CreateTaskInWorkspace(requester_user_id, workspace_id,
+			template_task.Title, template_task.Description, template_task.EstimatedTime, 0,
+			base.AddDate(0, 0, template_task.DueOffset), template_task.Priority,
+			[]uint{}, template_task.ImageURL)
+		if err != nil {
+			return tasks, err
+		}
+
+		for _, title := range template_task.Subtasks {
+			if _, err := CreateSubtaskInTask(requester_user_id, task.ID, title, []uint{}); err != nil {
+				return tasks, err
+			}
+		}
+
+		for _, name := range template_task.Labels {
+			label_id, ok := label_ids[name]
+			if !ok {
+				continue
+			}
+
+			_, err := DB.Exec(`
+				INSERT INTO
+				TaskLabel(task_id, label_id)
+				VALUES($1, $2)
+				ON CONFLICT DO NOTHING;`,
+				task.ID, label_id)
+
+			if err != nil {
+				log.Println("Error:", err)
+				return tasks, custom_errors.ErrDatabaseFailure
+			}
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	hashing_utils "github.com/skye-tan/trello/backend/utils/hashing"
+	token_utils "github.com/skye-tan/trello/backend/utils/token"
+)
+
+func isTokenScope(scope string) bool {
+	for _, item := range TokenScopes {
+		if item == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func scanPersonalAccessToken(row rowScanner) (PersonalAccessToken, error) {
+	var personal_access_token PersonalAccessToken
+	var token_hash []byte
+	var expires_at, last_used_at sql.NullTime
+
+	err := row.Scan(
+		&personal_access_token.ID,
+		&personal_access_token.UserID,
+		&personal_access_token.Name,
+		&token_hash,
+		pq.Array(&personal_access_token.Scopes),
+		&expires_at,
+		&last_used_at,
+		&personal_access_token.CreatedAt)
+
+	personal_access_token.ExpiresAt = expires_at.Time
+	personal_access_token.LastUsedAt = last_used_at.Time
+
+	return personal_access_token, err
+}
+
+func getPersonalAccessTokens(user_id uint) ([]PersonalAccessToken, error) {
+	rows, err := DB.Query(`
+		SELECT *
+		FROM PersonalAccessToken
+		WHERE user_id = $1
+		ORDER BY id;`,
+		user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []PersonalAccessToken{}, custom_errors.ErrDatabaseFailure
+	}
+
+	personal_access_tokens := []PersonalAccessToken{}
+
+	for rows.Next() {
+		if personal_access_token, err := scanPersonalAccessToken(rows); err != nil {
+			log.Println("Error:", err)
+			return []PersonalAccessToken{}, custom_errors.ErrDatabaseFailure
+		} else {
+			personal_access_tokens = append(personal_access_tokens, personal_access_token)
+		}
+	}
+
+	return personal_access_tokens, nil
+}
+
+func createPersonalAccessToken(user_id uint, name string, scopes []string, expires_at time.Time) (PersonalAccessToken, error) {
+	if len(scopes) == 0 {
+		return PersonalAccessToken{}, custom_errors.ErrInvalidArguments
+	}
+	for _, scope := range scopes {
+		if !isTokenScope(scope) {
+			return PersonalAccessToken{}, custom_errors.ErrInvalidArguments
+		}
+	}
+
+	if !expires_at.IsZero() && expires_at.Before(time.Now()) {
+		return PersonalAccessToken{}, custom_errors.ErrInvalidArguments
+	}
+
+	token, err := token_utils.GeneratePersonalToken()
+	if err != nil {
+		return PersonalAccessToken{}, err
+	}
+
+	personal_access_token, err := scanPersonalAccessToken(DB.QueryRow(`
+		INSERT INTO
+		PersonalAccessToken(user_id, name, token_hash, scopes, expires_at, created_at)
+		VALUES($1, $2, $3, $4, $5, $6)
+		RETURNING *;`,
+		user_id, name, hashing_utils.HashUsingSha256(token), pq.Array(scopes),
+		sql.NullTime{Time: expires_at, Valid: !expires_at.IsZero()}, time.Now()))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return PersonalAccessToken{}, custom_errors.ErrDatabaseFailure
+	}
+
+	personal_access_token.Token = token
+
+	return personal_access_token, nil
+}
+
+func deletePersonalAccessToken(user_id uint, token_id uint) error {
+	result, err := DB.Exec(`
+		DELETE FROM PersonalAccessToken
+		WHERE id = $1 AND user_id = $2;`,
+		token_id, user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
+
+func GetPersonalAccessTokens(requester_user_id uint) ([]PersonalAccessToken, error) {
+	return getPersonalAccessTokens(requester_user_id)
+}
+
+func CreatePersonalAccessToken(requester_user_id uint, name string, scopes []string, expires_at time.Time) (PersonalAccessToken, error) {
+	return createPersonalAccessToken(requester_user_id, name, scopes, expires_at)
+}
+
+func DeletePersonalAccessToken(requester_user_id uint, token_id uint) error {
+	return deletePersonalAccessToken(requester_user_id, token_id)
+}
+
+func AuthenticatePersonalAccessToken(token string) (PersonalAccessToken, error) {
+	personal_access_token, err := scanPersonalAccessToken(DB.QueryRow(`
+		SELECT *
+		FROM PersonalAccessToken
+		WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > $2);`,
+		hashing_utils.HashUsingSha256(token), time.Now()))
+
+	if err == sql.ErrNoRows {
+		return PersonalAccessToken{}, custom_errors.ErrAccessDenied
+	} else if err != nil {
+		log.Println("Error:", err)
+		return PersonalAccessToken{}, custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		UPDATE PersonalAccessToken
+		SET last_used_at = $1
+		WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $3);`,
+		time.Now(), personal_access_token.ID, time.Now().Add(-time.Minute))
+
+	if err != nil {
+		log.Println("Error:", err)
+	}
+
+	return personal_access_token, nil
+}
@@ -0,0 +1,318 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+// Deleted items are recorded in the Trash table rather than in deleted_at
+// columns on Workspace, Task, Subtask and Comment. Those tables are read with
+// SELECT * and positional scans throughout, so one table keeps them as they
+// are, and it also holds what the trash listing, restoring and purging need
+// for all four kinds of items: the deleting user, the parent and the
+// workspace. Every read of these tables therefore filters out trashed rows.
+//
+// Only the item the user deleted is recorded in the trash. Its children stay
+// untouched and are hidden through their parent, so restoring the parent
+// brings them back as they were.
+const trashItemColumns = `
+		Trash.id, Trash.workspace_id, Trash.item_type, Trash.item_id, Trash.parent_id,
+		COALESCE(Workspace.name, Task.title, Subtask.title, Comment.text, ''),
+		Trash.deleted_by, Trash.deleted_at
+		FROM Trash
+		LEFT JOIN Workspace ON Trash.item_type = 'workspace' AND Workspace.id = Trash.item_id
+		LEFT JOIN Task ON Trash.item_type = 'task' AND Task.id = Trash.item_id
+		LEFT JOIN Subtask ON Trash.item_type = 'subtask' AND Subtask.id = Trash.item_id
+		LEFT JOIN Comment ON Trash.item_type = 'comment' AND Comment.id = Trash.item_id`
+
+func scanTrashItem(row rowScanner) (TrashItem, error) {
+	var trash_item TrashItem
+	var parent_id, deleted_by sql.NullInt64
+
+	err := row.Scan(
+		&trash_item.ID,
+		&trash_item.WorkspaceID,
+		&trash_item.ItemType,
+		&trash_item.ItemID,
+		&parent_id,
+		&trash_item.Title,
+		&deleted_by,
+		&trash_item.DeletedAt)
+
+	trash_item.ParentID = uint(parent_id.Int64)
+	trash_item.DeletedBy = uint(deleted_by.Int64)
+
+	return trash_item, err
+}
+
+func scanTrashItems(rows *sql.Rows) ([]TrashItem, error) {
+	trash_items := []TrashItem{}
+
+	for rows.Next() {
+		if trash_item, err := scanTrashItem(rows); err != nil {
+			log.Println("Error:", err)
+			return []TrashItem{}, custom_errors.ErrDatabaseFailure
+		} else {
+			trash_items = append(trash_items, trash_item)
+		}
+	}
+
+	return trash_items, nil
+}
+
+func isTrashed(item_type string, item_id uint) (bool, error) {
+	var trashed bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM Trash
+			WHERE item_type = $1 AND item_id = $2
+		);`,
+		item_type, item_id).
+		Scan(&trashed)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return trashed, nil
+}
+
+// checkSubtaskInTask makes sure the subtask belongs to the task and has not
+// been moved to the trash.
+func checkSubtaskInTask(subtask_id uint, task_id uint) error {
+	var exists bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM Subtask
+			WHERE id = $1 AND task_id = $2
+		) AND NOT EXISTS (
+			SELECT 1
+			FROM Trash
+			WHERE item_type = $3 AND item_id = $1
+		);`,
+		subtask_id, task_id, SubtaskItem).
+		Scan(&exists)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if !exists {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	return nil
+}
+
+func trashItem(requester_user_id uint, workspace_id uint, item_type string, item_id uint, parent_id uint) error {
+	_, err := DB.Exec(`
+		INSERT INTO
+		Trash(workspace_id, item_type, item_id, parent_id, deleted_by, deleted_at)
+		VALUES($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING;`,
+		workspace_id, item_type, item_id, nullableID(parent_id), requester_user_id, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetTrashInWorkspace(requester_user_id uint, workspace_id uint) ([]TrashItem, error) {
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return []TrashItem{}, err
+	}
+
+	rows, err := DB.Query(`
+		SELECT `+trashItemColumns+`
+		WHERE Trash.workspace_id = $1 AND Trash.item_type != $2
+		ORDER BY Trash.deleted_at DESC;`,
+		workspace_id, WorkspaceItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []TrashItem{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return scanTrashItems(rows)
+}
+
+// RestoreFromTrash brings back a task, subtask or comment of the workspace
+// together with everything it hid. Subtasks and comments can only come back
+// once their task is out of the trash.
+func RestoreFromTrash(requester_user_id uint, workspace_id uint, trash_id uint) (TrashItem, error) {
+	trash_item, err := scanTrashItem(DB.QueryRow(`
+		SELECT `+trashItemColumns+`
+		WHERE Trash.id = $1 AND Trash.workspace_id = $2 AND Trash.item_type != $3;`,
+		trash_id, workspace_id, WorkspaceItem))
+
+	if err == sql.ErrNoRows {
+		return TrashItem{}, custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return TrashItem{}, custom_errors.ErrDatabaseFailure
+	}
+
+	switch trash_item.ItemType {
+	case TaskItem:
+		err = checkPermission(requester_user_id, workspace_id, TaskDeletePermission)
+	case SubtaskItem:
+		err = checkPermission(requester_user_id, workspace_id, SubtaskDeletePermission)
+	case CommentItem:
+		err = checkOwnPermission(requester_user_id, workspace_id, CommentDeleteAnyPermission, CommentDeleteOwnPermission, func() (bool, error) {
+			var author_id uint
+
+			err := DB.QueryRow(`
+				SELECT user_id
+				FROM Comment
+				WHERE id = $1;`,
+				trash_item.ItemID).
+				Scan(&author_id)
+
+			if err != nil {
+				log.Println("Error:", err)
+				return false, custom_errors.ErrDatabaseFailure
+			}
+
+			return author_id == requester_user_id, nil
+		})
+	}
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	if trash_item.ItemType != TaskItem {
+		parent_trashed, err := isTrashed(TaskItem, trash_item.ParentID)
+		if err != nil {
+			return TrashItem{}, err
+		} else if parent_trashed {
+			return TrashItem{}, custom_errors.ErrInvalidArguments
+		}
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM Trash
+		WHERE id = $1;`,
+		trash_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return TrashItem{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return trash_item, nil
+}
+
+func GetTrashedWorkspaces(requester_user_id uint) ([]TrashItem, error) {
+	rows, err := DB.Query(`
+		SELECT `+trashItemColumns+`
+		JOIN UserWorkspaceRole ON UserWorkspaceRole.workspace_id = Trash.workspace_id
+		WHERE Trash.item_type = $1 AND UserWorkspaceRole.user_id = $2
+		ORDER BY Trash.deleted_at DESC;`,
+		WorkspaceItem, requester_user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []TrashItem{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return scanTrashItems(rows)
+}
+
+// RestoreWorkspace takes a workspace out of the trash. It has to look up the
+// role directly, as trashed workspaces refuse every permission check.
+func RestoreWorkspace(requester_user_id uint, workspace_id uint) error {
+	trashed, err := isTrashed(WorkspaceItem, workspace_id)
+	if err != nil {
+		return err
+	} else if !trashed {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	if err != nil {
+		return err
+	} else if user_role == NoRole {
+		return custom_errors.ErrAccessDenied
+	}
+
+	permissions, err := getRolePermissions(workspace_id, user_role)
+	if err == custom_errors.ErrInvalidArguments {
+		return custom_errors.ErrAccessDenied
+	} else if err != nil {
+		return err
+	} else if !permissions.has(WorkspaceDeletePermission) {
+		return custom_errors.ErrAccessDenied
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM Trash
+		WHERE item_type = $1 AND item_id = $2;`,
+		WorkspaceItem, workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+// PurgeTrash permanently deletes everything that has been in the trash since
+// before the cutoff and reports how many items were removed.
+func PurgeTrash(cutoff time.Time) (int64, error) {
+	var purged int64
+
+	for _, item := range []struct {
+		item_type string
+		table     string
+	}{
+		{CommentItem, "Comment"},
+		{SubtaskItem, "Subtask"},
+		{TaskItem, "Task"},
+		{WorkspaceItem, "Workspace"},
+	} {
+		result, err := DB.Exec(`
+			DELETE FROM `+item.table+`
+			WHERE id IN (
+				SELECT item_id
+				FROM Trash
+				WHERE item_type = $1 AND deleted_at < $2
+			);`,
+			item.item_type, cutoff)
+
+		if err != nil {
+			log.Println("Error:", err)
+			return purged, custom_errors.ErrDatabaseFailure
+		}
+
+		if count, err := result.RowsAffected(); err == nil {
+			purged += count
+		}
+	}
+
+	_, err := DB.Exec(`
+		DELETE FROM Trash
+		WHERE deleted_at < $1
+		OR (item_type = $2 AND NOT EXISTS (SELECT 1 FROM Task WHERE Task.id = Trash.item_id))
+		OR (item_type = $3 AND NOT EXISTS (SELECT 1 FROM Subtask WHERE Subtask.id = Trash.item_id))
+		OR (item_type = $4 AND NOT EXISTS (SELECT 1 FROM Comment WHERE Comment.id = Trash.item_id));`,
+		cutoff, TaskItem, SubtaskItem, CommentItem)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return purged, custom_errors.ErrDatabaseFailure
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func containsTask(tasks []Task, task_id uint) bool {
+	for _, task := range tasks {
+		if task.ID == task_id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTrashedItemsStayHidden(t *testing.T) {
+	requireDatabase(t)
+
+	user := createTestUser(t)
+	workspace := createTestWorkspace(t, user.ID)
+
+	task, err := CreateTaskInWorkspace(user.ID, workspace.ID, testName("t"), "", 0, 0,
+		time.Now().AddDate(0, 0, 7), 0, []uint{user.ID}, "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	trashed_task, err := CreateTaskInWorkspace(user.ID, workspace.ID, testName("t"), "", 0, 0,
+		time.Now().AddDate(0, 0, 7), 0, []uint{user.ID}, "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+
+	subtask, err := CreateSubtaskInTask(user.ID, task.ID, testName("s"), []uint{})
+	if err != nil {
+		t.Fatalf("creating subtask: %v", err)
+	}
+	comment, err := AddComment(user.ID, task.ID, workspace.ID, "trashed comment")
+	if err != nil {
+		t.Fatalf("adding comment: %v", err)
+	}
+	if _, err := CreateSubtaskInTask(user.ID, trashed_task.ID, testName("s"), []uint{}); err != nil {
+		t.Fatalf("creating subtask: %v", err)
+	}
+	if _, err := AddComment(user.ID, trashed_task.ID, workspace.ID, "comment of a trashed task"); err != nil {
+		t.Fatalf("adding comment: %v", err)
+	}
+
+	if err := DeleteSubtask(user.ID, task.ID, subtask.ID); err != nil {
+		t.Fatalf("trashing subtask: %v", err)
+	}
+	if err := DeleteComment(user.ID, task.ID, workspace.ID, comment.ID); err != nil {
+		t.Fatalf("trashing comment: %v", err)
+	}
+	if err := DeleteTask(user.ID, workspace.ID, trashed_task.ID); err != nil {
+		t.Fatalf("trashing task: %v", err)
+	}
+
+	t.Run("subtasks", func(t *testing.T) {
+		subtasks, err := GetAllSubtasksInTask(user.ID, task.ID)
+		if err != nil {
+			t.Fatalf("listing subtasks: %v", err)
+		} else if len(subtasks) != 0 {
+			t.Errorf("got %d subtasks, want the trashed one hidden", len(subtasks))
+		}
+
+		if subtasks, err := GetAllSubtasksInTask(user.ID, trashed_task.ID); err == nil && len(subtasks) != 0 {
+			t.Errorf("got %d subtasks of a trashed task", len(subtasks))
+		}
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		comments, err := GetComments(user.ID, task.ID, workspace.ID)
+		if err != nil {
+			t.Fatalf("listing comments: %v", err)
+		} else if len(comments) != 0 {
+			t.Errorf("got %d comments, want the trashed one hidden", len(comments))
+		}
+
+		if comments, err := GetComments(user.ID, trashed_task.ID, workspace.ID); err == nil && len(comments) != 0 {
+			t.Errorf("got %d comments of a trashed task", len(comments))
+		}
+	})
+
+	t.Run("workspace tasks", func(t *testing.T) {
+		for _, archived := range []string{ExcludeArchived, IncludeArchived, OnlyArchived} {
+			tasks, err := GetAllTasksInWorkspace(user.ID, workspace.ID, []uint{}, archived, "")
+			if err != nil {
+				t.Fatalf("listing tasks: %v", err)
+			} else if containsTask(tasks, trashed_task.ID) {
+				t.Errorf("trashed task is listed with archived filter %q", archived)
+			}
+		}
+	})
+
+	t.Run("assigned tasks", func(t *testing.T) {
+		tasks, err := GetAssignedTasks(user.ID)
+		if err != nil {
+			t.Fatalf("listing assigned tasks: %v", err)
+		} else if containsTask(tasks, trashed_task.ID) {
+			t.Errorf("trashed task is listed among assigned tasks")
+		} else if !containsTask(tasks, task.ID) {
+			t.Errorf("task is missing from assigned tasks")
+		}
+	})
+
+	t.Run("task report", func(t *testing.T) {
+		rows, err := GetTaskReport(user.ID, workspace.ID, []uint{}, IncludeArchived, "")
+		if err != nil {
+			t.Fatalf("building report: %v", err)
+		}
+		for _, row := range rows {
+			if row.ID == trashed_task.ID {
+				t.Errorf("trashed task is in the report")
+			}
+		}
+	})
+
+	t.Run("export", func(t *testing.T) {
+		export, err := ExportWorkspace(user.ID, workspace.ID)
+		if err != nil {
+			t.Fatalf("exporting workspace: %v", err)
+		}
+		for _, exported := range export.Tasks {
+			if exported.ID == trashed_task.ID {
+				t.Errorf("trashed task is exported")
+			} else if len(exported.Subtasks) != 0 || len(exported.Comments) != 0 {
+				t.Errorf("trashed subtask or comment is exported")
+			}
+		}
+	})
+}
+
+func TestTrashedWorkspaceHidesItsTasks(t *testing.T) {
+	requireDatabase(t)
+
+	user := createTestUser(t)
+	workspace := createTestWorkspace(t, user.ID)
+
+	task, err := CreateTaskInWorkspace(user.ID, workspace.ID, testName("t"), "", 0, 0,
+		time.Now().AddDate(0, 0, 7), 0, []uint{user.ID}, "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	if _, err := CreateSubtaskInTask(user.ID, task.ID, testName("s"), []uint{}); err != nil {
+		t.Fatalf("creating subtask: %v", err)
+	}
+	if _, err := AddComment(user.ID, task.ID, workspace.ID, "comment"); err != nil {
+		t.Fatalf("adding comment: %v", err)
+	}
+
+	if err := DeleteWorkspace(user.ID, workspace.ID); err != nil {
+		t.Fatalf("trashing workspace: %v", err)
+	}
+
+	workspaces, err := GetWorkspaces(user.ID)
+	if err != nil {
+		t.Fatalf("listing workspaces: %v", err)
+	}
+	for _, listed := range workspaces {
+		if listed.ID == workspace.ID {
+			t.Errorf("trashed workspace is listed")
+		}
+	}
+
+	if tasks, err := GetAllTasksInWorkspace(user.ID, workspace.ID, []uint{}, IncludeArchived, ""); err == nil && len(tasks) != 0 {
+		t.Errorf("got %d tasks of a trashed workspace", len(tasks))
+	}
+
+	tasks, err := GetAssignedTasks(user.ID)
+	if err != nil {
+		t.Fatalf("listing assigned tasks: %v", err)
+	} else if containsTask(tasks, task.ID) {
+		t.Errorf("task of a trashed workspace is listed among assigned tasks")
+	}
+
+	if subtasks, err := GetAllSubtasksInTask(user.ID, task.ID); err == nil && len(subtasks) != 0 {
+		t.Errorf("got %d subtasks of a task in a trashed workspace", len(subtasks))
+	}
+
+	if comments, err := GetComments(user.ID, task.ID, workspace.ID); err == nil && len(comments) != 0 {
+		t.Errorf("got %d comments of a task in a trashed workspace", len(comments))
+	}
+
+	if rows, err := GetTaskReport(user.ID, workspace.ID, []uint{}, IncludeArchived, ""); err == nil && len(rows) != 0 {
+		t.Errorf("got %d report rows of a trashed workspace", len(rows))
+	}
+}
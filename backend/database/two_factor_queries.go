@@ -0,0 +1,373 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	hashing_utils "github.com/skye-tan/trello/backend/utils/hashing"
+	totp_utils "github.com/skye-tan/trello/backend/utils/totp"
+)
+
+const twoFactorIssuer = "Trello"
+const recoveryCodeCount = 10
+
+func getTwoFactorSecret(user_id uint, is_enabled bool) (string, error) {
+	var secret string
+
+	err := DB.QueryRow(`
+		SELECT secret
+		FROM TwoFactor
+		WHERE user_id = $1 AND is_enabled = $2;`,
+		user_id, is_enabled).
+		Scan(&secret)
+
+	if err == sql.ErrNoRows {
+		return "", custom_errors.ErrInvalidArguments
+	} else if err != nil {
+		log.Println("Error:", err)
+		return "", custom_errors.ErrDatabaseFailure
+	}
+
+	return secret, nil
+}
+
+// useTwoFactorCode accepts a code only when its time step is newer than the
+// last accepted one, so a code cannot be replayed within its window.
+func useTwoFactorCode(user_id uint, code string) error {
+	secret, err := getTwoFactorSecret(user_id, true)
+	if err != nil {
+		return err
+	}
+
+	step, ok := totp_utils.Validate(secret, code, time.Now())
+	if !ok {
+		return custom_errors.ErrAccessDenied
+	}
+
+	result, err := DB.Exec(`
+		UPDATE TwoFactor
+		SET last_used_step = $1
+		WHERE user_id = $2 AND is_enabled AND last_used_step < $1;`,
+		step, user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return custom_errors.ErrAccessDenied
+	}
+
+	return nil
+}
+
+func useRecoveryCode(user_id uint, recovery_code string) error {
+	result, err := DB.Exec(`
+		UPDATE RecoveryCode
+		SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL;`,
+		time.Now(), user_id, hashing_utils.HashUsingSha256(recovery_code))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return custom_errors.ErrAccessDenied
+	}
+
+	return nil
+}
+
+func replaceRecoveryCodes(user_id uint) ([]string, error) {
+	codes, err := totp_utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		log.Println("Error:", err)
+		return []string{}, custom_errors.ErrTokenFailure
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM RecoveryCode
+		WHERE user_id = $1;`,
+		user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []string{}, custom_errors.ErrDatabaseFailure
+	}
+
+	for _, code := range codes {
+		_, err = DB.Exec(`
+			INSERT INTO
+			RecoveryCode(user_id, code_hash, created_at)
+			VALUES($1, $2, $3);`,
+			user_id, hashing_utils.HashUsingSha256(code), time.Now())
+
+		if err != nil {
+			log.Println("Error:", err)
+			return []string{}, custom_errors.ErrDatabaseFailure
+		}
+	}
+
+	return codes, nil
+}
+
+func isTwoFactorRequiredForUser(user_id uint) (bool, error) {
+	var required bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM UserWorkspaceRole
+			JOIN WorkspaceSecurity ON WorkspaceSecurity.workspace_id = UserWorkspaceRole.workspace_id
+			WHERE UserWorkspaceRole.user_id = $1 AND WorkspaceSecurity.require_two_factor
+		);`,
+		user_id).
+		Scan(&required)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return false, custom_errors.ErrDatabaseFailure
+	}
+
+	return required, nil
+}
+
+func IsTwoFactorEnabled(user_id uint) (bool, error) {
+	_, err := getTwoFactorSecret(user_id, true)
+	if err == custom_errors.ErrInvalidArguments {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func GetTwoFactorStatus(requester_user_id uint) (TwoFactorStatus, error) {
+	var status TwoFactorStatus
+	var enabled_at sql.NullTime
+
+	err := DB.QueryRow(`
+		SELECT COALESCE(bool_or(TwoFactor.is_enabled), false), max(TwoFactor.enabled_at),
+			(SELECT count(*) FROM RecoveryCode WHERE user_id = $1 AND used_at IS NULL)
+		FROM TwoFactor
+		WHERE user_id = $1;`,
+		requester_user_id).
+		Scan(&status.Enabled, &enabled_at, &status.RecoveryCodesLeft)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return TwoFactorStatus{}, custom_errors.ErrDatabaseFailure
+	}
+
+	if status.Enabled {
+		status.EnabledAt = enabled_at.Time
+	} else {
+		status.RecoveryCodesLeft = 0
+	}
+
+	return status, nil
+}
+
+// EnrollTwoFactor stores a fresh pending secret for the user. It only takes
+// effect once a code generated from it is confirmed by EnableTwoFactor.
+func EnrollTwoFactor(requester_user_id uint) (TwoFactorEnrollment, error) {
+	enabled, err := IsTwoFactorEnabled(requester_user_id)
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	} else if enabled {
+		return TwoFactorEnrollment{}, custom_errors.ErrInvalidArguments
+	}
+
+	user, err := GetUserByID(requester_user_id)
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+
+	secret, err := totp_utils.GenerateSecret()
+	if err != nil {
+		log.Println("Error:", err)
+		return TwoFactorEnrollment{}, custom_errors.ErrTokenFailure
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		TwoFactor(user_id, secret, is_enabled, last_used_step, created_at)
+		VALUES($1, $2, false, 0, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = $2, last_used_step = 0, created_at = $3
+		WHERE NOT TwoFactor.is_enabled;`,
+		requester_user_id, secret, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return TwoFactorEnrollment{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp_utils.ProvisioningURI(twoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+func EnableTwoFactor(requester_user_id uint, code string) ([]string, error) {
+	secret, err := getTwoFactorSecret(requester_user_id, false)
+	if err != nil {
+		return []string{}, err
+	}
+
+	step, ok := totp_utils.Validate(secret, code, time.Now())
+	if !ok {
+		return []string{}, custom_errors.ErrAccessDenied
+	}
+
+	result, err := DB.Exec(`
+		UPDATE TwoFactor
+		SET is_enabled = true, last_used_step = $1, enabled_at = $2
+		WHERE user_id = $3 AND NOT is_enabled AND secret = $4;`,
+		step, time.Now(), requester_user_id, secret)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []string{}, custom_errors.ErrDatabaseFailure
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("Error:", err)
+		return []string{}, custom_errors.ErrDatabaseFailure
+	} else if affected == 0 {
+		return []string{}, custom_errors.ErrInvalidArguments
+	}
+
+	return replaceRecoveryCodes(requester_user_id)
+}
+
+// VerifyTwoFactor accepts either a current authenticator code or one of the
+// unused recovery codes, which is consumed.
+func VerifyTwoFactor(user_id uint, code string, recovery_code string) error {
+	if code != "" {
+		return useTwoFactorCode(user_id, code)
+	} else if recovery_code != "" {
+		return useRecoveryCode(user_id, recovery_code)
+	}
+
+	return custom_errors.ErrInvalidArguments
+}
+
+func DisableTwoFactor(requester_user_id uint, code string, recovery_code string) error {
+	required, err := isTwoFactorRequiredForUser(requester_user_id)
+	if err != nil {
+		return err
+	} else if required {
+		return custom_errors.ErrTwoFactorRequired
+	}
+
+	err = VerifyTwoFactor(requester_user_id, code, recovery_code)
+	if err != nil {
+		return err
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM RecoveryCode
+		WHERE user_id = $1;`,
+		requester_user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM TwoFactor
+		WHERE user_id = $1;`,
+		requester_user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func RegenerateRecoveryCodes(requester_user_id uint, code string) ([]string, error) {
+	err := useTwoFactorCode(requester_user_id, code)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return replaceRecoveryCodes(requester_user_id)
+}
+
+func GetWorkspaceSecurity(requester_user_id uint, workspace_id uint) (WorkspaceSecurity, error) {
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
+	if err != nil {
+		return WorkspaceSecurity{}, err
+	}
+
+	workspace_security := WorkspaceSecurity{WorkspaceID: workspace_id}
+	var updated_by sql.NullInt64
+	var updated_at sql.NullTime
+
+	err = DB.QueryRow(`
+		SELECT require_two_factor, updated_by, updated_at
+		FROM WorkspaceSecurity
+		WHERE workspace_id = $1;`,
+		workspace_id).
+		Scan(&workspace_security.RequireTwoFactor, &updated_by, &updated_at)
+
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error:", err)
+		return WorkspaceSecurity{}, custom_errors.ErrDatabaseFailure
+	}
+
+	workspace_security.UpdatedBy = uint(updated_by.Int64)
+	workspace_security.UpdatedAt = updated_at.Time
+
+	return workspace_security, nil
+}
+
+func UpdateWorkspaceSecurity(requester_user_id uint, workspace_id uint, require_two_factor bool) error {
+	err := checkPermission(requester_user_id, workspace_id, SecurityManagePermission)
+	if err != nil {
+		return err
+	}
+
+	if require_two_factor {
+		enabled, err := IsTwoFactorEnabled(requester_user_id)
+		if err != nil {
+			return err
+		} else if !enabled {
+			return custom_errors.ErrTwoFactorRequired
+		}
+	}
+
+	_, err = DB.Exec(`
+		INSERT INTO
+		WorkspaceSecurity(workspace_id, require_two_factor, updated_by, updated_at)
+		VALUES($1, $2, $3, $4)
+		ON CONFLICT (workspace_id) DO UPDATE
+		SET require_two_factor = $2, updated_by = $3, updated_at = $4;`,
+		workspace_id, require_two_factor, requester_user_id, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
@@ -26,6 +26,8 @@ type Task struct {
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 	ImageURL      string    `json:"image_url"`
+	AssigneeIDs   []uint    `json:"assignee_ids"`
+	IsArchived    bool      `json:"is_archived"`
 }
 
 type Subtask struct {
@@ -36,6 +38,7 @@ type Subtask struct {
 	AssigneeID  uint      `json:"assignee_id"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	AssigneeIDs []uint    `json:"assignee_ids"`
 }
 
 type User struct {
@@ -68,6 +71,164 @@ type Watch struct {
 	UserID uint `json:"user_id"`
 }
 
+type Label struct {
+	ID          uint      `json:"id"`
+	WorkspaceID uint      `json:"workspace_id"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type TaskAssignee struct {
+	TaskID uint `json:"task_id"`
+	UserID uint `json:"user_id"`
+}
+
+type SubtaskAssignee struct {
+	SubtaskID uint `json:"subtask_id"`
+	UserID    uint `json:"user_id"`
+}
+
+type TaskLabel struct {
+	TaskID  uint `json:"task_id"`
+	LabelID uint `json:"label_id"`
+}
+
+type TaskDependency struct {
+	ID           uint      `json:"id"`
+	TaskID       uint      `json:"task_id"`
+	TargetTaskID uint      `json:"target_task_id"`
+	Type         string    `json:"type"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type DependencyGraphNode struct {
+	ID      uint   `json:"id"`
+	Title   string `json:"title"`
+	Status  string `json:"status"`
+	Blocked bool   `json:"blocked"`
+}
+
+type DependencyGraph struct {
+	Nodes []DependencyGraphNode `json:"nodes"`
+	Edges []TaskDependency      `json:"edges"`
+}
+
+type RecurringTask struct {
+	ID             uint      `json:"id"`
+	WorkspaceID    uint      `json:"workspace_id"`
+	TemplateTaskID uint      `json:"template_task_id"`
+	Rule           string    `json:"rule"`
+	StartAt        time.Time `json:"start_at"`
+	NextRunAt      time.Time `json:"next_run_at"`
+	IsPaused       bool      `json:"is_paused"`
+	CreatedBy      uint      `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type Template struct {
+	ID        uint            `json:"id"`
+	UserID    uint            `json:"user_id"`
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Tasks     []TemplateTask  `json:"tasks,omitempty"`
+	Labels    []TemplateLabel `json:"labels,omitempty"`
+}
+
+type TemplateTask struct {
+	ID            uint     `json:"id"`
+	TemplateID    uint     `json:"template_id"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	EstimatedTime int      `json:"estimated_time"`
+	Priority      int      `json:"priority"`
+	DueOffset     int      `json:"due_offset"`
+	ImageURL      string   `json:"image_url"`
+	Subtasks      []string `json:"subtasks"`
+	Labels        []string `json:"labels"`
+}
+
+type TemplateLabel struct {
+	TemplateID uint   `json:"template_id"`
+	Name       string `json:"name"`
+	Color      string `json:"color"`
+}
+
+type Notification struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	Event     string    `json:"event"`
+	Message   string    `json:"message"`
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type NotificationPage struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   uint           `json:"unread_count"`
+	Total         uint           `json:"total"`
+	Page          uint           `json:"page"`
+	PerPage       uint           `json:"per_page"`
+}
+
+type NotificationPreference struct {
+	Event   string `json:"event"`
+	Enabled bool   `json:"enabled"`
+}
+
+type EmailSettings struct {
+	UserID           uint      `json:"user_id"`
+	Mode             string    `json:"mode"`
+	UnsubscribeToken string    `json:"-"`
+	LastDigestAt     time.Time `json:"-"`
+}
+
+type OutboxEmail struct {
+	ID             uint      `json:"id"`
+	UserID         uint      `json:"user_id"`
+	Recipient      string    `json:"recipient"`
+	Subject        string    `json:"subject"`
+	TextBody       string    `json:"text_body"`
+	HTMLBody       string    `json:"html_body"`
+	UnsubscribeURL string    `json:"unsubscribe_url"`
+	Status         string    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	NextAttemptAt  time.Time `json:"next_attempt_at"`
+	LastError      string    `json:"last_error"`
+	CreatedAt      time.Time `json:"created_at"`
+	SentAt         time.Time `json:"sent_at"`
+}
+
+type Webhook struct {
+	ID          uint      `json:"id"`
+	WorkspaceID uint      `json:"workspace_id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"secret"`
+	Events      []string  `json:"events"`
+	IsActive    bool      `json:"is_active"`
+	CreatedBy   uint      `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uint      `json:"id"`
+	WebhookID      uint      `json:"webhook_id"`
+	Event          string    `json:"event"`
+	Payload        string    `json:"payload"`
+	Status         string    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	NextAttemptAt  time.Time `json:"next_attempt_at"`
+	ResponseStatus int       `json:"response_status"`
+	LastError      string    `json:"last_error"`
+	CreatedAt      time.Time `json:"created_at"`
+	DeliveredAt    time.Time `json:"delivered_at"`
+}
+
 type WatchStatus struct {
 	Status string `json:"status"`
 }
@@ -75,3 +236,259 @@ type WatchStatus struct {
 type ImageStatus struct {
 	Status string `json:"status"`
 }
+
+type IncomingWebhook struct {
+	ID            uint              `json:"id"`
+	WorkspaceID   uint              `json:"workspace_id"`
+	Name          string            `json:"name"`
+	Token         string            `json:"token"`
+	FieldMapping  map[string]string `json:"field_mapping"`
+	DefaultValues map[string]string `json:"default_values"`
+	CreatedBy     uint              `json:"created_by"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+type TaskSource struct {
+	ID                uint      `json:"id"`
+	IncomingWebhookID uint      `json:"incoming_webhook_id"`
+	ExternalID        string    `json:"external_id"`
+	TaskID            uint      `json:"task_id"`
+	SourceURL         string    `json:"source_url"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type PersonalAccessToken struct {
+	ID         uint      `json:"id"`
+	UserID     uint      `json:"user_id"`
+	Name       string    `json:"name"`
+	Token      string    `json:"token,omitempty"`
+	Scopes     []string  `json:"scopes"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ServiceAccount struct {
+	UserID      uint      `json:"user_id"`
+	WorkspaceID uint      `json:"workspace_id"`
+	Name        string    `json:"name"`
+	Username    string    `json:"username"`
+	Role        string    `json:"role"`
+	CreatedBy   uint      `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Invitation struct {
+	ID            uint      `json:"id"`
+	WorkspaceID   uint      `json:"workspace_id"`
+	WorkspaceName string    `json:"workspace_name"`
+	Kind          string    `json:"kind"`
+	InviteeUserID uint      `json:"invitee_user_id"`
+	InviteeEmail  string    `json:"invitee_email"`
+	Role          string    `json:"role"`
+	Token         string    `json:"token,omitempty"`
+	MaxUses       uint      `json:"max_uses"`
+	UseCount      uint      `json:"use_count"`
+	Status        string    `json:"status"`
+	InvitedBy     uint      `json:"invited_by"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool      `json:"enabled"`
+	EnabledAt         time.Time `json:"enabled_at"`
+	RecoveryCodesLeft int       `json:"recovery_codes_left"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type WorkspaceSecurity struct {
+	WorkspaceID      uint      `json:"workspace_id"`
+	RequireTwoFactor bool      `json:"require_two_factor"`
+	UpdatedBy        uint      `json:"updated_by"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type CustomRole struct {
+	ID          uint      `json:"id"`
+	WorkspaceID uint      `json:"workspace_id"`
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+	IsBuiltin   bool      `json:"is_builtin"`
+	CreatedBy   uint      `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type OwnershipTransfer struct {
+	ID            uint      `json:"id"`
+	WorkspaceID   uint      `json:"workspace_id"`
+	WorkspaceName string    `json:"workspace_name"`
+	FromUserID    uint      `json:"from_user_id"`
+	ToUserID      uint      `json:"to_user_id"`
+	StayOwner     bool      `json:"stay_owner"`
+	Status        string    `json:"status"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type TrashItem struct {
+	ID          uint      `json:"id"`
+	WorkspaceID uint      `json:"workspace_id"`
+	ItemType    string    `json:"item_type"`
+	ItemID      uint      `json:"item_id"`
+	ParentID    uint      `json:"parent_id"`
+	Title       string    `json:"title"`
+	DeletedBy   uint      `json:"deleted_by"`
+	DeletedAt   time.Time `json:"deleted_at"`
+}
+
+type WorkspaceArchivePolicy struct {
+	WorkspaceID     uint      `json:"workspace_id"`
+	AutoArchiveDays int       `json:"auto_archive_days"`
+	UpdatedBy       uint      `json:"updated_by"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type BulkTaskOperation struct {
+	Type              string
+	Status            string
+	Force             bool
+	UserID            uint
+	Replace           bool
+	LabelID           uint
+	TargetWorkspaceID uint
+}
+
+// BulkTaskResult reports the outcome of a bulk operation for one task.
+// Assigned tells whether a bulk assign made the user a new assignee of it.
+type BulkTaskResult struct {
+	TaskID   uint   `json:"task_id"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+	Task     *Task  `json:"task,omitempty"`
+	Assigned bool   `json:"-"`
+}
+
+// WorkspaceExport is the versioned JSON document produced by the workspace
+// export and accepted by the import. Users are referred to by username and
+// the ids only tie the entries of one document together; the import hands out
+// new ids and reports how they map. The picture uploaded for a task travels
+// with it as base64 encoded data.
+type WorkspaceExport struct {
+	Version      int                  `json:"version"`
+	ExportedAt   time.Time            `json:"exported_at"`
+	Workspace    ExportedWorkspace    `json:"workspace"`
+	Roles        []ExportedRole       `json:"roles"`
+	Members      []ExportedMember     `json:"members"`
+	Labels       []ExportedLabel      `json:"labels"`
+	Tasks        []ExportedTask       `json:"tasks"`
+	Dependencies []ExportedDependency `json:"dependencies"`
+}
+
+type ExportedWorkspace struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ExportedRole struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+type ExportedMember struct {
+	Username string `json:"username"`
+	Email    string `json:"email,omitempty"`
+	Role     string `json:"role"`
+}
+
+type ExportedLabel struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type ExportedTask struct {
+	ID            uint              `json:"id"`
+	Title         string            `json:"title"`
+	Description   string            `json:"description"`
+	Status        string            `json:"status"`
+	EstimatedTime int               `json:"estimated_time"`
+	ActualTime    int               `json:"actual_time"`
+	DueDate       time.Time         `json:"due_date"`
+	Priority      int               `json:"priority"`
+	ImageURL      string            `json:"image_url"`
+	Picture       string            `json:"picture,omitempty"`
+	IsArchived    bool              `json:"is_archived"`
+	Assignees     []string          `json:"assignees"`
+	Watchers      []string          `json:"watchers"`
+	LabelIDs      []uint            `json:"label_ids"`
+	Subtasks      []ExportedSubtask `json:"subtasks"`
+	Comments      []ExportedComment `json:"comments"`
+}
+
+type ExportedSubtask struct {
+	ID          uint     `json:"id"`
+	Title       string   `json:"title"`
+	IsCompleted bool     `json:"is_completed"`
+	Assignees   []string `json:"assignees"`
+}
+
+type ExportedComment struct {
+	ID     uint   `json:"id"`
+	Author string `json:"author"`
+	Text   string `json:"text"`
+}
+
+type ExportedDependency struct {
+	TaskID       uint   `json:"task_id"`
+	TargetTaskID uint   `json:"target_task_id"`
+	Type         string `json:"type"`
+}
+
+type WorkspaceImport struct {
+	Workspace  Workspace     `json:"workspace"`
+	LabelIDs   map[uint]uint `json:"label_ids"`
+	TaskIDs    map[uint]uint `json:"task_ids"`
+	SubtaskIDs map[uint]uint `json:"subtask_ids"`
+	CommentIDs map[uint]uint `json:"comment_ids"`
+	Conflicts  []string      `json:"conflicts"`
+	DryRun     bool          `json:"dry_run"`
+}
+
+type ImportJob struct {
+	ID         uint             `json:"id"`
+	UserID     uint             `json:"user_id"`
+	Source     string           `json:"source"`
+	Status     string           `json:"status"`
+	DryRun     bool             `json:"dry_run"`
+	Total      int              `json:"total"`
+	Processed  int              `json:"processed"`
+	Result     *WorkspaceImport `json:"result,omitempty"`
+	LastError  string           `json:"last_error"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	FinishedAt time.Time        `json:"finished_at"`
+}
+
+// TaskReportRow is a task together with the names its assignees and labels
+// are shown by in reports.
+type TaskReportRow struct {
+	Task
+	Assignees []string `json:"assignees"`
+	Labels    []string `json:"labels"`
+}
+
+type CalendarFeed struct {
+	UserID    uint      `json:"user_id"`
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -45,19 +45,31 @@ func CreateUser(username string, email string, password_hash []byte) error {
 		return custom_errors.ErrDuplicateUsername
 	}
 
-	_, err := DB.Exec(`
+	var user User
+	err := DB.QueryRow(`
 		INSERT INTO
 		Users(username, email, password_hash, created_at, updated_at)
-		VALUES($1, $2, $3, $4, $5);`,
+		VALUES($1, $2, $3, $4, $5)
+		RETURNING *;`,
 		username, email, password_hash,
-		time.Now(), time.Now())
+		time.Now(), time.Now()).
+		Scan(&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.PasswordHash,
+			&user.CreatedAt,
+			&user.UpdatedAt)
 
 	if err != nil {
 		log.Println("Error:", err)
 		return custom_errors.ErrDatabaseFailure
 	}
 
-	return nil
+	if err := claimEmailInvitations(user); err != nil {
+		return err
+	}
+
+	return startEmailVerification(user)
 }
 
 func GetUserByID(user_id uint) (User, error) {
@@ -125,7 +137,7 @@ func UpdateUserPassword(requester_user_id uint, password_hash []byte) error {
 	_, err := DB.Exec(`
 		UPDATE Users
 		SET password_hash = $1, updated_at = $2
-		WHERE id = $3;`,
+		WHERE id = $3 AND NOT EXISTS (SELECT 1 FROM ServiceAccount WHERE user_id = $3);`,
 		password_hash, time.Now(), requester_user_id)
 
 	if err != nil {
@@ -136,8 +148,56 @@ func UpdateUserPassword(requester_user_id uint, password_hash []byte) error {
 	return nil
 }
 
+// DeleteUser removes the account unless it is the last owner of a workspace
+// other people still use. Workspaces nobody else is a member of, apart from
+// their service accounts, are deleted along with it.
 func DeleteUser(requester_user_id uint) error {
-	_, err := DB.Exec(`
+	var last_owner bool
+
+	err := DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM UserWorkspaceRole AS Membership
+			WHERE Membership.user_id = $1 AND Membership.role = $2 AND NOT EXISTS (
+				SELECT 1 FROM UserWorkspaceRole
+				WHERE workspace_id = Membership.workspace_id AND user_id != $1 AND role = $2
+			) AND EXISTS (
+				SELECT 1 FROM UserWorkspaceRole
+				WHERE workspace_id = Membership.workspace_id AND user_id != $1 AND NOT EXISTS (
+					SELECT 1 FROM ServiceAccount WHERE user_id = UserWorkspaceRole.user_id
+				)
+			)
+		);`,
+		requester_user_id, Owner).
+		Scan(&last_owner)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	} else if last_owner {
+		return custom_errors.ErrLastOwner
+	}
+
+	_, err = DB.Exec(`
+		DELETE FROM Workspace
+		WHERE id IN (
+			SELECT workspace_id
+			FROM UserWorkspaceRole AS Membership
+			WHERE Membership.user_id = $1 AND Membership.role = $2 AND NOT EXISTS (
+				SELECT 1 FROM UserWorkspaceRole
+				WHERE workspace_id = Membership.workspace_id AND user_id != $1 AND NOT EXISTS (
+					SELECT 1 FROM ServiceAccount WHERE user_id = UserWorkspaceRole.user_id
+				)
+			)
+		);`,
+		requester_user_id, Owner)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	_, err = DB.Exec(`
 		DELETE FROM Users
 		WHERE id = $1;`,
 		requester_user_id)
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"log"
 	"time"
 
@@ -8,11 +9,9 @@ import (
 )
 
 func GetUserWorkspaceRoles(requester_user_id uint, workspace_id uint) ([]UserWorkspaceRole, error) {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
 	if err != nil {
 		return []UserWorkspaceRole{}, err
-	} else if user_role == NoRole {
-		return []UserWorkspaceRole{}, custom_errors.ErrAccessDenied
 	}
 
 	rows, err := DB.Query(`
@@ -48,59 +47,48 @@ func GetUserWorkspaceRoles(requester_user_id uint, workspace_id uint) ([]UserWor
 	return user_workspace_roles, nil
 }
 
-func AddUserWorkspaceRole(requester_user_id uint, user_id uint, workspace_id uint, role string) (UserWorkspaceRole, error) {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
-	if err != nil {
-		return UserWorkspaceRole{}, err
-	} else if user_role != Admin && user_role != Owner {
-		return UserWorkspaceRole{}, custom_errors.ErrAccessDenied
-	}
-
-	var user_workspace_role UserWorkspaceRole
-
-	err = DB.QueryRow(`
-		INSERT INTO
-		UserWorkspaceRole(user_id, workspace_id, role, created_at, updated_at)
-		VALUES($1, $2, $3, $4, $5)
-		RETURNING *;`,
-		user_id, workspace_id, role,
-		time.Now(), time.Now()).
-		Scan(&user_workspace_role.ID,
-			&user_workspace_role.UserID,
-			&user_workspace_role.WorkspaceID,
-			&user_workspace_role.Role,
-			&user_workspace_role.CreatedAt,
-			&user_workspace_role.UpdatedAt)
-
-	if err != nil {
+// AddUserWorkspaceRole invites the user to the workspace with the role. Users
+// are never added without their consent, so this only creates a direct
+// invitation they still have to accept.
+func AddUserWorkspaceRole(requester_user_id uint, user_id uint, workspace_id uint, role string) (Invitation, error) {
+	var username string
+
+	err := DB.QueryRow(`
+		SELECT username
+		FROM Users
+		WHERE id = $1;`,
+		user_id).
+		Scan(&username)
+
+	if err == sql.ErrNoRows {
+		return Invitation{}, custom_errors.ErrInvalidArguments
+	} else if err != nil {
 		log.Println("Error:", err)
-		return UserWorkspaceRole{}, custom_errors.ErrDatabaseFailure
+		return Invitation{}, custom_errors.ErrDatabaseFailure
 	}
 
-	return user_workspace_role, nil
+	return CreateInvitation(requester_user_id, workspace_id, username, "", role, time.Time{}, 0)
 }
 
 func UpdateUserWorkspaceRole(requester_user_id uint, user_id uint, workspace_id uint, role string) error {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	user_role, permissions, err := getUserPermissions(requester_user_id, workspace_id)
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
+	} else if !permissions.has(MemberManagePermission) {
 		return custom_errors.ErrAccessDenied
 	}
 
-	target_user_role, err := getUserWorkspaceRole(user_id, workspace_id)
+	target_user_role, err := getMembershipRole(user_id, workspace_id)
 	if err != nil {
 		return err
 	} else if target_user_role == NoRole {
 		return custom_errors.ErrInvalidArguments
-	} else if target_user_role == Owner {
-		return custom_errors.ErrAccessDenied
-	} else if user_role == Admin && target_user_role != StandardUser {
-		return custom_errors.ErrAccessDenied
 	}
 
-	if role == Owner {
-		return custom_errors.ErrAccessDenied
+	if err := checkRoleAuthority(user_role, permissions, workspace_id, target_user_role); err != nil {
+		return err
+	} else if err := checkRoleAuthority(user_role, permissions, workspace_id, role); err != nil {
+		return err
 	}
 
 	_, err = DB.Exec(`
@@ -119,23 +107,27 @@ func UpdateUserWorkspaceRole(requester_user_id uint, user_id uint, workspace_id
 
 func DeleteUserWorkspaceRole(requester_user_id uint, user_id uint, workspace_id uint) error {
 	if requester_user_id != user_id {
-		user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+		user_role, permissions, err := getUserPermissions(requester_user_id, workspace_id)
 		if err != nil {
 			return err
-		} else if user_role != Admin && user_role != Owner {
+		} else if !permissions.has(MemberManagePermission) {
 			return custom_errors.ErrAccessDenied
 		}
 
-		target_user_role, err := getUserWorkspaceRole(user_id, workspace_id)
+		target_user_role, err := getMembershipRole(user_id, workspace_id)
 		if err != nil {
 			return err
 		} else if target_user_role == NoRole {
 			return custom_errors.ErrInvalidArguments
 		} else if target_user_role == Owner {
 			return custom_errors.ErrInvalidArguments
-		} else if user_role == Admin && target_user_role != StandardUser {
-			return custom_errors.ErrAccessDenied
 		}
+
+		if err := checkRoleAuthority(user_role, permissions, workspace_id, target_user_role); err != nil {
+			return err
+		}
+	} else if err := checkLastOwner(user_id, workspace_id); err != nil {
+		return err
 	}
 
 	_, err := DB.Exec(`
@@ -1,6 +1,9 @@
 package database
 
 import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
 	"log"
 
 	"github.com/skye-tan/trello/backend/utils/custom_errors"
@@ -13,6 +16,69 @@ const (
 	NoRole       = ""
 )
 
+const (
+	WorkspaceViewPermission        = "workspace.view"
+	WorkspaceUpdatePermission      = "workspace.update"
+	WorkspaceDeletePermission      = "workspace.delete"
+	SecurityManagePermission       = "security.manage"
+	MemberInvitePermission         = "member.invite"
+	MemberManagePermission         = "member.manage"
+	RoleManagePermission           = "role.manage"
+	TaskCreatePermission           = "task.create"
+	TaskUpdateOwnPermission        = "task.update.own"
+	TaskUpdateAnyPermission        = "task.update.any"
+	TaskDeletePermission           = "task.delete"
+	TaskAssignPermission           = "task.assign"
+	SubtaskCreatePermission        = "subtask.create"
+	SubtaskUpdateOwnPermission     = "subtask.update.own"
+	SubtaskUpdateAnyPermission     = "subtask.update.any"
+	SubtaskDeletePermission        = "subtask.delete"
+	SubtaskAssignPermission        = "subtask.assign"
+	CommentCreatePermission        = "comment.create"
+	CommentDeleteOwnPermission     = "comment.delete.own"
+	CommentDeleteAnyPermission     = "comment.delete.any"
+	LabelManagePermission          = "label.manage"
+	LabelApplyPermission           = "label.apply"
+	DependencyManagePermission     = "dependency.manage"
+	RecurrenceManagePermission     = "recurrence.manage"
+	TemplateApplyPermission        = "template.apply"
+	IntegrationManagePermission    = "integration.manage"
+	WebhookManagePermission        = "webhook.manage"
+	ServiceAccountViewPermission   = "service_account.view"
+	ServiceAccountManagePermission = "service_account.manage"
+)
+
+var Permissions = []string{
+	WorkspaceViewPermission, WorkspaceUpdatePermission, WorkspaceDeletePermission, SecurityManagePermission,
+	MemberInvitePermission, MemberManagePermission, RoleManagePermission,
+	TaskCreatePermission, TaskUpdateOwnPermission, TaskUpdateAnyPermission, TaskDeletePermission, TaskAssignPermission,
+	SubtaskCreatePermission, SubtaskUpdateOwnPermission, SubtaskUpdateAnyPermission, SubtaskDeletePermission, SubtaskAssignPermission,
+	CommentCreatePermission, CommentDeleteOwnPermission, CommentDeleteAnyPermission,
+	LabelManagePermission, LabelApplyPermission, DependencyManagePermission, RecurrenceManagePermission, TemplateApplyPermission,
+	IntegrationManagePermission, WebhookManagePermission, ServiceAccountViewPermission, ServiceAccountManagePermission,
+}
+
+// BuiltinRoles maps the roles every workspace has to their permissions.
+// Owner holds every permission; the Admin set keeps what admins could always
+// do, and standard users may work on what is assigned to them.
+var BuiltinRoles = map[string][]string{
+	Owner: Permissions,
+	Admin: {
+		WorkspaceViewPermission, WorkspaceUpdatePermission, WorkspaceDeletePermission,
+		MemberInvitePermission, MemberManagePermission,
+		TaskCreatePermission, TaskUpdateOwnPermission, TaskUpdateAnyPermission, TaskDeletePermission, TaskAssignPermission,
+		SubtaskCreatePermission, SubtaskUpdateOwnPermission, SubtaskUpdateAnyPermission, SubtaskDeletePermission, SubtaskAssignPermission,
+		CommentCreatePermission, CommentDeleteOwnPermission, CommentDeleteAnyPermission,
+		LabelManagePermission, LabelApplyPermission, DependencyManagePermission, RecurrenceManagePermission, TemplateApplyPermission,
+		IntegrationManagePermission, ServiceAccountViewPermission,
+	},
+	StandardUser: {
+		WorkspaceViewPermission,
+		TaskUpdateOwnPermission, SubtaskUpdateOwnPermission,
+		CommentCreatePermission, CommentDeleteOwnPermission,
+	},
+}
+
 const (
 	Planned    = "Planned"
 	InProgress = "InProgress"
@@ -24,8 +90,226 @@ const (
 	No  = "No"
 )
 
+const (
+	Blocks     = "blocks"
+	BlockedBy  = "blocked-by"
+	RelatesTo  = "relates-to"
+	Duplicates = "duplicates"
+)
+
+const (
+	TaskTemplate  = "task"
+	BoardTemplate = "board"
+)
+
+const (
+	OverdueReminder = "overdue"
+)
+
+const (
+	AssignmentEvent = "assignment"
+	WatchEvent      = "watch"
+	MentionEvent    = "mention"
+	RoleEvent       = "role"
+	ReminderEvent   = "reminder"
+	InvitationEvent = "invitation"
+)
+
+var NotificationEvents = []string{AssignmentEvent, WatchEvent, MentionEvent, RoleEvent, ReminderEvent, InvitationEvent}
+
+const (
+	InstantEmail = "instant"
+	DigestEmail  = "digest"
+	NoEmail      = "off"
+)
+
+const (
+	PendingEmail = "pending"
+	SendingEmail = "sending"
+	SentEmail    = "sent"
+	FailedEmail  = "failed"
+)
+
+const (
+	TaskCreatedEvent    = "task.created"
+	TaskUpdatedEvent    = "task.updated"
+	TaskDeletedEvent    = "task.deleted"
+	SubtaskCreatedEvent = "subtask.created"
+	SubtaskUpdatedEvent = "subtask.updated"
+	SubtaskDeletedEvent = "subtask.deleted"
+	CommentCreatedEvent = "comment.created"
+	MemberAddedEvent    = "member.added"
+	MemberUpdatedEvent  = "member.updated"
+	MemberRemovedEvent  = "member.removed"
+	PingEvent           = "ping"
+)
+
+var WebhookEvents = []string{
+	TaskCreatedEvent, TaskUpdatedEvent, TaskDeletedEvent,
+	SubtaskCreatedEvent, SubtaskUpdatedEvent, SubtaskDeletedEvent,
+	CommentCreatedEvent,
+	MemberAddedEvent, MemberUpdatedEvent, MemberRemovedEvent,
+}
+
+const (
+	PendingDelivery   = "pending"
+	SendingDelivery   = "sending"
+	DeliveredDelivery = "delivered"
+	FailedDelivery    = "failed"
+)
+
+const (
+	ReadScope       = "read"
+	TasksWriteScope = "tasks:write"
+	AdminScope      = "admin"
+)
+
+var TokenScopes = []string{ReadScope, TasksWriteScope, AdminScope}
+
+const (
+	DirectInvitation = "direct"
+	EmailInvitation  = "email"
+	LinkInvitation   = "link"
+)
+
+const (
+	PendingInvitation  = "pending"
+	AcceptedInvitation = "accepted"
+	DeclinedInvitation = "declined"
+	RevokedInvitation  = "revoked"
+)
+
+const WorkspaceExportVersion = 1
+
+const (
+	WorkspaceImportSource = "workspace"
+	TrelloImportSource    = "trello"
+	CSVImportSource       = "csv"
+)
+
+const (
+	PendingImport   = "pending"
+	RunningImport   = "running"
+	CompletedImport = "completed"
+	FailedImport    = "failed"
+)
+
+const (
+	BulkStatusOperation   = "status"
+	BulkAssignOperation   = "assign"
+	BulkAddLabelOperation = "add_label"
+	BulkMoveOperation     = "move"
+	BulkDeleteOperation   = "delete"
+	BulkArchiveOperation  = "archive"
+)
+
+const (
+	ExcludeArchived = "exclude"
+	IncludeArchived = "include"
+	OnlyArchived    = "only"
+)
+
+const (
+	WorkspaceItem = "workspace"
+	TaskItem      = "task"
+	SubtaskItem   = "subtask"
+	CommentItem   = "comment"
+)
+
+const (
+	PendingTransfer   = "pending"
+	AcceptedTransfer  = "accepted"
+	DeclinedTransfer  = "declined"
+	CancelledTransfer = "cancelled"
+)
+
+const (
+	VerifyEmailPurpose   = "verify_email"
+	ResetPasswordPurpose = "reset_password"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// queryExecutor is satisfied by both the database and a transaction, so the
+// same statements can run on their own or as part of a larger change.
+type queryExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func nullableID(id uint) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+func toInt64Array(ids []uint) []int64 {
+	values := make([]int64, len(ids))
+	for i, id := range ids {
+		values[i] = int64(id)
+	}
+	return values
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool)
+	unique := []uint{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func generateSecretToken() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
+
+// getUserWorkspaceRole returns the role the user may act with in the
+// workspace. Members without two-factor authentication are refused while the
+// workspace requires it; service accounts are exempt since they cannot enroll.
 func getUserWorkspaceRole(user_id uint, workspace_id uint) (string, error) {
 	var role string
+	var missing_two_factor bool
+
+	err := DB.QueryRow(`
+		SELECT role,
+			EXISTS (
+				SELECT 1 FROM WorkspaceSecurity
+				WHERE workspace_id = $2 AND require_two_factor
+			) AND NOT EXISTS (
+				SELECT 1 FROM TwoFactor
+				WHERE user_id = $1 AND is_enabled
+			) AND NOT EXISTS (
+				SELECT 1 FROM ServiceAccount
+				WHERE user_id = $1
+			)
+		FROM UserWorkspaceRole
+		WHERE user_id = $1 AND workspace_id = $2;`,
+		user_id, workspace_id).
+		Scan(&role, &missing_two_factor)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return "", custom_errors.ErrDatabaseFailure
+	} else if missing_two_factor {
+		return "", custom_errors.ErrTwoFactorRequired
+	}
+
+	return role, nil
+}
+
+// getMembershipRole returns the stored role of a user in the workspace without
+// applying any security policy, for checks made about other members.
+func getMembershipRole(user_id uint, workspace_id uint) (string, error) {
+	var role string
 
 	err := DB.QueryRow(`
 		SELECT role
@@ -48,8 +332,113 @@ func getTaskWorkspaceID(task_id uint) (uint, error) {
 	err := DB.QueryRow(`
 		SELECT workspace_id
 		FROM Task 
+		WHERE id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $2 AND item_id = Task.id
+		);`,
+		task_id, TaskItem).Scan(&workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	return workspace_id, nil
+}
+
+func getLabelWorkspaceID(label_id uint) (uint, error) {
+	var workspace_id uint
+
+	err := DB.QueryRow(`
+		SELECT workspace_id
+		FROM Label 
+		WHERE id = $1;`,
+		label_id).Scan(&workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	return workspace_id, nil
+}
+
+func getRecurringTaskWorkspaceID(recurring_task_id uint) (uint, error) {
+	var workspace_id uint
+
+	err := DB.QueryRow(`
+		SELECT workspace_id
+		FROM RecurringTask 
+		WHERE id = $1;`,
+		recurring_task_id).Scan(&workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	return workspace_id, nil
+}
+
+func getTemplateUserID(template_id uint) (uint, error) {
+	var user_id uint
+
+	err := DB.QueryRow(`
+		SELECT user_id
+		FROM Template 
+		WHERE id = $1;`,
+		template_id).Scan(&user_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	return user_id, nil
+}
+
+func getWebhookWorkspaceID(webhook_id uint) (uint, error) {
+	var workspace_id uint
+
+	err := DB.QueryRow(`
+		SELECT workspace_id
+		FROM Webhook 
+		WHERE id = $1;`,
+		webhook_id).Scan(&workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	return workspace_id, nil
+}
+
+func getIncomingWebhookWorkspaceID(incoming_webhook_id uint) (uint, error) {
+	var workspace_id uint
+
+	err := DB.QueryRow(`
+		SELECT workspace_id
+		FROM IncomingWebhook
 		WHERE id = $1;`,
-		task_id).Scan(&workspace_id)
+		incoming_webhook_id).Scan(&workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return 0, custom_errors.ErrDatabaseFailure
+	}
+
+	return workspace_id, nil
+}
+
+func getServiceAccountWorkspaceID(service_account_id uint) (uint, error) {
+	var workspace_id uint
+
+	err := DB.QueryRow(`
+		SELECT workspace_id
+		FROM ServiceAccount
+		WHERE user_id = $1;`,
+		service_account_id).Scan(&workspace_id)
 
 	if err != nil {
 		log.Println("Error:", err)
@@ -136,3 +525,29 @@ func checkDuplicateSubtaskTitle(task_id uint, title string) bool {
 
 	return true
 }
+
+func checkDuplicateLabelName(workspace_id uint, name string) bool {
+	rows, err := DB.Query(`
+		SELECT * FROM Label
+		WHERE workspace_id = $1 AND name = $2`,
+		workspace_id, name)
+
+	if rows.Next() || err != nil {
+		return false
+	}
+
+	return true
+}
+
+func checkDuplicateLabelNameWithoutSelf(workspace_id uint, name string, id uint) bool {
+	rows, err := DB.Query(`
+		SELECT * FROM Label
+		WHERE workspace_id = $1 AND name = $2 AND id != $3`,
+		workspace_id, name, id)
+
+	if rows.Next() || err != nil {
+		return false
+	}
+
+	return true
+}
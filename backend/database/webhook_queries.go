@@ -0,0 +1,340 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+)
+
+func isWebhookEvent(event string) bool {
+	for _, item := range WebhookEvents {
+		if item == event {
+			return true
+		}
+	}
+	return false
+}
+
+func scanWebhook(row rowScanner) (Webhook, error) {
+	var webhook Webhook
+	var created_by sql.NullInt64
+
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.WorkspaceID,
+		&webhook.URL,
+		&webhook.Secret,
+		pq.Array(&webhook.Events),
+		&webhook.IsActive,
+		&created_by,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt)
+
+	webhook.CreatedBy = uint(created_by.Int64)
+
+	return webhook, err
+}
+
+func scanWebhookDelivery(row rowScanner) (WebhookDelivery, error) {
+	var webhook_delivery WebhookDelivery
+	var delivered_at sql.NullTime
+
+	err := row.Scan(
+		&webhook_delivery.ID,
+		&webhook_delivery.WebhookID,
+		&webhook_delivery.Event,
+		&webhook_delivery.Payload,
+		&webhook_delivery.Status,
+		&webhook_delivery.Attempts,
+		&webhook_delivery.NextAttemptAt,
+		&webhook_delivery.ResponseStatus,
+		&webhook_delivery.LastError,
+		&webhook_delivery.CreatedAt,
+		&delivered_at)
+
+	webhook_delivery.DeliveredAt = delivered_at.Time
+
+	return webhook_delivery, err
+}
+
+func scanWebhookDeliveries(rows *sql.Rows) ([]WebhookDelivery, error) {
+	webhook_deliveries := []WebhookDelivery{}
+
+	for rows.Next() {
+		if webhook_delivery, err := scanWebhookDelivery(rows); err != nil {
+			log.Println("Error:", err)
+			return []WebhookDelivery{}, custom_errors.ErrDatabaseFailure
+		} else {
+			webhook_deliveries = append(webhook_deliveries, webhook_delivery)
+		}
+	}
+
+	return webhook_deliveries, nil
+}
+
+func checkWebhookOwnership(requester_user_id uint, workspace_id uint, webhook_id uint) error {
+	actual_workspace_id, err := getWebhookWorkspaceID(webhook_id)
+	if err != nil {
+		return err
+	} else if actual_workspace_id != workspace_id {
+		return custom_errors.ErrInvalidArguments
+	}
+
+	err = checkPermission(requester_user_id, workspace_id, WebhookManagePermission)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetWebhooksInWorkspace(requester_user_id uint, workspace_id uint) ([]Webhook, error) {
+	err := checkPermission(requester_user_id, workspace_id, WebhookManagePermission)
+	if err != nil {
+		return []Webhook{}, err
+	}
+
+	rows, err := DB.Query(`
+		SELECT *
+		FROM Webhook
+		WHERE workspace_id = $1;`,
+		workspace_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []Webhook{}, custom_errors.ErrDatabaseFailure
+	}
+
+	webhooks := []Webhook{}
+
+	for rows.Next() {
+		if webhook, err := scanWebhook(rows); err != nil {
+			log.Println("Error:", err)
+			return []Webhook{}, custom_errors.ErrDatabaseFailure
+		} else {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	return webhooks, nil
+}
+
+func CreateWebhookInWorkspace(requester_user_id uint, workspace_id uint, url string, events []string) (Webhook, error) {
+	err := checkPermission(requester_user_id, workspace_id, WebhookManagePermission)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	for _, event := range events {
+		if !isWebhookEvent(event) {
+			return Webhook{}, custom_errors.ErrInvalidArguments
+		}
+	}
+
+	secret, err := generateSecretToken()
+	if err != nil {
+		log.Println("Error:", err)
+		return Webhook{}, custom_errors.ErrDatabaseFailure
+	}
+
+	webhook, err := scanWebhook(DB.QueryRow(`
+		INSERT INTO
+		Webhook(workspace_id, url, secret, events, is_active, created_by, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING *;`,
+		workspace_id, url, secret, pq.Array(events), true,
+		requester_user_id, time.Now(), time.Now()))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Webhook{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return webhook, nil
+}
+
+func UpdateWebhook(requester_user_id uint, workspace_id uint, webhook_id uint, url string, events []string, is_active bool) error {
+	if err := checkWebhookOwnership(requester_user_id, workspace_id, webhook_id); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if !isWebhookEvent(event) {
+			return custom_errors.ErrInvalidArguments
+		}
+	}
+
+	_, err := DB.Exec(`
+		UPDATE Webhook
+		SET url = $1, events = $2, is_active = $3, updated_at = $4
+		WHERE id = $5;`,
+		url, pq.Array(events), is_active, time.Now(), webhook_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func DeleteWebhook(requester_user_id uint, workspace_id uint, webhook_id uint) error {
+	if err := checkWebhookOwnership(requester_user_id, workspace_id, webhook_id); err != nil {
+		return err
+	}
+
+	_, err := DB.Exec(`
+		DELETE FROM Webhook
+		WHERE id = $1;`,
+		webhook_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func GetWebhookDeliveries(requester_user_id uint, workspace_id uint, webhook_id uint, page uint, per_page uint) ([]WebhookDelivery, error) {
+	if err := checkWebhookOwnership(requester_user_id, workspace_id, webhook_id); err != nil {
+		return []WebhookDelivery{}, err
+	}
+
+	rows, err := DB.Query(`
+		SELECT *
+		FROM WebhookDelivery
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3;`,
+		webhook_id, per_page, (page-1)*per_page)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []WebhookDelivery{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return scanWebhookDeliveries(rows)
+}
+
+func QueueTestWebhookDelivery(requester_user_id uint, workspace_id uint, webhook_id uint, payload string) (WebhookDelivery, error) {
+	if err := checkWebhookOwnership(requester_user_id, workspace_id, webhook_id); err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	webhook_delivery, err := scanWebhookDelivery(DB.QueryRow(`
+		INSERT INTO
+		WebhookDelivery(webhook_id, event, payload, status, next_attempt_at, created_at)
+		VALUES($1, $2, $3, $4, $5, $6)
+		RETURNING *;`,
+		webhook_id, PingEvent, payload, PendingDelivery, time.Now(), time.Now()))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return WebhookDelivery{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return webhook_delivery, nil
+}
+
+func QueueWebhookDeliveries(workspace_id uint, event string, payload string) error {
+	_, err := DB.Exec(`
+		INSERT INTO
+		WebhookDelivery(webhook_id, event, payload, status, next_attempt_at, created_at)
+		SELECT id, $2::text, $3, $4, $5, $5
+		FROM Webhook
+		WHERE workspace_id = $1 AND is_active = true AND $2::text = ANY(events);`,
+		workspace_id, event, payload, PendingDelivery, time.Now())
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func QueueTaskWebhookDeliveries(task_id uint, event string, payload string) error {
+	workspace_id, err := getTaskWorkspaceID(task_id)
+	if err != nil {
+		return err
+	}
+
+	return QueueWebhookDeliveries(workspace_id, event, payload)
+}
+
+func GetWebhookForDelivery(webhook_id uint) (Webhook, error) {
+	webhook, err := scanWebhook(DB.QueryRow(`
+		SELECT *
+		FROM Webhook
+		WHERE id = $1;`,
+		webhook_id))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return Webhook{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return webhook, nil
+}
+
+func ClaimPendingWebhookDeliveries(now time.Time, limit int, lease time.Duration) ([]WebhookDelivery, error) {
+	rows, err := DB.Query(`
+		UPDATE WebhookDelivery
+		SET status = $1, attempts = attempts + 1, next_attempt_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM WebhookDelivery
+			WHERE status IN ($3, $1) AND next_attempt_at <= $4
+			ORDER BY id
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *;`,
+		SendingDelivery, now.Add(lease), PendingDelivery, now, limit)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return []WebhookDelivery{}, custom_errors.ErrDatabaseFailure
+	}
+
+	return scanWebhookDeliveries(rows)
+}
+
+func MarkWebhookDelivered(webhook_delivery_id uint, response_status int) error {
+	_, err := DB.Exec(`
+		UPDATE WebhookDelivery
+		SET status = $1, response_status = $2, delivered_at = $3, last_error = ''
+		WHERE id = $4;`,
+		DeliveredDelivery, response_status, time.Now(), webhook_delivery_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
+
+func MarkWebhookDeliveryFailed(webhook_delivery_id uint, give_up bool, next_attempt_at time.Time, response_status int, last_error string) error {
+	status := PendingDelivery
+	if give_up {
+		status = FailedDelivery
+	}
+
+	_, err := DB.Exec(`
+		UPDATE WebhookDelivery
+		SET status = $1, next_attempt_at = $2, response_status = $3, last_error = $4
+		WHERE id = $5;`,
+		status, next_attempt_at, response_status, last_error, webhook_delivery_id)
+
+	if err != nil {
+		log.Println("Error:", err)
+		return custom_errors.ErrDatabaseFailure
+	}
+
+	return nil
+}
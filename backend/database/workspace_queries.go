@@ -12,8 +12,11 @@ func GetWorkspaces(requester_user_id uint) ([]Workspace, error) {
 		SELECT A.*
 		FROM Workspace A JOIN UserWorkspaceRole B
 		ON A.id = B.workspace_id
-		WHERE B.user_id = $1;`,
-		requester_user_id)
+		WHERE B.user_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM Trash
+			WHERE item_type = $2 AND item_id = A.id
+		);`,
+		requester_user_id, WorkspaceItem)
 
 	if err != nil {
 		log.Println("Error:", err)
@@ -41,11 +44,20 @@ func GetWorkspaces(requester_user_id uint) ([]Workspace, error) {
 	return workspaces, nil
 }
 
-func CreateWorkSpace(requester_user_id uint, name string, description string) (Workspace, error) {
+func CreateWorkSpace(requester_user_id uint, name string, description string, template_id uint) (Workspace, error) {
 	if ok := checkDuplicateWorkspaceName(name); !ok {
 		return Workspace{}, custom_errors.ErrDuplicateWorspaceName
 	}
 
+	if template_id != 0 {
+		user_id, err := getTemplateUserID(template_id)
+		if err != nil {
+			return Workspace{}, err
+		} else if user_id != requester_user_id {
+			return Workspace{}, custom_errors.ErrAccessDenied
+		}
+	}
+
 	var workspace Workspace
 
 	err := DB.QueryRow(`
@@ -77,15 +89,28 @@ func CreateWorkSpace(requester_user_id uint, name string, description string) (W
 		return Workspace{}, custom_errors.ErrDatabaseFailure
 	}
 
+	if template_id != 0 {
+		if _, err := InstantiateTemplate(requester_user_id, workspace.ID, template_id, time.Now()); err != nil {
+			// The template goes through the regular task queries, so a
+			// half-instantiated workspace is removed instead of rolled back.
+			if _, delete_err := DB.Exec(`
+				DELETE FROM Workspace
+				WHERE id = $1;`,
+				workspace.ID); delete_err != nil {
+				log.Println("Error:", delete_err)
+			}
+
+			return Workspace{}, err
+		}
+	}
+
 	return workspace, nil
 }
 
 func GetWorkspace(requester_user_id uint, workspace_id uint) (Workspace, error) {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceViewPermission)
 	if err != nil {
 		return Workspace{}, err
-	} else if user_role == NoRole {
-		return Workspace{}, custom_errors.ErrAccessDenied
 	}
 
 	var workspace Workspace
@@ -115,11 +140,9 @@ func UpdateWorkspace(requester_user_id uint, workspace_id uint, name string, des
 		return custom_errors.ErrDuplicateWorspaceName
 	}
 
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceUpdatePermission)
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
-		return custom_errors.ErrAccessDenied
 	}
 
 	_, err = DB.Exec(`
@@ -137,22 +160,10 @@ func UpdateWorkspace(requester_user_id uint, workspace_id uint, name string, des
 }
 
 func DeleteWorkspace(requester_user_id uint, workspace_id uint) error {
-	user_role, err := getUserWorkspaceRole(requester_user_id, workspace_id)
+	err := checkPermission(requester_user_id, workspace_id, WorkspaceDeletePermission)
 	if err != nil {
 		return err
-	} else if user_role != Admin && user_role != Owner {
-		return custom_errors.ErrAccessDenied
-	}
-
-	_, err = DB.Exec(`
-		DELETE FROM Workspace 
-		WHERE id = $1;`,
-		workspace_id)
-
-	if err != nil {
-		log.Println("Error:", err)
-		return custom_errors.ErrDatabaseFailure
 	}
 
-	return nil
+	return trashItem(requester_user_id, workspace_id, WorkspaceItem, workspace_id, 0)
 }
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	hashing_utils "github.com/skye-tan/trello/backend/utils/hashing"
+	oidc_utils "github.com/skye-tan/trello/backend/utils/oidc"
+	regex_utils "github.com/skye-tan/trello/backend/utils/regex"
+)
+
+// POST "/password/forgot"
+func forgotPassword(c echo.Context) error {
+	if !oidc_utils.PasswordLoginEnabled() {
+		return echo.NewHTTPError(http.StatusForbidden, custom_messages.PasswordDisabled)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	email, ok := content["email"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	} else if !regex_utils.ValidateEmail(email) {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidEmailFormat)
+	}
+
+	err = database.RequestPasswordReset(email)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// POST "/password/reset"
+func resetPassword(c echo.Context) error {
+	if !oidc_utils.PasswordLoginEnabled() {
+		return echo.NewHTTPError(http.StatusForbidden, custom_messages.PasswordDisabled)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	token, ok := content["token"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	password, ok := content["password"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	} else if !regex_utils.ValidatePassword(password) {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidPasswordFormat)
+	}
+
+	err = database.ResetPassword(token, hashing_utils.HashUsingSha256(password))
+	if err == custom_errors.ErrInvalidArguments {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidAccountToken)
+	} else if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// GET, POST "/email/verify/:token"
+func verifyEmail(c echo.Context) error {
+	err := database.VerifyEmail(c.Param("token"))
+	if err == custom_errors.ErrInvalidArguments {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidAccountToken)
+	} else if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.String(http.StatusOK, custom_messages.EmailVerified)
+}
+
+// POST "/email/verify/resend"
+func resendEmailVerification(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	err := database.RequestEmailVerification(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+func extractArchiveDays(c echo.Context, key string) (int, error) {
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	tmp, ok := content[key].(float64)
+	if !ok {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	} else if tmp < 0 || tmp != float64(int(tmp)) || tmp > 3650 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidArchiveAge)
+	}
+
+	return int(tmp), nil
+}
+
+func broadcastTaskArchiveChange(requester_user_id uint, workspace_id uint, task_id uint, change string) error {
+	task, err := database.GetDetailsOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Task '%s' has been %s.", task.Title, change),
+		},
+	}
+
+	queueWebhookEvent(workspace_id, database.TaskUpdatedEvent, task)
+
+	return nil
+}
+
+// POST "/workspaces/:workspace_id/tasks/:task_id/archive"
+func archiveTask(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	err := database.ArchiveTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if err := broadcastTaskArchiveChange(requester_user_id, workspace_id, task_id, "archived"); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// POST "/workspaces/:workspace_id/tasks/:task_id/unarchive"
+func unarchiveTask(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	err := database.UnarchiveTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if err := broadcastTaskArchiveChange(requester_user_id, workspace_id, task_id, "unarchived"); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// POST "/workspaces/:workspace_id/tasks/archive-completed"
+func archiveCompletedTasks(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	older_than_days, err := extractArchiveDays(c, "older_than_days")
+	if err != nil {
+		return err
+	}
+
+	task_ids, err := database.ArchiveCompletedTasks(requester_user_id, workspace_id, older_than_days)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if len(task_ids) != 0 {
+		members, err := database.GetWorkspaceMembers(workspace_id)
+		if err != nil {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+			return generateProperResponse(err)
+		}
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+		websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+			TargetUserIDs: members,
+			Body: &websocket_utils.WebsocketBody{
+				Group:   websocket_utils.TaskGroup,
+				Type:    websocket_utils.UpdateType,
+				Message: fmt.Sprintf("%d completed tasks have been archived.", len(task_ids)),
+			},
+		}
+	}
+
+	for _, task_id := range task_ids {
+		queueTaskSnapshotWebhookEvent(requester_user_id, workspace_id, task_id, database.TaskUpdatedEvent)
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"archived_task_ids": task_ids,
+	})
+}
+
+// GET "/workspaces/:workspace_id/archive-policy"
+func getArchivePolicy(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	archive_policy, err := database.GetWorkspaceArchivePolicy(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, archive_policy)
+}
+
+// PUT "/workspaces/:workspace_id/archive-policy"
+func updateArchivePolicy(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	auto_archive_days, err := extractArchiveDays(c, "auto_archive_days")
+	if err != nil {
+		return err
+	}
+
+	err = database.UpdateWorkspaceArchivePolicy(requester_user_id, workspace_id, auto_archive_days)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
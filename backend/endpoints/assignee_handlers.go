@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+// POST "/workspaces/:workspace_id/tasks/:task_id/assignees"
+func addTaskAssignees(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	user_ids, ok := extractIDList(content, "user_ids")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidUserId)
+	} else if len(user_ids) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	task, err := database.GetDetailsOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	added_users, err := database.AddAssigneesToTask(requester_user_id, workspace_id, task_id, user_ids)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: added_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.WatchType,
+			Message: fmt.Sprintf("Task '%s' has been assigned to you.", task.Title),
+		},
+		Event: database.AssignmentEvent,
+	}
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Task '%s' has been updated.", task.Title),
+		},
+	}
+
+	assignees, err := database.GetTaskAssignees(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	queueTaskSnapshotWebhookEvent(requester_user_id, workspace_id, task_id, database.TaskUpdatedEvent)
+
+	return c.JSON(http.StatusCreated, assignees)
+}
+
+// DELETE "/workspaces/:workspace_id/tasks/:task_id/assignees/:user_id"
+func removeTaskAssignee(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	user_id, ok := extractQueryParameter(c, "user_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidUserId)
+	}
+
+	task, err := database.GetDetailsOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	err = database.RemoveAssigneeFromTask(requester_user_id, workspace_id, task_id, user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if containsID(task.AssigneeIDs, user_id) {
+		websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+			TargetUserIDs: []uint{user_id},
+			Body: &websocket_utils.WebsocketBody{
+				Group:   websocket_utils.TaskGroup,
+				Type:    websocket_utils.WatchType,
+				Message: fmt.Sprintf("Task '%s' is no longer assigned to you.", task.Title),
+			},
+			Event: database.AssignmentEvent,
+		}
+	}
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Task '%s' has been updated.", task.Title),
+		},
+	}
+
+	queueTaskSnapshotWebhookEvent(requester_user_id, workspace_id, task_id, database.TaskUpdatedEvent)
+
+	return c.NoContent(http.StatusOK)
+}
+
+// POST "/tasks/:task_id/subtasks/:subtask_id/assignees"
+func addSubtaskAssignees(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	subtask_id, ok := extractQueryParameter(c, "subtask_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidSubtaskId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	user_ids, ok := extractIDList(content, "user_ids")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidUserId)
+	} else if len(user_ids) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	subtask, err := database.GetDetailsOfSubtask(requester_user_id, task_id, subtask_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	added_users, err := database.AddAssigneesToSubtask(requester_user_id, task_id, subtask_id, user_ids)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: added_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.SubtaskGroup,
+			Type:    websocket_utils.WatchType,
+			Message: fmt.Sprintf("Subtask '%s' has been assigned to you.", subtask.Title),
+		},
+		Event: database.AssignmentEvent,
+	}
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.SubtaskGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Subtask '%s' has been updated.", subtask.Title),
+		},
+	}
+
+	assignees, err := database.GetSubtaskAssignees(subtask_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	queueSubtaskSnapshotWebhookEvent(requester_user_id, task_id, subtask_id, database.SubtaskUpdatedEvent)
+
+	return c.JSON(http.StatusCreated, assignees)
+}
+
+// DELETE "/tasks/:task_id/subtasks/:subtask_id/assignees/:user_id"
+func removeSubtaskAssignee(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	subtask_id, ok := extractQueryParameter(c, "subtask_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidSubtaskId)
+	}
+
+	user_id, ok := extractQueryParameter(c, "user_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidUserId)
+	}
+
+	subtask, err := database.GetDetailsOfSubtask(requester_user_id, task_id, subtask_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	err = database.RemoveAssigneeFromSubtask(requester_user_id, task_id, subtask_id, user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.SubtaskGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Subtask '%s' has been updated.", subtask.Title),
+		},
+	}
+
+	queueSubtaskSnapshotWebhookEvent(requester_user_id, task_id, subtask_id, database.SubtaskUpdatedEvent)
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+const maxBulkTasks = 200
+
+func extractBulkTaskOperation(content map[string]interface{}) (database.BulkTaskOperation, error) {
+	operation := database.BulkTaskOperation{}
+
+	operation_type, ok := content["operation"].(string)
+	if !ok {
+		return operation, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+	operation.Type = operation_type
+
+	switch operation_type {
+	case database.BulkStatusOperation:
+		status, ok := content["status"].(string)
+		if !ok {
+			return operation, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+		} else if status != database.Planned && status != database.Completed && status != database.InProgress {
+			return operation, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidStatus)
+		}
+		operation.Status = status
+		operation.Force, _ = content["force"].(bool)
+
+	case database.BulkAssignOperation:
+		if operation.UserID, ok = extractBodyID(content, "user_id"); !ok {
+			return operation, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidUserId)
+		}
+		operation.Replace, _ = content["replace"].(bool)
+
+	case database.BulkAddLabelOperation:
+		if operation.LabelID, ok = extractBodyID(content, "label_id"); !ok {
+			return operation, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidLabelId)
+		}
+
+	case database.BulkMoveOperation:
+		if operation.TargetWorkspaceID, ok = extractBodyID(content, "target_workspace_id"); !ok {
+			return operation, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+		}
+
+	case database.BulkDeleteOperation, database.BulkArchiveOperation:
+
+	default:
+		return operation, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBulkOperation)
+	}
+
+	return operation, nil
+}
+
+// POST "/workspaces/:workspace_id/tasks/bulk"
+func bulkUpdateTasks(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	task_ids, ok := extractIDList(content, "task_ids")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	} else if len(task_ids) == 0 || len(task_ids) > maxBulkTasks {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskList)
+	}
+
+	operation, err := extractBulkTaskOperation(content)
+	if err != nil {
+		return err
+	}
+
+	results, err := database.BulkUpdateTasks(requester_user_id, workspace_id, task_ids, operation)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	updated := 0
+	for _, result := range results {
+		if !result.Success {
+			continue
+		}
+		updated++
+
+		switch operation.Type {
+		case database.BulkDeleteOperation:
+			queueWebhookEvent(workspace_id, database.TaskDeletedEvent, result.Task)
+		case database.BulkMoveOperation:
+			queueWebhookEvent(workspace_id, database.TaskDeletedEvent, result.Task)
+			queueWebhookEvent(operation.TargetWorkspaceID, database.TaskCreatedEvent, result.Task)
+		default:
+			queueWebhookEvent(workspace_id, database.TaskUpdatedEvent, result.Task)
+		}
+
+		if result.Assigned {
+			websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+				TargetUserIDs: []uint{operation.UserID},
+				Body: &websocket_utils.WebsocketBody{
+					Group:   websocket_utils.TaskGroup,
+					Type:    websocket_utils.WatchType,
+					Message: fmt.Sprintf("Task '%s' has been assigned to you.", result.Task.Title),
+				},
+				Event: database.AssignmentEvent,
+			}
+		}
+	}
+
+	if updated != 0 {
+		workspace_ids := []uint{workspace_id}
+		if operation.Type == database.BulkMoveOperation {
+			workspace_ids = append(workspace_ids, operation.TargetWorkspaceID)
+		}
+
+		for _, id := range workspace_ids {
+			members, err := database.GetWorkspaceMembers(id)
+			if err != nil {
+				monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+				return generateProperResponse(err)
+			}
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+			websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+				TargetUserIDs: members,
+				Body: &websocket_utils.WebsocketBody{
+					Group:   websocket_utils.TaskGroup,
+					Type:    websocket_utils.UpdateType,
+					Message: fmt.Sprintf("%d tasks have been updated.", updated),
+				},
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
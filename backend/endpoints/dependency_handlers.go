@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+// GET "/workspaces/:workspace_id/tasks/:task_id/dependencies"
+func getTaskDependencies(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	dependencies, err := database.GetDependenciesOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, dependencies)
+}
+
+// POST "/workspaces/:workspace_id/tasks/:task_id/dependencies"
+func addTaskDependency(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	var target_task_id uint
+	if tmp, ok := content["target_task_id"].(float64); ok {
+		target_task_id = uint(tmp)
+	} else if tmp, ok := content["target_task_id"].(string); ok {
+		value, err := strconv.ParseUint(tmp, 10, 32)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+		}
+		target_task_id = uint(value)
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	dependency_type, ok := content["type"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	} else if dependency_type != database.Blocks && dependency_type != database.BlockedBy &&
+		dependency_type != database.RelatesTo && dependency_type != database.Duplicates {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidDependencyType)
+	}
+
+	task, err := database.GetDetailsOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	dependency, err := database.AddDependencyToTask(requester_user_id, workspace_id, task_id, target_task_id, dependency_type)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Dependencies of task '%s' have been updated.", task.Title),
+		},
+	}
+
+	return c.JSON(http.StatusCreated, dependency)
+}
+
+// DELETE "/workspaces/:workspace_id/tasks/:task_id/dependencies/:dependency_id"
+func deleteTaskDependency(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	dependency_id, ok := extractQueryParameter(c, "dependency_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidDependencyId)
+	}
+
+	task, err := database.GetDetailsOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	err = database.DeleteDependency(requester_user_id, workspace_id, task_id, dependency_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Dependencies of task '%s' have been updated.", task.Title),
+		},
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GET "/workspaces/:workspace_id/dependencies"
+func getDependencyGraph(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	graph, err := database.GetDependencyGraph(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, graph)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+)
+
+// GET "/users/self/email-settings"
+func getEmailSettings(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	email_settings, err := database.GetEmailSettings(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, email_settings)
+}
+
+// PUT "/users/self/email-settings"
+func updateEmailSettings(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	mode, ok := content["mode"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	} else if mode != database.InstantEmail && mode != database.DigestEmail && mode != database.NoEmail {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidEmailMode)
+	}
+
+	err = database.UpdateEmailSettings(requester_user_id, mode)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// unsubscribePage asks for a confirmation before unsubscribing, since mail
+// scanners follow the links in emails. Mail clients that support one-click
+// unsubscribing post to the link directly.
+const unsubscribePage = `<!DOCTYPE html>
+<html>
+<head><title>Unsubscribe</title></head>
+<body>
+<p>Do you want to stop receiving email notifications?</p>
+<form method="post"><button type="submit">Unsubscribe</button></form>
+</body>
+</html>
+`
+
+// GET "/unsubscribe/:token"
+func confirmUnsubscribe(c echo.Context) error {
+	return c.HTML(http.StatusOK, unsubscribePage)
+}
+
+// POST "/unsubscribe/:token"
+func unsubscribe(c echo.Context) error {
+	err := database.UnsubscribeByToken(c.Param("token"))
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.String(http.StatusOK, custom_messages.Unsubscribed)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/skye-tan/trello/backend/middlewares/authentication"
 	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/middlewares/ratelimit"
 )
 
 func customLogger(next echo.HandlerFunc) echo.HandlerFunc {
@@ -20,6 +21,7 @@ func customLogger(next echo.HandlerFunc) echo.HandlerFunc {
 
 func Start(listen_address string) {
 	e := echo.New()
+	e.IPExtractor = ratelimit.IPExtractor()
 	e.Use(customLogger)
 
 	// Metrics Endpoint
@@ -34,13 +36,29 @@ func Start(listen_address string) {
 	// Statistics Collector Middleware
 	api.Use(monitoring.StatisticsCollectorMiddleware())
 
+	// Rate Limiting Middleware
+	api.Use(ratelimit.APIRateLimitMiddleware)
+
 	// Miscellaneous Endpoints
-	api.POST("/signup", signup)
-	api.POST("/login", login)
+	api.POST("/signup", signup, ratelimit.AuthRateLimitMiddleware)
+	api.POST("/login", login, ratelimit.AuthRateLimitMiddleware)
+	api.POST("/login/2fa", loginTwoFactor, ratelimit.AuthRateLimitMiddleware)
 	api.GET("/ws/:token", websocketHandler)
 	api.GET("/token/validate", validateToken, authentication.AccessJWTMiddleware)
 	api.GET("/token/refresh", refreshToken, authentication.RefreshJWTMiddleware)
 
+	// Account Recovery Endpoints
+	api.POST("/password/forgot", forgotPassword, ratelimit.AuthRateLimitMiddleware)
+	api.POST("/password/reset", resetPassword, ratelimit.AuthRateLimitMiddleware)
+	api.GET("/email/verify/:token", verifyEmail)
+	api.POST("/email/verify/:token", verifyEmail)
+	api.POST("/email/verify/resend", resendEmailVerification, authentication.AccessJWTMiddleware)
+
+	// Single Sign-On Endpoints
+	api.GET("/auth/methods", getAuthMethods)
+	api.GET("/auth/oidc/login", oidcLogin)
+	api.GET("/auth/oidc/callback", oidcCallback)
+
 	// Workspace Endpoints
 	api.GET("/workspaces", getWorkspaces, authentication.AccessJWTMiddleware)
 	api.POST("/workspaces", createWorkspace, authentication.AccessJWTMiddleware)
@@ -48,14 +66,51 @@ func Start(listen_address string) {
 	api.PUT("/workspaces/:workspace_id", updateWorkspace, authentication.AccessJWTMiddleware)
 	api.DELETE("/workspaces/:workspace_id", deleteWorkspace, authentication.AccessJWTMiddleware)
 
+	// Trash Endpoints
+	api.GET("/self/trash", getTrashedWorkspaces, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/restore", restoreWorkspace, authentication.AccessJWTMiddleware)
+	api.GET("/workspaces/:workspace_id/trash", getTrash, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/trash/:trash_id/restore", restoreFromTrash, authentication.AccessJWTMiddleware)
+
+	// Archive Endpoints
+	api.POST("/workspaces/:workspace_id/tasks/archive-completed", archiveCompletedTasks, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/tasks/:task_id/archive", archiveTask, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/tasks/:task_id/unarchive", unarchiveTask, authentication.AccessJWTMiddleware)
+	api.GET("/workspaces/:workspace_id/archive-policy", getArchivePolicy, authentication.AccessJWTMiddleware)
+	api.PUT("/workspaces/:workspace_id/archive-policy", updateArchivePolicy, authentication.AccessJWTMiddleware)
+
+	// Export Endpoints
+	api.GET("/workspaces/:workspace_id/export", exportWorkspace, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/import", importWorkspace, authentication.AccessJWTMiddleware)
+
+	// Import Endpoints
+	api.GET("/self/imports", getImportJobs, authentication.AccessJWTMiddleware)
+	api.GET("/self/imports/:import_id", getImportJob, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/import/trello", importTrelloBoard, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/import/csv", importCSV, authentication.AccessJWTMiddleware)
+
+	// Report Endpoints
+	api.GET("/workspaces/:workspace_id/tasks.csv", exportTasksCSV, authentication.AccessJWTMiddleware)
+	api.GET("/self/calendar", getCalendarFeed, authentication.AccessJWTMiddleware)
+	api.POST("/self/calendar/token", regenerateCalendarFeed, authentication.AccessJWTMiddleware)
+	api.GET("/calendar/:token", getCalendar)
+
+	// Move Endpoints
+	api.POST("/workspaces/:workspace_id/tasks/:task_id/move", moveTask, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/tasks/:task_id/copy", copyTask, authentication.AccessJWTMiddleware)
+
 	// Task Endpoints
 	api.GET("/self/tasks", getAssignedTasks, authentication.AccessJWTMiddleware)
+	api.GET("/self/tasks/overdue", getOverdueTasks, authentication.AccessJWTMiddleware)
 	api.GET("/workspaces/:workspace_id/tasks", getTasks, authentication.AccessJWTMiddleware)
 	api.POST("/workspaces/:workspace_id/tasks", createTask, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/tasks/bulk", bulkUpdateTasks, authentication.AccessJWTMiddleware)
 	api.GET("/workspaces/:workspace_id/tasks/:task_id", getTask, authentication.AccessJWTMiddleware)
 	api.PUT("/workspaces/:workspace_id/tasks/:task_id", updateTask, authentication.AccessJWTMiddleware)
 	api.PUT("/workspaces/:workspace_id/tasks/:task_id/status", updateTaskStatus, authentication.AccessJWTMiddleware)
 	api.DELETE("/workspaces/:workspace_id/tasks/:task_id", deleteTask, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/tasks/:task_id/assignees", addTaskAssignees, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/tasks/:task_id/assignees/:user_id", removeTaskAssignee, authentication.AccessJWTMiddleware)
 
 	// Subtask Endpoints
 	api.GET("/tasks/:task_id/subtasks", getSubtasks, authentication.AccessJWTMiddleware)
@@ -66,6 +121,8 @@ func Start(listen_address string) {
 	api.PUT("/tasks/:task_id/subtasks/:subtask_id/title", updateSubtaskTitle, authentication.AccessJWTMiddleware)
 	api.PUT("/tasks/:task_id/subtasks/:subtask_id/assigneeid", updateSubtaskAssigneeID, authentication.AccessJWTMiddleware)
 	api.DELETE("/tasks/:task_id/subtasks/:subtask_id", deleteSubtask, authentication.AccessJWTMiddleware)
+	api.POST("/tasks/:task_id/subtasks/:subtask_id/assignees", addSubtaskAssignees, authentication.AccessJWTMiddleware)
+	api.DELETE("/tasks/:task_id/subtasks/:subtask_id/assignees/:user_id", removeSubtaskAssignee, authentication.AccessJWTMiddleware)
 
 	// User-Profile Endpoints
 	api.GET("/users/self/profile", getSelfProfile, authentication.AccessJWTMiddleware)
@@ -82,15 +139,120 @@ func Start(listen_address string) {
 	api.DELETE("/workspaces/:workspace_id/users/:user_id", deleteUserWorkspaceRole, authentication.AccessJWTMiddleware)
 	api.DELETE("/workspaces/:workspace_id/users/leave", leaveUserWorkspaceRole, authentication.AccessJWTMiddleware)
 
+	// Ownership Endpoints
+	api.GET("/workspaces/:workspace_id/ownership-transfer", getOwnershipTransfer, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/ownership-transfer", createOwnershipTransfer, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/ownership-transfer", cancelOwnershipTransfer, authentication.AccessJWTMiddleware)
+	api.GET("/users/self/ownership-transfers", getSelfOwnershipTransfers, authentication.AccessJWTMiddleware)
+	api.POST("/users/self/ownership-transfers/:transfer_id/accept", acceptOwnershipTransfer, authentication.AccessJWTMiddleware)
+	api.POST("/users/self/ownership-transfers/:transfer_id/decline", declineOwnershipTransfer, authentication.AccessJWTMiddleware)
+
+	// Role Endpoints
+	api.GET("/workspaces/:workspace_id/roles", getRoles, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/roles", createRole, authentication.AccessJWTMiddleware)
+	api.PUT("/workspaces/:workspace_id/roles/:role_id", updateRole, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/roles/:role_id", deleteRole, authentication.AccessJWTMiddleware)
+
+	// Invitation Endpoints
+	api.GET("/workspaces/:workspace_id/invitations", getInvitations, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/invitations", createInvitation, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/invitations/:invitation_id", revokeInvitation, authentication.AccessJWTMiddleware)
+	api.GET("/users/self/invitations", getSelfInvitations, authentication.AccessJWTMiddleware)
+	api.POST("/users/self/invitations/:invitation_id/accept", acceptInvitation, authentication.AccessJWTMiddleware)
+	api.POST("/users/self/invitations/:invitation_id/decline", declineInvitation, authentication.AccessJWTMiddleware)
+	api.POST("/invitations/:token/accept", acceptInvitationLink, authentication.AccessJWTMiddleware)
+
 	// Comment Endpoints
 	api.GET("/workspaces/:workspace_id/tasks/:task_id/comments", getComments, authentication.AccessJWTMiddleware)
 	api.POST("/workspaces/:workspace_id/tasks/:task_id/comments", addComment, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/tasks/:task_id/comments/:comment_id", deleteComment, authentication.AccessJWTMiddleware)
 
 	// Watch Endpoints
 	api.GET("/workspaces/:workspace_id/tasks/:task_id/watch", getWatch, authentication.AccessJWTMiddleware)
 	api.POST("/workspaces/:workspace_id/tasks/:task_id/watch", addWatch, authentication.AccessJWTMiddleware)
 	api.DELETE("/workspaces/:workspace_id/tasks/:task_id/watch", deleteWatch, authentication.AccessJWTMiddleware)
 
+	// Label Endpoints
+	api.GET("/workspaces/:workspace_id/labels", getLabels, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/labels", createLabel, authentication.AccessJWTMiddleware)
+	api.PUT("/workspaces/:workspace_id/labels/:label_id", updateLabel, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/labels/:label_id", deleteLabel, authentication.AccessJWTMiddleware)
+	api.GET("/workspaces/:workspace_id/tasks/:task_id/labels", getTaskLabels, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/tasks/:task_id/labels/:label_id", addTaskLabel, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/tasks/:task_id/labels/:label_id", removeTaskLabel, authentication.AccessJWTMiddleware)
+
+	// Dependency Endpoints
+	api.GET("/workspaces/:workspace_id/dependencies", getDependencyGraph, authentication.AccessJWTMiddleware)
+	api.GET("/workspaces/:workspace_id/tasks/:task_id/dependencies", getTaskDependencies, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/tasks/:task_id/dependencies", addTaskDependency, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/tasks/:task_id/dependencies/:dependency_id", deleteTaskDependency, authentication.AccessJWTMiddleware)
+
+	// Recurrence Endpoints
+	api.GET("/workspaces/:workspace_id/recurrences", getRecurrences, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/tasks/:task_id/recurrence", createRecurrence, authentication.AccessJWTMiddleware)
+	api.PUT("/workspaces/:workspace_id/recurrences/:recurrence_id", updateRecurrence, authentication.AccessJWTMiddleware)
+	api.PUT("/workspaces/:workspace_id/recurrences/:recurrence_id/pause", pauseRecurrence, authentication.AccessJWTMiddleware)
+	api.PUT("/workspaces/:workspace_id/recurrences/:recurrence_id/resume", resumeRecurrence, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/recurrences/:recurrence_id", deleteRecurrence, authentication.AccessJWTMiddleware)
+
+	// Template Endpoints
+	api.GET("/self/templates", getTemplates, authentication.AccessJWTMiddleware)
+	api.GET("/self/templates/:template_id", getTemplate, authentication.AccessJWTMiddleware)
+	api.DELETE("/self/templates/:template_id", deleteTemplate, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/template", createBoardTemplate, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/tasks/:task_id/template", createTaskTemplate, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/templates/:template_id", instantiateTemplate, authentication.AccessJWTMiddleware)
+
+	// Notification Endpoints
+	api.GET("/self/notifications", getNotifications, authentication.AccessJWTMiddleware)
+	api.PUT("/self/notifications/read", markAllNotificationsAsRead, authentication.AccessJWTMiddleware)
+	api.PUT("/self/notifications/:notification_id/read", markNotificationAsRead, authentication.AccessJWTMiddleware)
+	api.GET("/self/notifications/preferences", getNotificationPreferences, authentication.AccessJWTMiddleware)
+	api.PUT("/self/notifications/preferences", updateNotificationPreferences, authentication.AccessJWTMiddleware)
+
+	// Email Endpoints
+	api.GET("/users/self/email-settings", getEmailSettings, authentication.AccessJWTMiddleware)
+	api.PUT("/users/self/email-settings", updateEmailSettings, authentication.AccessJWTMiddleware)
+	api.GET("/unsubscribe/:token", confirmUnsubscribe)
+	api.POST("/unsubscribe/:token", unsubscribe)
+
+	// Webhook Endpoints
+	api.GET("/workspaces/:workspace_id/webhooks", getWebhooks, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/webhooks", createWebhook, authentication.AccessJWTMiddleware)
+	api.PUT("/workspaces/:workspace_id/webhooks/:webhook_id", updateWebhook, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/webhooks/:webhook_id", deleteWebhook, authentication.AccessJWTMiddleware)
+	api.GET("/workspaces/:workspace_id/webhooks/:webhook_id/deliveries", getWebhookDeliveries, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/webhooks/:webhook_id/test", testWebhook, authentication.AccessJWTMiddleware)
+
+	// Incoming Webhook Endpoints
+	api.GET("/workspaces/:workspace_id/incoming-webhooks", getIncomingWebhooks, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/incoming-webhooks", createIncomingWebhook, authentication.AccessJWTMiddleware)
+	api.PUT("/workspaces/:workspace_id/incoming-webhooks/:incoming_webhook_id", updateIncomingWebhook, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/incoming-webhooks/:incoming_webhook_id", deleteIncomingWebhook, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/incoming-webhooks/:incoming_webhook_id/token", regenerateIncomingWebhookToken, authentication.AccessJWTMiddleware)
+	api.GET("/workspaces/:workspace_id/tasks/:task_id/source", getTaskSource, authentication.AccessJWTMiddleware)
+	api.POST("/hooks/:token", receiveIncomingWebhook)
+
+	// Token Endpoints
+	api.GET("/users/self/tokens", getPersonalAccessTokens, authentication.AccessJWTMiddleware)
+	api.POST("/users/self/tokens", createPersonalAccessToken, authentication.AccessJWTMiddleware)
+	api.DELETE("/users/self/tokens/:token_id", deletePersonalAccessToken, authentication.AccessJWTMiddleware)
+	api.GET("/workspaces/:workspace_id/service-accounts", getServiceAccounts, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/service-accounts", createServiceAccount, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/service-accounts/:service_account_id", deleteServiceAccount, authentication.AccessJWTMiddleware)
+	api.GET("/workspaces/:workspace_id/service-accounts/:service_account_id/tokens", getServiceAccountTokens, authentication.AccessJWTMiddleware)
+	api.POST("/workspaces/:workspace_id/service-accounts/:service_account_id/tokens", createServiceAccountToken, authentication.AccessJWTMiddleware)
+	api.DELETE("/workspaces/:workspace_id/service-accounts/:service_account_id/tokens/:token_id", deleteServiceAccountToken, authentication.AccessJWTMiddleware)
+
+	// Two-Factor Endpoints
+	api.GET("/users/self/2fa", getTwoFactorStatus, authentication.AccessJWTMiddleware)
+	api.DELETE("/users/self/2fa", disableTwoFactor, authentication.AccessJWTMiddleware)
+	api.POST("/users/self/2fa/enroll", enrollTwoFactor, authentication.AccessJWTMiddleware)
+	api.POST("/users/self/2fa/verify", enableTwoFactor, authentication.AccessJWTMiddleware)
+	api.POST("/users/self/2fa/recovery-codes", regenerateRecoveryCodes, authentication.AccessJWTMiddleware)
+	api.GET("/workspaces/:workspace_id/security", getWorkspaceSecurity, authentication.AccessJWTMiddleware)
+	api.PUT("/workspaces/:workspace_id/security", updateWorkspaceSecurity, authentication.AccessJWTMiddleware)
+
 	// Upload Picture Endpoints
 	api.POST("/upload/picture/:task_id", uploadFile, authentication.AccessJWTMiddleware)
 	api.GET("/retrieve/picture/:task_id", retrieveFile)
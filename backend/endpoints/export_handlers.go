@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+)
+
+// GET "/workspaces/:workspace_id/export"
+func exportWorkspace(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	export, err := database.ExportWorkspace(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, export)
+}
+
+// POST "/workspaces/import"
+func importWorkspace(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	var export database.WorkspaceExport
+	err := json.NewDecoder(c.Request().Body).Decode(&export)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	result, err := database.ImportWorkspace(requester_user_id, export)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+)
+
+func extractImportOptions(content map[string]interface{}) (string, bool, error) {
+	name := ""
+	if _, ok := content["name"]; ok {
+		if name, ok = content["name"].(string); !ok {
+			return "", false, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+		}
+	}
+
+	dry_run := false
+	if _, ok := content["dry_run"]; ok {
+		if dry_run, ok = content["dry_run"].(bool); !ok {
+			return "", false, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+		}
+	}
+
+	return name, dry_run, nil
+}
+
+func queueImportJob(c echo.Context, source string, export database.WorkspaceExport, notes []string, dry_run bool) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	import_job, err := database.CreateImportJob(requester_user_id, source, export, notes, dry_run)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, import_job)
+}
+
+// POST "/workspaces/import/trello"
+func importTrelloBoard(c echo.Context) error {
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	board, ok := content["board"].(map[string]interface{})
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	list_statuses := map[string]string{}
+	if _, ok := content["list_statuses"]; ok {
+		if list_statuses, ok = extractStringMap(content, "list_statuses"); !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTrelloBoard)
+		}
+	}
+
+	name, dry_run, err := extractImportOptions(content)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTrelloBoard)
+	}
+
+	export, notes, err := database.ConvertTrelloBoard(data, list_statuses, name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTrelloBoard)
+	}
+
+	return queueImportJob(c, database.TrelloImportSource, export, notes, dry_run)
+}
+
+// POST "/workspaces/import/csv"
+func importCSV(c echo.Context) error {
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	data, ok := content["csv"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	columns := map[string]string{}
+	if _, ok := content["columns"]; ok {
+		if columns, ok = extractStringMap(content, "columns"); !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidCSVImport)
+		}
+	}
+
+	name, dry_run, err := extractImportOptions(content)
+	if err != nil {
+		return err
+	}
+
+	export, notes, err := database.ConvertCSV(data, columns, name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidCSVImport)
+	}
+
+	return queueImportJob(c, database.CSVImportSource, export, notes, dry_run)
+}
+
+// GET "/self/imports"
+func getImportJobs(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	import_jobs, err := database.GetImportJobs(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, import_jobs)
+}
+
+// GET "/self/imports/:import_id"
+func getImportJob(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	import_id, ok := extractQueryParameter(c, "import_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidImportId)
+	}
+
+	import_job, err := database.GetImportJob(requester_user_id, import_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, import_job)
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+)
+
+var incomingWebhookFields = []string{
+	"title", "description", "estimated_time", "actual_time", "due_date",
+	"priority", "image_url", "assignee_ids", "external_id", "source_url",
+}
+
+func isIncomingWebhookField(field string) bool {
+	for _, item := range incomingWebhookFields {
+		if item == field {
+			return true
+		}
+	}
+	return false
+}
+
+func extractFieldMap(content map[string]interface{}, key string) (map[string]string, bool) {
+	values := map[string]string{}
+	raw, ok := content[key]
+	if !ok {
+		return values, true
+	}
+	items, ok := raw.(map[string]interface{})
+	if !ok {
+		return map[string]string{}, false
+	}
+	for field, item := range items {
+		value, ok := item.(string)
+		if !ok || !isIncomingWebhookField(field) {
+			return map[string]string{}, false
+		}
+		values[field] = value
+	}
+	return values, true
+}
+
+func lookupPayloadPath(payload map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = payload
+	for _, key := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[key]
+		if !ok || current == nil {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func normalizePayloadValue(field string, value interface{}) interface{} {
+	switch value := value.(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case string:
+		if field == "assignee_ids" {
+			items := []interface{}{}
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			return items
+		} else if field == "due_date" {
+			if due_date, err := time.Parse(time.RFC3339, value); err == nil {
+				return due_date.Format("2006-01-02")
+			}
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// mapIncomingPayload translates an external payload into the body accepted
+// by createTask, using the configured field mapping and default values.
+func mapIncomingPayload(incoming_webhook database.IncomingWebhook, payload map[string]interface{}) map[string]interface{} {
+	content := map[string]interface{}{
+		"description":    "",
+		"estimated_time": "0",
+		"actual_time":    "0",
+		"due_date":       time.Now().Format("2006-01-02"),
+		"priority":       "0",
+		"image_url":      "",
+		"external_id":    "",
+		"source_url":     "",
+	}
+
+	for _, field := range incomingWebhookFields {
+		path, ok := incoming_webhook.FieldMapping[field]
+		if !ok {
+			path = field
+		}
+
+		if value, ok := lookupPayloadPath(payload, path); ok {
+			content[field] = normalizePayloadValue(field, value)
+		} else if value, ok := incoming_webhook.DefaultValues[field]; ok {
+			content[field] = normalizePayloadValue(field, value)
+		}
+	}
+
+	return content
+}
+
+// GET "/workspaces/:workspace_id/incoming-webhooks"
+func getIncomingWebhooks(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	incoming_webhooks, err := database.GetIncomingWebhooksInWorkspace(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, incoming_webhooks)
+}
+
+// POST "/workspaces/:workspace_id/incoming-webhooks"
+func createIncomingWebhook(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	name, ok := content["name"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	field_mapping, ok := extractFieldMap(content, "field_mapping")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidFieldMapping)
+	}
+
+	default_values, ok := extractFieldMap(content, "default_values")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidFieldMapping)
+	}
+
+	incoming_webhook, err := database.CreateIncomingWebhookInWorkspace(requester_user_id, workspace_id, name, field_mapping, default_values)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, incoming_webhook)
+}
+
+// PUT "/workspaces/:workspace_id/incoming-webhooks/:incoming_webhook_id"
+func updateIncomingWebhook(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	incoming_webhook_id, ok := extractQueryParameter(c, "incoming_webhook_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWebhookId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	name, ok := content["name"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	field_mapping, ok := extractFieldMap(content, "field_mapping")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidFieldMapping)
+	}
+
+	default_values, ok := extractFieldMap(content, "default_values")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidFieldMapping)
+	}
+
+	err = database.UpdateIncomingWebhook(requester_user_id, workspace_id, incoming_webhook_id, name, field_mapping, default_values)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// POST "/workspaces/:workspace_id/incoming-webhooks/:incoming_webhook_id/token"
+func regenerateIncomingWebhookToken(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	incoming_webhook_id, ok := extractQueryParameter(c, "incoming_webhook_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWebhookId)
+	}
+
+	incoming_webhook, err := database.RegenerateIncomingWebhookToken(requester_user_id, workspace_id, incoming_webhook_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, incoming_webhook)
+}
+
+// DELETE "/workspaces/:workspace_id/incoming-webhooks/:incoming_webhook_id"
+func deleteIncomingWebhook(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	incoming_webhook_id, ok := extractQueryParameter(c, "incoming_webhook_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWebhookId)
+	}
+
+	err := database.DeleteIncomingWebhook(requester_user_id, workspace_id, incoming_webhook_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusOK)
+}
+
+// POST "/hooks/:token"
+func receiveIncomingWebhook(c echo.Context) error {
+	incoming_webhook, err := database.GetIncomingWebhookByToken(c.Param("token"))
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	payload := make(map[string]interface{})
+	err = json.NewDecoder(c.Request().Body).Decode(&payload)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	content := mapIncomingPayload(incoming_webhook, payload)
+
+	task_content, err := extractTaskContent(content)
+	if err != nil {
+		return err
+	}
+
+	external_id, ok := content["external_id"].(string)
+	if !ok || len(external_id) > 100 {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidExternalId)
+	}
+
+	source_url, ok := content["source_url"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidURL)
+	}
+
+	task, created, err := database.CreateTaskFromIncomingWebhook(incoming_webhook, external_id, source_url, task_content.Title, task_content.Description, task_content.EstimatedTime, task_content.ActualTime, task_content.DueDate, task_content.Priority, task_content.AssigneeIDs, task_content.ImageURL)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if !created {
+		return c.JSON(http.StatusOK, task)
+	}
+
+	if err := announceTaskCreation(incoming_webhook.WorkspaceID, task); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, task)
+}
+
+// GET "/workspaces/:workspace_id/tasks/:task_id/source"
+func getTaskSource(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	task_source, err := database.GetTaskSource(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, task_source)
+}
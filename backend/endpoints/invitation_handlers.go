@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	regex_utils "github.com/skye-tan/trello/backend/utils/regex"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+func generateInvitationResponse(err error) *echo.HTTPError {
+	if err == custom_errors.ErrInvalidArguments {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidInvitation)
+	}
+	return generateProperResponse(err)
+}
+
+// GET "/workspaces/:workspace_id/invitations"
+func getInvitations(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	invitations, err := database.GetInvitationsInWorkspace(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, invitations)
+}
+
+// notifyInvitee tells the invited user about a direct invitation.
+func notifyInvitee(invitation database.Invitation) {
+	if invitation.Kind != database.DirectInvitation {
+		return
+	}
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: []uint{invitation.InviteeUserID},
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.WorkspaceGroup,
+			Type:    websocket_utils.WatchType,
+			Message: fmt.Sprintf("You have been invited to join workspace '%s' as %s.", invitation.WorkspaceName, invitation.Role),
+		},
+		Event: database.InvitationEvent,
+	}
+}
+
+// POST "/workspaces/:workspace_id/invitations"
+func createInvitation(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	username, _ := content["username"].(string)
+	email, _ := content["email"].(string)
+	if username != "" && email != "" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	} else if email != "" && !regex_utils.ValidateEmail(email) {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidEmailFormat)
+	}
+
+	role, ok := content["role"].(string)
+	if !ok {
+		role = database.StandardUser
+	} else if role == "" || role == database.Owner {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidRole)
+	}
+
+	expires_at, ok := extractExpiry(content, "expires_at")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidDate)
+	}
+
+	var max_uses uint
+	if tmp, ok := content["max_uses"].(float64); ok {
+		if tmp < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+		}
+		max_uses = uint(tmp)
+	}
+
+	invitation, err := database.CreateInvitation(requester_user_id, workspace_id, username, email, role, expires_at, max_uses)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	notifyInvitee(invitation)
+
+	return c.JSON(http.StatusCreated, invitation)
+}
+
+// DELETE "/workspaces/:workspace_id/invitations/:invitation_id"
+func revokeInvitation(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	invitation_id, ok := extractQueryParameter(c, "invitation_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidInvitationId)
+	}
+
+	err := database.RevokeInvitation(requester_user_id, workspace_id, invitation_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateInvitationResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GET "/users/self/invitations"
+func getSelfInvitations(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	invitations, err := database.GetInvitationsOfUser(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, invitations)
+}
+
+// POST "/users/self/invitations/:invitation_id/accept"
+func acceptInvitation(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	invitation_id, ok := extractQueryParameter(c, "invitation_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidInvitationId)
+	}
+
+	user_workspace_role, err := database.AcceptInvitation(requester_user_id, invitation_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateInvitationResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	err = announceMemberAddition(user_workspace_role.WorkspaceID, user_workspace_role)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, user_workspace_role)
+}
+
+// POST "/users/self/invitations/:invitation_id/decline"
+func declineInvitation(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	invitation_id, ok := extractQueryParameter(c, "invitation_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidInvitationId)
+	}
+
+	err := database.DeclineInvitation(requester_user_id, invitation_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateInvitationResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// POST "/invitations/:token/accept"
+func acceptInvitationLink(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	user_workspace_role, err := database.AcceptInvitationLink(requester_user_id, c.Param("token"))
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateInvitationResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	err = announceMemberAddition(user_workspace_role.WorkspaceID, user_workspace_role)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, user_workspace_role)
+}
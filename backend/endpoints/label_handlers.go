@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	regex_utils "github.com/skye-tan/trello/backend/utils/regex"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+// GET "/workspaces/:workspace_id/labels"
+func getLabels(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	labels, err := database.GetAllLabelsInWorkspace(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, labels)
+}
+
+// POST "/workspaces/:workspace_id/labels"
+func createLabel(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	name, ok := content["name"].(string)
+	if !ok || name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	color, ok := content["color"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	} else if !regex_utils.ValidateColor(color) {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidColor)
+	}
+
+	label, err := database.CreateLabelInWorkspace(requester_user_id, workspace_id, name, color)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	members, err := database.GetWorkspaceMembers(workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: members,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.LabelGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Label '%s' has been created.", label.Name),
+		},
+	}
+
+	return c.JSON(http.StatusCreated, label)
+}
+
+// PUT "/workspaces/:workspace_id/labels/:label_id"
+func updateLabel(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	label_id, ok := extractQueryParameter(c, "label_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidLabelId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	label, err := database.GetDetailsOfLabel(requester_user_id, workspace_id, label_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	name, ok := content["name"].(string)
+	if !ok || name == "" {
+		name = label.Name
+	}
+
+	color, ok := content["color"].(string)
+	if !ok {
+		color = label.Color
+	} else if !regex_utils.ValidateColor(color) {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidColor)
+	}
+
+	err = database.UpdateDetailsOfLabel(requester_user_id, workspace_id, label_id, name, color)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	members, err := database.GetWorkspaceMembers(workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: members,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.LabelGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Label '%s' has been updated.", name),
+		},
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// DELETE "/workspaces/:workspace_id/labels/:label_id"
+func deleteLabel(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	label_id, ok := extractQueryParameter(c, "label_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidLabelId)
+	}
+
+	label, err := database.GetDetailsOfLabel(requester_user_id, workspace_id, label_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	err = database.DeleteLabel(requester_user_id, workspace_id, label_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	members, err := database.GetWorkspaceMembers(workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: members,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.LabelGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Label '%s' has been deleted.", label.Name),
+		},
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GET "/workspaces/:workspace_id/tasks/:task_id/labels"
+func getTaskLabels(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	labels, err := database.GetLabelsOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, labels)
+}
+
+// POST "/workspaces/:workspace_id/tasks/:task_id/labels/:label_id"
+func addTaskLabel(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	label_id, ok := extractQueryParameter(c, "label_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidLabelId)
+	}
+
+	task, err := database.GetDetailsOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	label, err := database.GetDetailsOfLabel(requester_user_id, workspace_id, label_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	err = database.AddLabelToTask(requester_user_id, workspace_id, task_id, label_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.LabelGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Label '%s' has been added to task '%s'.", label.Name, task.Title),
+		},
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// DELETE "/workspaces/:workspace_id/tasks/:task_id/labels/:label_id"
+func removeTaskLabel(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	label_id, ok := extractQueryParameter(c, "label_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidLabelId)
+	}
+
+	task, err := database.GetDetailsOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	label, err := database.GetDetailsOfLabel(requester_user_id, workspace_id, label_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	err = database.RemoveLabelFromTask(requester_user_id, workspace_id, task_id, label_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.LabelGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Label '%s' has been removed from task '%s'.", label.Name, task.Title),
+		},
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/skye-tan/trello/backend/database"
 	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/middlewares/ratelimit"
 	"github.com/skye-tan/trello/backend/utils/custom_messages"
 	hashing_utils "github.com/skye-tan/trello/backend/utils/hashing"
+	oidc_utils "github.com/skye-tan/trello/backend/utils/oidc"
 	regex_utils "github.com/skye-tan/trello/backend/utils/regex"
 	token_utils "github.com/skye-tan/trello/backend/utils/token"
 	"github.com/skye-tan/trello/backend/websocket_utils"
@@ -18,6 +20,10 @@ import (
 
 // POST "/signup"
 func signup(c echo.Context) error {
+	if !oidc_utils.PasswordLoginEnabled() {
+		return echo.NewHTTPError(http.StatusForbidden, custom_messages.PasswordDisabled)
+	}
+
 	content_type := c.Request().Header.Get(echo.HeaderContentType)
 	if content_type != "application/json" {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
@@ -62,6 +68,10 @@ func signup(c echo.Context) error {
 
 // POST "/login"
 func login(c echo.Context) error {
+	if !oidc_utils.PasswordLoginEnabled() {
+		return echo.NewHTTPError(http.StatusForbidden, custom_messages.PasswordDisabled)
+	}
+
 	content_type := c.Request().Header.Get(echo.HeaderContentType)
 	if content_type != "application/json" {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
@@ -83,15 +93,44 @@ func login(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
 	}
 
+	if err := ratelimit.CheckLoginAttempt(c, username); err != nil {
+		return err
+	}
+
 	user, err := database.GetUserByUsername(username)
 	if err != nil {
+		ratelimit.RegisterLoginFailure(c, username)
 		return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidCredentials)
 	}
 
 	if !bytes.Equal(user.PasswordHash, hashing_utils.HashUsingSha256(password)) {
+		ratelimit.RegisterLoginFailure(c, username)
 		return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidCredentials)
 	}
 
+	two_factor_enabled, err := database.IsTwoFactorEnabled(user.ID)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if two_factor_enabled {
+		two_factor_token, err := token_utils.GenerateToken(user.ID, token_utils.TwoFactor)
+		if err != nil {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+			return generateProperResponse(err)
+		}
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"TwoFactorRequired": true,
+			"TwoFactorToken":    two_factor_token,
+		})
+	}
+
+	ratelimit.RegisterLoginSuccess(c, user.Username)
+
 	refresh_token, access_token, err := token_utils.GenerateTokens(user.ID)
 	if err != nil {
 		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
@@ -118,7 +157,7 @@ func websocketHandler(c echo.Context) error {
 
 	user_id := claims.UserID
 
-	_, err = database.GetUserByID(user_id)
+	err = database.ValidateSession(user_id, claims.IssuedTime())
 	if err != nil {
 		return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidToken)
 	}
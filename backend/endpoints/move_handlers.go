@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+func extractTargetWorkspaceID(c echo.Context, default_workspace_id uint) (uint, error) {
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	if _, ok := content["target_workspace_id"]; !ok && default_workspace_id != 0 {
+		return default_workspace_id, nil
+	}
+
+	target_workspace_id, ok := extractBodyID(content, "target_workspace_id")
+	if !ok {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	return target_workspace_id, nil
+}
+
+func broadcastToWorkspace(workspace_id uint, message string) error {
+	members, err := database.GetWorkspaceMembers(workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: members,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: message,
+		},
+	}
+
+	return nil
+}
+
+// POST "/workspaces/:workspace_id/tasks/:task_id/move"
+func moveTask(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	target_workspace_id, err := extractTargetWorkspaceID(c, 0)
+	if err != nil {
+		return err
+	}
+
+	watchers, err := database.GetWatchers(task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	task, err := database.MoveTaskToWorkspace(requester_user_id, workspace_id, task_id, target_workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: watchers,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.WatchType,
+			Message: fmt.Sprintf("Task '%s' has been moved to another workspace.", task.Title),
+		},
+		Event: database.WatchEvent,
+	}
+
+	if err := broadcastToWorkspace(workspace_id, fmt.Sprintf("Task '%s' has been moved to another workspace.", task.Title)); err != nil {
+		return err
+	}
+
+	if err := broadcastToWorkspace(target_workspace_id, fmt.Sprintf("Task '%s' has been moved into this workspace.", task.Title)); err != nil {
+		return err
+	}
+
+	queueWebhookEvent(workspace_id, database.TaskDeletedEvent, task)
+	queueWebhookEvent(target_workspace_id, database.TaskCreatedEvent, task)
+
+	return c.JSON(http.StatusCreated, task)
+}
+
+// POST "/workspaces/:workspace_id/tasks/:task_id/copy"
+func copyTask(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	target_workspace_id, err := extractTargetWorkspaceID(c, workspace_id)
+	if err != nil {
+		return err
+	}
+
+	task, err := database.CopyTaskToWorkspace(requester_user_id, workspace_id, task_id, target_workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if target_workspace_id != workspace_id {
+		if err := broadcastToWorkspace(workspace_id, fmt.Sprintf("Task '%s' has been copied to another workspace.", task.Title)); err != nil {
+			return err
+		}
+	}
+
+	if err := broadcastToWorkspace(target_workspace_id, fmt.Sprintf("Task '%s' has been created.", task.Title)); err != nil {
+		return err
+	}
+
+	queueWebhookEvent(target_workspace_id, database.TaskCreatedEvent, task)
+
+	return c.JSON(http.StatusCreated, task)
+}
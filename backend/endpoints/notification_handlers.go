@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+)
+
+const maxNotificationsPerPage = 100
+
+// GET "/self/notifications"
+func getNotifications(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	page, ok := extractQueryNumber(c, "page", 1)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidPagination)
+	}
+
+	per_page, ok := extractQueryNumber(c, "per_page", 20)
+	if !ok || per_page > maxNotificationsPerPage {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidPagination)
+	}
+
+	unread_only := c.QueryParam("unread") == "true"
+
+	notification_page, err := database.GetNotificationsOfUser(requester_user_id, unread_only, page, per_page)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, notification_page)
+}
+
+// PUT "/self/notifications/:notification_id/read"
+func markNotificationAsRead(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	notification_id, ok := extractQueryParameter(c, "notification_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidNotificationId)
+	}
+
+	err := database.MarkNotificationAsRead(requester_user_id, notification_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// PUT "/self/notifications/read"
+func markAllNotificationsAsRead(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	err := database.MarkAllNotificationsAsRead(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// GET "/self/notifications/preferences"
+func getNotificationPreferences(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	preferences, err := database.GetNotificationPreferences(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, preferences)
+}
+
+// PUT "/self/notifications/preferences"
+func updateNotificationPreferences(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	preferences := []database.NotificationPreference{}
+	for event, value := range content {
+		enabled, ok := value.(bool)
+		if !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+		}
+		preferences = append(preferences, database.NotificationPreference{Event: event, Enabled: enabled})
+	}
+
+	err = database.UpdateNotificationPreferences(requester_user_id, preferences)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	oidc_utils "github.com/skye-tan/trello/backend/utils/oidc"
+	token_utils "github.com/skye-tan/trello/backend/utils/token"
+)
+
+type oidcState struct {
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// GET "/auth/methods"
+func getAuthMethods(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]bool{
+		"password": oidc_utils.PasswordLoginEnabled(),
+		"oidc":     oidc_utils.Enabled(),
+	})
+}
+
+// GET "/auth/oidc/login"
+func oidcLogin(c echo.Context) error {
+	if !oidc_utils.Enabled() {
+		return echo.NewHTTPError(http.StatusNotFound, custom_messages.SSODisabled)
+	}
+
+	state, err := oidc_utils.RandomString()
+	if err != nil {
+		log.Println("Error:", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	nonce, err := oidc_utils.RandomString()
+	if err != nil {
+		log.Println("Error:", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	verifier, err := oidc_utils.RandomString()
+	if err != nil {
+		log.Println("Error:", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	encoded, _ := json.Marshal(oidcState{Nonce: nonce, Verifier: verifier})
+
+	err = database.SaveOIDCState(state, string(encoded), 10*time.Minute)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	authorization_url, err := oidc_utils.AuthorizationURL(state, nonce, verifier)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+
+	return c.Redirect(http.StatusFound, authorization_url)
+}
+
+// GET "/auth/oidc/callback"
+func oidcCallback(c echo.Context) error {
+	if !oidc_utils.Enabled() {
+		return echo.NewHTTPError(http.StatusNotFound, custom_messages.SSODisabled)
+	}
+
+	if c.QueryParam("error") != "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, c.QueryParam("error"))
+	}
+
+	code := c.QueryParam("code")
+	if code == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	encoded, err := database.TakeOIDCState(c.QueryParam("state"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidSSOState)
+	}
+
+	var state oidcState
+	if err := json.Unmarshal([]byte(encoded), &state); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidSSOState)
+	}
+
+	claims, err := oidc_utils.Exchange(code, state.Verifier, state.Nonce)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+
+	username_hint := claims.PreferredUsername
+	if username_hint == "" {
+		username_hint = claims.Name
+	}
+	if username_hint == "" {
+		username_hint = claims.Email
+	}
+
+	user, err := database.ResolveOIDCUser(oidc_utils.Issuer(), claims.Subject, claims.Email, claims.EmailVerified, username_hint, oidc_utils.AutoProvisionEnabled())
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	two_factor_enabled, err := database.IsTwoFactorEnabled(user.ID)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if two_factor_enabled {
+		two_factor_token, err := token_utils.GenerateToken(user.ID, token_utils.TwoFactor)
+		if err != nil {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+			return generateProperResponse(err)
+		}
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+		if post_login_url := oidc_utils.PostLoginURL(); post_login_url != "" {
+			fragment := url.Values{}
+			fragment.Set("two_factor_token", two_factor_token)
+			return c.Redirect(http.StatusFound, post_login_url+"#"+fragment.Encode())
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"TwoFactorRequired": true,
+			"TwoFactorToken":    two_factor_token,
+		})
+	}
+
+	refresh_token, access_token, err := token_utils.GenerateTokens(user.ID)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if post_login_url := oidc_utils.PostLoginURL(); post_login_url != "" {
+		fragment := url.Values{}
+		fragment.Set("access_token", access_token)
+		fragment.Set("refresh_token", refresh_token)
+		return c.Redirect(http.StatusFound, post_login_url+"#"+fragment.Encode())
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"AccessToken":  access_token,
+		"RefreshToken": refresh_token,
+	})
+}
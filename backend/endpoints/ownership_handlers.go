@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+func generateTransferResponse(err error) *echo.HTTPError {
+	if err == custom_errors.ErrInvalidArguments {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTransfer)
+	}
+	return generateProperResponse(err)
+}
+
+// GET "/workspaces/:workspace_id/ownership-transfer"
+func getOwnershipTransfer(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	ownership_transfer, err := database.GetOwnershipTransferInWorkspace(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateTransferResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, ownership_transfer)
+}
+
+// POST "/workspaces/:workspace_id/ownership-transfer"
+func createOwnershipTransfer(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	tmp, ok := content["user_id"].(string)
+	user_id, err := strconv.ParseUint(tmp, 10, 32)
+	if !ok || err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	stay_owner, _ := content["stay_owner"].(bool)
+
+	ownership_transfer, err := database.CreateOwnershipTransfer(requester_user_id, workspace_id, uint(user_id), stay_owner)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: []uint{ownership_transfer.ToUserID},
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.WorkspaceGroup,
+			Type:    websocket_utils.WatchType,
+			Message: fmt.Sprintf("You have been offered ownership of workspace '%s'.", ownership_transfer.WorkspaceName),
+		},
+		Event: database.RoleEvent,
+	}
+
+	return c.JSON(http.StatusCreated, ownership_transfer)
+}
+
+// DELETE "/workspaces/:workspace_id/ownership-transfer"
+func cancelOwnershipTransfer(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	err := database.CancelOwnershipTransfer(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateTransferResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GET "/users/self/ownership-transfers"
+func getSelfOwnershipTransfers(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	ownership_transfers, err := database.GetOwnershipTransfersOfUser(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, ownership_transfers)
+}
+
+// POST "/users/self/ownership-transfers/:transfer_id/accept"
+func acceptOwnershipTransfer(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	transfer_id, ok := extractQueryParameter(c, "transfer_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTransferId)
+	}
+
+	ownership_transfer, err := database.AcceptOwnershipTransfer(requester_user_id, transfer_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateTransferResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	workspace_id := ownership_transfer.WorkspaceID
+
+	members, err := database.GetWorkspaceMembers(workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: members,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.MemeberGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: "Member has been updated.",
+		},
+	}
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: []uint{ownership_transfer.FromUserID},
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.WorkspaceGroup,
+			Type:    websocket_utils.WatchType,
+			Message: fmt.Sprintf("Ownership of workspace '%s' has been accepted.", ownership_transfer.WorkspaceName),
+		},
+		Event: database.RoleEvent,
+	}
+
+	queueWebhookEvent(workspace_id, database.MemberUpdatedEvent, map[string]interface{}{
+		"user_id":      requester_user_id,
+		"workspace_id": workspace_id,
+		"role":         database.Owner,
+	})
+
+	if !ownership_transfer.StayOwner {
+		queueWebhookEvent(workspace_id, database.MemberUpdatedEvent, map[string]interface{}{
+			"user_id":      ownership_transfer.FromUserID,
+			"workspace_id": workspace_id,
+			"role":         database.Admin,
+		})
+	}
+
+	return c.JSON(http.StatusCreated, ownership_transfer)
+}
+
+// POST "/users/self/ownership-transfers/:transfer_id/decline"
+func declineOwnershipTransfer(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	transfer_id, ok := extractQueryParameter(c, "transfer_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTransferId)
+	}
+
+	err := database.DeclineOwnershipTransfer(requester_user_id, transfer_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateTransferResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"github.com/skye-tan/trello/backend/database"
 	"github.com/skye-tan/trello/backend/middlewares/monitoring"
 	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	regex_utils "github.com/skye-tan/trello/backend/utils/regex"
 	"github.com/skye-tan/trello/backend/websocket_utils"
 )
 
@@ -77,25 +79,80 @@ func addComment(c echo.Context) error {
 	}
 	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
 
+	// The comment is already saved, so failing to notify about it is logged
+	// rather than reported to the requester.
 	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
 	if err != nil {
 		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
-		return generateProperResponse(err)
+		log.Println("Error:", err)
+	} else {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+		websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+			TargetUserIDs: associated_users,
+			Body: &websocket_utils.WebsocketBody{
+				Group:   websocket_utils.CommentGroup,
+				Type:    websocket_utils.UpdateType,
+				Message: text,
+			},
+		}
 	}
-	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
 
-	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
-		TargetUserIDs: associated_users,
-		Body: &websocket_utils.WebsocketBody{
-			Group:   websocket_utils.CommentGroup,
-			Type:    websocket_utils.UpdateType,
-			Message: text,
-		},
+	mentions := regex_utils.ExtractMentions(text)
+	if len(mentions) != 0 {
+		mentioned_users, err := database.GetMentionedUsersInWorkspace(workspace_id, mentions)
+		if err != nil {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+			log.Println("Error:", err)
+		} else {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+			websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+				TargetUserIDs: mentioned_users,
+				Body: &websocket_utils.WebsocketBody{
+					Group:   websocket_utils.CommentGroup,
+					Type:    websocket_utils.WatchType,
+					Message: fmt.Sprintf("You have been mentioned in a comment: %s", text),
+				},
+				Event: database.MentionEvent,
+			}
+		}
 	}
 
+	queueWebhookEvent(workspace_id, database.CommentCreatedEvent, comment)
+
 	return c.JSON(http.StatusCreated, comment)
 }
 
+// DELETE "/workspaces/:workspace_id/tasks/:task_id/comments/:comment_id"
+func deleteComment(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	comment_id, ok := extractQueryParameter(c, "comment_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidCommentId)
+	}
+
+	err := database.DeleteComment(requester_user_id, task_id, workspace_id, comment_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusOK)
+}
+
 // GET "/workspaces/:workspace_id/tasks/:task_id/watch"
 func getWatch(c echo.Context) error {
 	requester_user_id := getUserIDFromContext(c)
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	calendar_utils "github.com/skye-tan/trello/backend/utils/calendar"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	email_utils "github.com/skye-tan/trello/backend/utils/email"
+)
+
+func formatReportTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.Format(time.RFC3339)
+}
+
+// taskReportColumns are the columns the CSV report can be asked for.
+var taskReportColumns = map[string]func(database.TaskReportRow) string{
+	"id":             func(row database.TaskReportRow) string { return strconv.FormatUint(uint64(row.ID), 10) },
+	"title":          func(row database.TaskReportRow) string { return row.Title },
+	"description":    func(row database.TaskReportRow) string { return row.Description },
+	"status":         func(row database.TaskReportRow) string { return row.Status },
+	"priority":       func(row database.TaskReportRow) string { return strconv.Itoa(row.Priority) },
+	"estimated_time": func(row database.TaskReportRow) string { return strconv.Itoa(row.EstimatedTime) },
+	"actual_time":    func(row database.TaskReportRow) string { return strconv.Itoa(row.ActualTime) },
+	"due_date":       func(row database.TaskReportRow) string { return formatReportTime(row.DueDate) },
+	"assignees":      func(row database.TaskReportRow) string { return strings.Join(row.Assignees, "; ") },
+	"labels":         func(row database.TaskReportRow) string { return strings.Join(row.Labels, "; ") },
+	"is_archived":    func(row database.TaskReportRow) string { return strconv.FormatBool(row.IsArchived) },
+	"created_at":     func(row database.TaskReportRow) string { return formatReportTime(row.CreatedAt) },
+	"updated_at":     func(row database.TaskReportRow) string { return formatReportTime(row.UpdatedAt) },
+}
+
+var defaultTaskReportColumns = []string{"id", "title", "status", "priority", "due_date", "assignees", "labels"}
+
+func extractReportColumns(c echo.Context) ([]string, bool) {
+	raw := c.QueryParam("columns")
+	if raw == "" {
+		return defaultTaskReportColumns, true
+	}
+
+	columns := []string{}
+	for _, column := range strings.Split(raw, ",") {
+		column = strings.TrimSpace(column)
+		if _, ok := taskReportColumns[column]; !ok {
+			return []string{}, false
+		}
+		columns = append(columns, column)
+	}
+	return columns, true
+}
+
+// GET "/workspaces/:workspace_id/tasks.csv"
+func exportTasksCSV(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	label_ids, archived, search, err := extractTaskFilters(c)
+	if err != nil {
+		return err
+	}
+
+	columns, ok := extractReportColumns(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidReportColumns)
+	}
+
+	rows, err := database.GetTaskReport(requester_user_id, workspace_id, label_ids, archived, search)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	writer.Write(columns)
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = escapeCSVCell(taskReportColumns[column](row))
+		}
+		writer.Write(record)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=\"workspace-%d-tasks.csv\"", workspace_id))
+
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buffer.Bytes())
+}
+
+// escapeCSVCell keeps spreadsheet applications from evaluating cells that
+// start like a formula.
+func escapeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// GET "/self/calendar"
+func getCalendarFeed(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	calendar_feed, err := database.GetCalendarFeed(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	calendar_feed.URL = email_utils.CalendarURL(calendar_feed.Token)
+
+	return c.JSON(http.StatusOK, calendar_feed)
+}
+
+// POST "/self/calendar/token"
+func regenerateCalendarFeed(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	calendar_feed, err := database.RegenerateCalendarFeed(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	calendar_feed.URL = email_utils.CalendarURL(calendar_feed.Token)
+
+	return c.JSON(http.StatusCreated, calendar_feed)
+}
+
+// GET "/calendar/:token"
+func getCalendar(c echo.Context) error {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	if token == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidCalendarToken)
+	}
+
+	user_id, err := database.GetCalendarFeedUserID(token)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	tasks, err := database.GetAssignedTasks(user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	events := []calendar_utils.Event{}
+	for _, task := range tasks {
+		if task.DueDate.IsZero() {
+			continue
+		}
+
+		description := "Status: " + task.Status
+		if task.Description != "" {
+			description += "\n\n" + task.Description
+		}
+
+		events = append(events, calendar_utils.Event{
+			UID:         fmt.Sprintf("task-%d@trello", task.ID),
+			Summary:     task.Title,
+			Description: description,
+			Start:       task.DueDate,
+			Stamp:       task.UpdatedAt,
+		})
+	}
+
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar_utils.Build("Assigned tasks", events)))
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import "testing"
+
+func TestEscapeCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"=SUM(A1:A2)", "'=SUM(A1:A2)"},
+		{"+1", "'+1"},
+		{"-1", "'-1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"'=1", "'=1"},
+		{"Write report", "Write report"},
+		{"a=b+c", "a=b+c"},
+		{" =1", " =1"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := escapeCSVCell(test.value); got != test.want {
+			t.Errorf("escapeCSVCell(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
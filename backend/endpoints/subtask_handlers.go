@@ -58,13 +58,21 @@ func createSubtask(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
 	}
 
-	tmp, ok := content["assignee_id"].(string)
-	assignee_id, _ := strconv.Atoi(tmp)
+	assignee_ids, ok := extractIDList(content, "assignee_ids")
 	if !ok {
-		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidUserId)
+	}
+
+	tmp, ok := content["assignee_id"].(string)
+	if ok && tmp != "" {
+		assignee_id, err := strconv.Atoi(tmp)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidUserId)
+		}
+		assignee_ids = append([]uint{uint(assignee_id)}, assignee_ids...)
 	}
 
-	subtask, err := database.CreateSubtaskInTask(requester_user_id, task_id, title, uint(assignee_id))
+	subtask, err := database.CreateSubtaskInTask(requester_user_id, task_id, title, assignee_ids)
 	if err != nil {
 		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
 		return generateProperResponse(err)
@@ -72,12 +80,13 @@ func createSubtask(c echo.Context) error {
 	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
 
 	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
-		TargetUserIDs: []uint{uint(assignee_id)},
+		TargetUserIDs: subtask.AssigneeIDs,
 		Body: &websocket_utils.WebsocketBody{
 			Group:   websocket_utils.SubtaskGroup,
 			Type:    websocket_utils.WatchType,
 			Message: fmt.Sprintf("Subtask '%s' has been assigned to you.", subtask.Title),
 		},
+		Event: database.AssignmentEvent,
 	}
 
 	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
@@ -96,6 +105,8 @@ func createSubtask(c echo.Context) error {
 		},
 	}
 
+	queueTaskWebhookEvent(task_id, database.SubtaskCreatedEvent, subtask)
+
 	return c.JSON(http.StatusCreated, subtask)
 }
 
@@ -179,7 +190,7 @@ func updateSubtask(c echo.Context) error {
 	}
 	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
 
-	if assignee_id != subtask.AssigneeID {
+	if !containsID(subtask.AssigneeIDs, assignee_id) {
 		websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
 			TargetUserIDs: []uint{uint(assignee_id)},
 			Body: &websocket_utils.WebsocketBody{
@@ -187,6 +198,7 @@ func updateSubtask(c echo.Context) error {
 				Type:    websocket_utils.WatchType,
 				Message: fmt.Sprintf("Subtask '%s' has been assigned to you.", subtask.Title),
 			},
+			Event: database.AssignmentEvent,
 		}
 	}
 
@@ -206,6 +218,8 @@ func updateSubtask(c echo.Context) error {
 		},
 	}
 
+	queueSubtaskSnapshotWebhookEvent(requester_user_id, task_id, subtask_id, database.SubtaskUpdatedEvent)
+
 	return c.NoContent(http.StatusCreated)
 }
 
@@ -255,7 +269,7 @@ func updateSubtaskAssigneeID(c echo.Context) error {
 	}
 	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
 
-	if assignee_id != subtask.AssigneeID {
+	if !containsID(subtask.AssigneeIDs, assignee_id) {
 		websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
 			TargetUserIDs: []uint{uint(assignee_id)},
 			Body: &websocket_utils.WebsocketBody{
@@ -263,6 +277,7 @@ func updateSubtaskAssigneeID(c echo.Context) error {
 				Type:    websocket_utils.WatchType,
 				Message: fmt.Sprintf("Subtask '%s' has been assigned to you.", subtask.Title),
 			},
+			Event: database.AssignmentEvent,
 		}
 	}
 
@@ -282,6 +297,8 @@ func updateSubtaskAssigneeID(c echo.Context) error {
 		},
 	}
 
+	queueSubtaskSnapshotWebhookEvent(requester_user_id, task_id, subtask_id, database.SubtaskUpdatedEvent)
+
 	return c.NoContent(http.StatusCreated)
 }
 
@@ -347,6 +364,8 @@ func updateSubtaskStatus(c echo.Context) error {
 		},
 	}
 
+	queueSubtaskSnapshotWebhookEvent(requester_user_id, task_id, subtask_id, database.SubtaskUpdatedEvent)
+
 	return c.NoContent(http.StatusCreated)
 }
 
@@ -410,6 +429,8 @@ func updateSubtaskTitle(c echo.Context) error {
 		},
 	}
 
+	queueSubtaskSnapshotWebhookEvent(requester_user_id, task_id, subtask_id, database.SubtaskUpdatedEvent)
+
 	return c.NoContent(http.StatusCreated)
 }
 
@@ -457,5 +478,7 @@ func deleteSubtask(c echo.Context) error {
 		},
 	}
 
+	queueTaskWebhookEvent(task_id, database.SubtaskDeletedEvent, subtask)
+
 	return c.NoContent(http.StatusOK)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/skye-tan/trello/backend/database"
 	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
 	"github.com/skye-tan/trello/backend/utils/custom_messages"
 	"github.com/skye-tan/trello/backend/websocket_utils"
 )
@@ -29,6 +30,40 @@ func getAssignedTasks(c echo.Context) error {
 	return c.JSON(http.StatusOK, tasks)
 }
 
+// GET "/self/tasks/overdue"
+func getOverdueTasks(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	tasks, err := database.GetOverdueTasks(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, tasks)
+}
+
+// extractTaskFilters reads the filters the task listings share: the labels
+// tasks must carry, which archive state to list and the search text.
+func extractTaskFilters(c echo.Context) ([]uint, string, string, error) {
+	label_ids, ok := extractQueryList(c, "labels")
+	if !ok {
+		return []uint{}, "", "", echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidLabelId)
+	}
+
+	archived := c.QueryParam("archived")
+	if archived == "" {
+		archived = database.ExcludeArchived
+	} else if archived != database.ExcludeArchived && archived != database.IncludeArchived && archived != database.OnlyArchived {
+		return []uint{}, "", "", echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidArchivedFilter)
+	}
+
+	search := strings.TrimSpace(c.QueryParam("search"))
+
+	return label_ids, archived, search, nil
+}
+
 // GET "/workspaces/:workspace_id/tasks"
 func getTasks(c echo.Context) error {
 	requester_user_id := getUserIDFromContext(c)
@@ -38,7 +73,12 @@ func getTasks(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
 	}
 
-	tasks, err := database.GetAllTasksInWorkspace(requester_user_id, workspace_id)
+	label_ids, archived, search, err := extractTaskFilters(c)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := database.GetAllTasksInWorkspace(requester_user_id, workspace_id, label_ids, archived, search)
 	if err != nil {
 		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
 		return generateProperResponse(err)
@@ -68,82 +108,20 @@ func createTask(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
 	}
 
-	title, ok := content["title"].(string)
-	if !ok {
-		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
-	}
-
-	description, ok := content["description"].(string)
-	if !ok {
-		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
-	}
-
-	tmp, ok := content["estimated_time"].(string)
-	estimated_time, err := strconv.Atoi(tmp)
-	if !ok || err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
-	}
-
-	tmp, ok = content["actual_time"].(string)
-	actual_time, err := strconv.Atoi(tmp)
-
-	if !ok || err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
-	}
-
-	tmp, ok = content["due_date"].(string)
-	due_date, err := time.Parse("2006-01-02", tmp)
-	if !ok || err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
-	}
-
-	tmp, ok = content["priority"].(string)
-	priority, err := strconv.Atoi(tmp)
-	if !ok || err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
-	}
-
-	tmp, ok = content["assignee_id"].(string)
-	assignee_id, err := strconv.Atoi(tmp)
-	if !ok || err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
-	}
-
-	image_url, ok := content["image_url"].(string)
-	if !ok {
-		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
-	}
-
-	task, err := database.CreateTaskInWorkspace(requester_user_id, workspace_id, title, description, estimated_time, actual_time, due_date, priority, uint(assignee_id), image_url)
+	task_content, err := extractTaskContent(content)
 	if err != nil {
-		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
-		return generateProperResponse(err)
-	}
-	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
-
-	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
-		TargetUserIDs: []uint{uint(assignee_id)},
-		Body: &websocket_utils.WebsocketBody{
-			Group:   websocket_utils.TaskGroup,
-			Type:    websocket_utils.WatchType,
-			Message: fmt.Sprintf("Task '%s' has been assigned to you.", task.Title),
-		},
+		return err
 	}
 
-	associated_users, err := database.GetAssociatedUsersWithTask(task.ID)
+	task, err := database.CreateTaskInWorkspace(requester_user_id, workspace_id, task_content.Title, task_content.Description, task_content.EstimatedTime, task_content.ActualTime, task_content.DueDate, task_content.Priority, task_content.AssigneeIDs, task_content.ImageURL)
 	if err != nil {
 		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
 		return generateProperResponse(err)
 	}
 	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
 
-	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
-		TargetUserIDs: associated_users,
-		Body: &websocket_utils.WebsocketBody{
-			Group:   websocket_utils.TaskGroup,
-			Type:    websocket_utils.UpdateType,
-			Message: fmt.Sprintf("Task '%s' has been created.", task.Title),
-		},
+	if err := announceTaskCreation(workspace_id, task); err != nil {
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, task)
@@ -205,6 +183,8 @@ func updateTaskStatus(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidStatus)
 	}
 
+	force, _ := content["force"].(bool)
+
 	task, err := database.GetDetailsOfTask(requester_user_id, workspace_id, task_id)
 	if err != nil {
 		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
@@ -212,8 +192,20 @@ func updateTaskStatus(c echo.Context) error {
 	}
 	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
 
-	err = database.UpdateStatusOfTask(requester_user_id, workspace_id, task_id, status)
-	if err != nil {
+	err = database.UpdateStatusOfTask(requester_user_id, workspace_id, task_id, status, force)
+	if err == custom_errors.ErrTaskBlocked {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+
+		blockers, err := database.GetUnfinishedBlockers(task_id)
+		if err != nil {
+			return generateProperResponse(err)
+		}
+
+		return c.JSON(http.StatusConflict, map[string]interface{}{
+			"message":  custom_errors.ErrTaskBlocked.Error(),
+			"blockers": blockers,
+		})
+	} else if err != nil {
 		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
 		return generateProperResponse(err)
 	}
@@ -233,6 +225,7 @@ func updateTaskStatus(c echo.Context) error {
 			Type:    websocket_utils.WatchType,
 			Message: fmt.Sprintf("Task '%s' has been updated.", task.Title),
 		},
+		Event: database.WatchEvent,
 	}
 
 	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
@@ -251,6 +244,8 @@ func updateTaskStatus(c echo.Context) error {
 		},
 	}
 
+	queueTaskSnapshotWebhookEvent(requester_user_id, workspace_id, task_id, database.TaskUpdatedEvent)
+
 	return c.NoContent(http.StatusCreated)
 }
 
@@ -339,7 +334,7 @@ func updateTask(c echo.Context) error {
 	}
 	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
 
-	if assignee_id != task.AssigneeID {
+	if !containsID(task.AssigneeIDs, assignee_id) {
 		websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
 			TargetUserIDs: []uint{uint(assignee_id)},
 			Body: &websocket_utils.WebsocketBody{
@@ -347,6 +342,7 @@ func updateTask(c echo.Context) error {
 				Type:    websocket_utils.WatchType,
 				Message: fmt.Sprintf("Task '%s' has been assigned to you.", task.Title),
 			},
+			Event: database.AssignmentEvent,
 		}
 	}
 
@@ -364,6 +360,7 @@ func updateTask(c echo.Context) error {
 			Type:    websocket_utils.WatchType,
 			Message: fmt.Sprintf("Task '%s' has been updated.", task.Title),
 		},
+		Event: database.WatchEvent,
 	}
 
 	associated_users, err := database.GetAssociatedUsersWithTask(task_id)
@@ -382,6 +379,8 @@ func updateTask(c echo.Context) error {
 		},
 	}
 
+	queueTaskSnapshotWebhookEvent(requester_user_id, workspace_id, task_id, database.TaskUpdatedEvent)
+
 	return c.NoContent(http.StatusCreated)
 }
 
@@ -434,6 +433,7 @@ func deleteTask(c echo.Context) error {
 			Type:    websocket_utils.WatchType,
 			Message: fmt.Sprintf("Task '%s' has been deleted.", task.Title),
 		},
+		Event: database.WatchEvent,
 	}
 
 	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
@@ -445,5 +445,117 @@ func deleteTask(c echo.Context) error {
 		},
 	}
 
+	queueWebhookEvent(workspace_id, database.TaskDeletedEvent, task)
+
 	return c.NoContent(http.StatusCreated)
 }
+
+type taskContent struct {
+	Title         string
+	Description   string
+	EstimatedTime int
+	ActualTime    int
+	DueDate       time.Time
+	Priority      int
+	AssigneeIDs   []uint
+	ImageURL      string
+}
+
+func extractTaskContent(content map[string]interface{}) (taskContent, error) {
+	title, ok := content["title"].(string)
+	if !ok {
+		return taskContent{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	description, ok := content["description"].(string)
+	if !ok {
+		return taskContent{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	tmp, ok := content["estimated_time"].(string)
+	estimated_time, err := strconv.Atoi(tmp)
+	if !ok || err != nil {
+		return taskContent{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	tmp, ok = content["actual_time"].(string)
+	actual_time, err := strconv.Atoi(tmp)
+
+	if !ok || err != nil {
+		return taskContent{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	tmp, ok = content["due_date"].(string)
+	due_date, err := time.Parse("2006-01-02", tmp)
+	if !ok || err != nil {
+		return taskContent{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	tmp, ok = content["priority"].(string)
+	priority, err := strconv.Atoi(tmp)
+	if !ok || err != nil {
+		return taskContent{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	assignee_ids, ok := extractIDList(content, "assignee_ids")
+	if !ok {
+		return taskContent{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidUserId)
+	}
+
+	tmp, ok = content["assignee_id"].(string)
+	if ok && tmp != "" {
+		assignee_id, err := strconv.Atoi(tmp)
+		if err != nil {
+			return taskContent{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidUserId)
+		}
+		assignee_ids = append([]uint{uint(assignee_id)}, assignee_ids...)
+	}
+
+	image_url, ok := content["image_url"].(string)
+	if !ok {
+		return taskContent{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	return taskContent{
+		Title:         title,
+		Description:   description,
+		EstimatedTime: estimated_time,
+		ActualTime:    actual_time,
+		DueDate:       due_date,
+		Priority:      priority,
+		AssigneeIDs:   assignee_ids,
+		ImageURL:      image_url,
+	}, nil
+}
+
+func announceTaskCreation(workspace_id uint, task database.Task) error {
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: task.AssigneeIDs,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.WatchType,
+			Message: fmt.Sprintf("Task '%s' has been assigned to you.", task.Title),
+		},
+		Event: database.AssignmentEvent,
+	}
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task.ID)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Task '%s' has been created.", task.Title),
+		},
+	}
+
+	queueWebhookEvent(workspace_id, database.TaskCreatedEvent, task)
+
+	return nil
+}
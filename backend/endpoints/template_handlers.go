@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+// GET "/self/templates"
+func getTemplates(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	templates, err := database.GetTemplatesOfUser(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, templates)
+}
+
+// GET "/self/templates/:template_id"
+func getTemplate(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	template_id, ok := extractQueryParameter(c, "template_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTemplateId)
+	}
+
+	template, err := database.GetDetailsOfTemplate(requester_user_id, template_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, template)
+}
+
+// DELETE "/self/templates/:template_id"
+func deleteTemplate(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	template_id, ok := extractQueryParameter(c, "template_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTemplateId)
+	}
+
+	err := database.DeleteTemplate(requester_user_id, template_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusOK)
+}
+
+// POST "/workspaces/:workspace_id/tasks/:task_id/template"
+func createTaskTemplate(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	task_id, ok := extractQueryParameter(c, "task_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTaskId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	name, ok := content["name"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	template, err := database.CreateTaskTemplate(requester_user_id, workspace_id, task_id, name)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, template)
+}
+
+// POST "/workspaces/:workspace_id/template"
+func createBoardTemplate(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	name, ok := content["name"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	template, err := database.CreateBoardTemplate(requester_user_id, workspace_id, name)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, template)
+}
+
+// POST "/workspaces/:workspace_id/templates/:template_id"
+func instantiateTemplate(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	template_id, ok := extractQueryParameter(c, "template_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTemplateId)
+	}
+
+	tasks, err := database.InstantiateTemplate(requester_user_id, workspace_id, template_id, time.Now())
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	for _, task := range tasks {
+		associated_users, err := database.GetAssociatedUsersWithTask(task.ID)
+		if err != nil {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+			return generateProperResponse(err)
+		}
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+		websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+			TargetUserIDs: associated_users,
+			Body: &websocket_utils.WebsocketBody{
+				Group:   websocket_utils.TaskGroup,
+				Type:    websocket_utils.UpdateType,
+				Message: fmt.Sprintf("Task '%s' has been created.", task.Title),
+			},
+		}
+	}
+
+	return c.JSON(http.StatusCreated, tasks)
+}
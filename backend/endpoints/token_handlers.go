@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+)
+
+func extractTokenContent(c echo.Context) (string, []string, time.Time, error) {
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return "", []string{}, time.Time{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return "", []string{}, time.Time{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	name, ok := content["name"].(string)
+	if !ok {
+		return "", []string{}, time.Time{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	scopes, ok := extractStringList(content, "scopes")
+	if !ok || len(scopes) == 0 {
+		return "", []string{}, time.Time{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTokenScope)
+	}
+
+	expires_at, ok := extractExpiry(content, "expires_at")
+	if !ok {
+		return "", []string{}, time.Time{}, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidDate)
+	}
+
+	return name, scopes, expires_at, nil
+}
+
+// GET "/users/self/tokens"
+func getPersonalAccessTokens(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	personal_access_tokens, err := database.GetPersonalAccessTokens(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, personal_access_tokens)
+}
+
+// POST "/users/self/tokens"
+func createPersonalAccessToken(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	name, scopes, expires_at, err := extractTokenContent(c)
+	if err != nil {
+		return err
+	}
+
+	personal_access_token, err := database.CreatePersonalAccessToken(requester_user_id, name, scopes, expires_at)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, personal_access_token)
+}
+
+// DELETE "/users/self/tokens/:token_id"
+func deletePersonalAccessToken(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	token_id, ok := extractQueryParameter(c, "token_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTokenId)
+	}
+
+	err := database.DeletePersonalAccessToken(requester_user_id, token_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GET "/workspaces/:workspace_id/service-accounts"
+func getServiceAccounts(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	service_accounts, err := database.GetServiceAccountsInWorkspace(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, service_accounts)
+}
+
+// POST "/workspaces/:workspace_id/service-accounts"
+func createServiceAccount(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	name, ok := content["name"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	role, ok := content["role"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+	if role == "" || role == database.Owner {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidRole)
+	}
+
+	service_account, err := database.CreateServiceAccountInWorkspace(requester_user_id, workspace_id, name, role)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, service_account)
+}
+
+// DELETE "/workspaces/:workspace_id/service-accounts/:service_account_id"
+func deleteServiceAccount(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	service_account_id, ok := extractQueryParameter(c, "service_account_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidServiceAccount)
+	}
+
+	err := database.DeleteServiceAccount(requester_user_id, workspace_id, service_account_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GET "/workspaces/:workspace_id/service-accounts/:service_account_id/tokens"
+func getServiceAccountTokens(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	service_account_id, ok := extractQueryParameter(c, "service_account_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidServiceAccount)
+	}
+
+	personal_access_tokens, err := database.GetServiceAccountTokens(requester_user_id, workspace_id, service_account_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, personal_access_tokens)
+}
+
+// POST "/workspaces/:workspace_id/service-accounts/:service_account_id/tokens"
+func createServiceAccountToken(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	service_account_id, ok := extractQueryParameter(c, "service_account_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidServiceAccount)
+	}
+
+	name, scopes, expires_at, err := extractTokenContent(c)
+	if err != nil {
+		return err
+	}
+
+	personal_access_token, err := database.CreateServiceAccountToken(requester_user_id, workspace_id, service_account_id, name, scopes, expires_at)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, personal_access_token)
+}
+
+// DELETE "/workspaces/:workspace_id/service-accounts/:service_account_id/tokens/:token_id"
+func deleteServiceAccountToken(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	service_account_id, ok := extractQueryParameter(c, "service_account_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidServiceAccount)
+	}
+
+	token_id, ok := extractQueryParameter(c, "token_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTokenId)
+	}
+
+	err := database.DeleteServiceAccountToken(requester_user_id, workspace_id, service_account_id, token_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+var trashItemGroups = map[string]string{
+	database.TaskItem:    websocket_utils.TaskGroup,
+	database.SubtaskItem: websocket_utils.SubtaskGroup,
+	database.CommentItem: websocket_utils.CommentGroup,
+}
+
+// GET "/workspaces/:workspace_id/trash"
+func getTrash(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	trash_items, err := database.GetTrashInWorkspace(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, trash_items)
+}
+
+// POST "/workspaces/:workspace_id/trash/:trash_id/restore"
+func restoreFromTrash(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	trash_id, ok := extractQueryParameter(c, "trash_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTrashId)
+	}
+
+	trash_item, err := database.RestoreFromTrash(requester_user_id, workspace_id, trash_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	members, err := database.GetWorkspaceMembers(workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: members,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   trashItemGroups[trash_item.ItemType],
+			Type:    websocket_utils.UpdateType,
+			Message: "Item has been restored from the trash.",
+		},
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// GET "/self/trash"
+func getTrashedWorkspaces(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	trash_items, err := database.GetTrashedWorkspaces(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, trash_items)
+}
+
+// POST "/workspaces/:workspace_id/restore"
+func restoreWorkspace(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	err := database.RestoreWorkspace(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	members, err := database.GetWorkspaceMembers(workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: members,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.WorkspaceGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: "Workspace has been restored from the trash.",
+		},
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/middlewares/ratelimit"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+	token_utils "github.com/skye-tan/trello/backend/utils/token"
+)
+
+func extractTwoFactorContent(c echo.Context) (map[string]interface{}, error) {
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	return content, nil
+}
+
+func generateTwoFactorResponse(err error) *echo.HTTPError {
+	if err == custom_errors.ErrAccessDenied {
+		return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidTwoFactor)
+	}
+	return generateProperResponse(err)
+}
+
+// POST "/login/2fa"
+func loginTwoFactor(c echo.Context) error {
+	content, err := extractTwoFactorContent(c)
+	if err != nil {
+		return err
+	}
+
+	token, ok := content["token"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	code, _ := content["code"].(string)
+	recovery_code, _ := content["recovery_code"].(string)
+	if code == "" && recovery_code == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	claims, err := token_utils.ParseToken(token)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidToken)
+	} else if claims.Type != token_utils.TwoFactor {
+		return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidTokenType)
+	}
+
+	user, err := database.GetUserByID(claims.UserID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidToken)
+	}
+
+	if err := ratelimit.CheckLoginAttempt(c, user.Username); err != nil {
+		return err
+	}
+
+	err = database.VerifyTwoFactor(user.ID, code, recovery_code)
+	if err == custom_errors.ErrAccessDenied {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+		ratelimit.RegisterLoginFailure(c, user.Username)
+		return generateTwoFactorResponse(err)
+	} else if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateTwoFactorResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	ratelimit.RegisterLoginSuccess(c, user.Username)
+
+	refresh_token, access_token, err := token_utils.GenerateTokens(user.ID)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"AccessToken":  access_token,
+		"RefreshToken": refresh_token,
+	})
+}
+
+// GET "/users/self/2fa"
+func getTwoFactorStatus(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	status, err := database.GetTwoFactorStatus(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, status)
+}
+
+// POST "/users/self/2fa/enroll"
+func enrollTwoFactor(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	enrollment, err := database.EnrollTwoFactor(requester_user_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, enrollment)
+}
+
+// POST "/users/self/2fa/verify"
+func enableTwoFactor(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	content, err := extractTwoFactorContent(c)
+	if err != nil {
+		return err
+	}
+
+	code, ok := content["code"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	recovery_codes, err := database.EnableTwoFactor(requester_user_id, code)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateTwoFactorResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, map[string][]string{
+		"recovery_codes": recovery_codes,
+	})
+}
+
+// DELETE "/users/self/2fa"
+func disableTwoFactor(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	content, err := extractTwoFactorContent(c)
+	if err != nil {
+		return err
+	}
+
+	code, _ := content["code"].(string)
+	recovery_code, _ := content["recovery_code"].(string)
+	if code == "" && recovery_code == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	err = database.DisableTwoFactor(requester_user_id, code, recovery_code)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateTwoFactorResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusOK)
+}
+
+// POST "/users/self/2fa/recovery-codes"
+func regenerateRecoveryCodes(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	content, err := extractTwoFactorContent(c)
+	if err != nil {
+		return err
+	}
+
+	code, ok := content["code"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	recovery_codes, err := database.RegenerateRecoveryCodes(requester_user_id, code)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateTwoFactorResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, map[string][]string{
+		"recovery_codes": recovery_codes,
+	})
+}
+
+// GET "/workspaces/:workspace_id/security"
+func getWorkspaceSecurity(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	workspace_security, err := database.GetWorkspaceSecurity(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, workspace_security)
+}
+
+// PUT "/workspaces/:workspace_id/security"
+func updateWorkspaceSecurity(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	content, err := extractTwoFactorContent(c)
+	if err != nil {
+		return err
+	}
+
+	require_two_factor, ok := content["require_two_factor"].(bool)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	err = database.UpdateWorkspaceSecurity(requester_user_id, workspace_id, require_two_factor)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
@@ -62,24 +62,23 @@ func addUserWorkspaceRole(c echo.Context) error {
 	if !ok {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
 	}
-	if role != database.Admin && role != database.Owner && role != database.StandardUser {
+	if role == "" || role == database.Owner {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidRole)
 	}
 
-	user_workespace_role, err := database.AddUserWorkspaceRole(requester_user_id, uint(user_id), workspace_id, role)
+	invitation, err := database.AddUserWorkspaceRole(requester_user_id, uint(user_id), workspace_id, role)
 	if err != nil {
 		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
 		return generateProperResponse(err)
 	}
 	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
 
-	workspace, err := database.GetWorkspace(requester_user_id, workspace_id)
-	if err != nil {
-		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
-		return generateProperResponse(err)
-	}
-	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+	notifyInvitee(invitation)
+
+	return c.JSON(http.StatusCreated, invitation)
+}
 
+func announceMemberAddition(workspace_id uint, user_workespace_role database.UserWorkspaceRole) error {
 	members, err := database.GetWorkspaceMembers(workspace_id)
 	if err != nil {
 		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
@@ -96,16 +95,9 @@ func addUserWorkspaceRole(c echo.Context) error {
 		},
 	}
 
-	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
-		TargetUserIDs: []uint{uint(user_id)},
-		Body: &websocket_utils.WebsocketBody{
-			Group:   websocket_utils.WorkspaceGroup,
-			Type:    websocket_utils.WatchType,
-			Message: fmt.Sprintf("You have been added to workspace '%s'.", workspace.Name),
-		},
-	}
+	queueWebhookEvent(workspace_id, database.MemberAddedEvent, user_workespace_role)
 
-	return c.JSON(http.StatusCreated, user_workespace_role)
+	return nil
 }
 
 // PUT "/workspaces/:workspace_id/users/:user_id"
@@ -137,7 +129,7 @@ func updateUserWorkspaceRole(c echo.Context) error {
 	if !ok {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
 	}
-	if role != database.Admin && role != database.Owner && role != database.StandardUser {
+	if role == "" || role == database.Owner {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidRole)
 	}
 
@@ -178,8 +170,15 @@ func updateUserWorkspaceRole(c echo.Context) error {
 			Type:    websocket_utils.WatchType,
 			Message: fmt.Sprintf("Your role in workspace '%s' has been updated.", workspace.Name),
 		},
+		Event: database.RoleEvent,
 	}
 
+	queueWebhookEvent(workspace_id, database.MemberUpdatedEvent, map[string]interface{}{
+		"user_id":      user_id,
+		"workspace_id": workspace_id,
+		"role":         role,
+	})
+
 	return c.NoContent(http.StatusCreated)
 }
 
@@ -234,8 +233,14 @@ func deleteUserWorkspaceRole(c echo.Context) error {
 			Type:    websocket_utils.WatchType,
 			Message: fmt.Sprintf("You have been removed from workspace '%s'.", workspace.Name),
 		},
+		Event: database.RoleEvent,
 	}
 
+	queueWebhookEvent(workspace_id, database.MemberRemovedEvent, map[string]interface{}{
+		"user_id":      user_id,
+		"workspace_id": workspace_id,
+	})
+
 	return c.NoContent(http.StatusOK)
 }
 
@@ -271,5 +276,10 @@ func leaveUserWorkspaceRole(c echo.Context) error {
 		},
 	}
 
+	queueWebhookEvent(workspace_id, database.MemberRemovedEvent, map[string]interface{}{
+		"user_id":      requester_user_id,
+		"workspace_id": workspace_id,
+	})
+
 	return c.NoContent(http.StatusOK)
 }
@@ -3,6 +3,8 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/skye-tan/trello/backend/utils/custom_errors"
@@ -24,12 +26,150 @@ func extractQueryParameter(c echo.Context, parameter string) (uint, bool) {
 	return uint(value), true
 }
 
+func extractQueryList(c echo.Context, parameter string) ([]uint, bool) {
+	values := []uint{}
+	raw := c.QueryParam(parameter)
+	if raw == "" {
+		return values, true
+	}
+	for _, item := range strings.Split(raw, ",") {
+		value, err := strconv.ParseUint(strings.TrimSpace(item), 10, 32)
+		if err != nil {
+			return []uint{}, false
+		}
+		values = append(values, uint(value))
+	}
+	return values, true
+}
+
+func extractQueryNumber(c echo.Context, parameter string, default_value uint) (uint, bool) {
+	raw := c.QueryParam(parameter)
+	if raw == "" {
+		return default_value, true
+	}
+	value, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil || value == 0 {
+		return 0, false
+	}
+	return uint(value), true
+}
+
+// extractExpiry reads an optional future expiry given either as an RFC 3339
+// timestamp or a plain date. The zero time is returned when it is absent.
+func extractExpiry(content map[string]interface{}, key string) (time.Time, bool) {
+	tmp, ok := content[key].(string)
+	if !ok || tmp == "" {
+		return time.Time{}, true
+	}
+
+	expires_at, err := time.Parse(time.RFC3339, tmp)
+	if err != nil {
+		expires_at, err = time.Parse("2006-01-02", tmp)
+	}
+	if err != nil || expires_at.Before(time.Now()) {
+		return time.Time{}, false
+	}
+
+	return expires_at, true
+}
+
+func extractIDList(content map[string]interface{}, key string) ([]uint, bool) {
+	values := []uint{}
+	raw, ok := content[key]
+	if !ok {
+		return values, true
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return []uint{}, false
+	}
+	for _, item := range items {
+		value, ok := parseID(item)
+		if !ok {
+			return []uint{}, false
+		}
+		values = append(values, value)
+	}
+	return values, true
+}
+
+// extractBodyID reads an id given either as a JSON number or as a string.
+func extractBodyID(content map[string]interface{}, key string) (uint, bool) {
+	return parseID(content[key])
+}
+
+// parseID accepts positive whole numbers that fit an id, given either as a
+// JSON number or as a string.
+func parseID(raw interface{}) (uint, bool) {
+	switch tmp := raw.(type) {
+	case float64:
+		if tmp <= 0 || tmp != float64(uint32(tmp)) {
+			return 0, false
+		}
+		return uint(tmp), true
+	case string:
+		value, err := strconv.ParseUint(tmp, 10, 32)
+		if err != nil || value == 0 {
+			return 0, false
+		}
+		return uint(value), true
+	}
+	return 0, false
+}
+
+func extractStringList(content map[string]interface{}, key string) ([]string, bool) {
+	items, ok := content[key].([]interface{})
+	if !ok {
+		return []string{}, false
+	}
+	values := []string{}
+	for _, item := range items {
+		value, ok := item.(string)
+		if !ok {
+			return []string{}, false
+		}
+		values = append(values, value)
+	}
+	return values, true
+}
+
+func extractStringMap(content map[string]interface{}, key string) (map[string]string, bool) {
+	items, ok := content[key].(map[string]interface{})
+	if !ok {
+		return map[string]string{}, false
+	}
+	values := make(map[string]string)
+	for item_key, item := range items {
+		value, ok := item.(string)
+		if !ok {
+			return map[string]string{}, false
+		}
+		values[item_key] = value
+	}
+	return values, true
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, item := range ids {
+		if item == id {
+			return true
+		}
+	}
+	return false
+}
+
 func generateProperResponse(err error) *echo.HTTPError {
 	message := err.Error()
 	if err == custom_errors.ErrTokenFailure {
 		return echo.NewHTTPError(http.StatusInternalServerError, message)
 	} else if err == custom_errors.ErrAccessDenied {
 		return echo.NewHTTPError(http.StatusUnauthorized, message)
+	} else if err == custom_errors.ErrTwoFactorRequired || err == custom_errors.ErrEmailNotVerified {
+		return echo.NewHTTPError(http.StatusForbidden, message)
+	} else if err == custom_errors.ErrDependencyCycle || err == custom_errors.ErrTaskBlocked || err == custom_errors.ErrDuplicateExternalID ||
+		err == custom_errors.ErrDuplicateInvitation || err == custom_errors.ErrAlreadyMember || err == custom_errors.ErrRoleInUse ||
+		err == custom_errors.ErrLastOwner {
+		return echo.NewHTTPError(http.StatusConflict, message)
 	} else {
 		return echo.NewHTTPError(http.StatusBadRequest, message)
 	}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+)
+
+func queueWebhookEvent(workspace_id uint, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Println("Error:", err)
+		return
+	}
+
+	if err := database.QueueWebhookDeliveries(workspace_id, event, string(payload)); err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+	} else {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+	}
+}
+
+func queueTaskWebhookEvent(task_id uint, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Println("Error:", err)
+		return
+	}
+
+	if err := database.QueueTaskWebhookDeliveries(task_id, event, string(payload)); err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+	} else {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+	}
+}
+
+func queueTaskSnapshotWebhookEvent(requester_user_id uint, workspace_id uint, task_id uint, event string) {
+	task, err := database.GetDetailsOfTask(requester_user_id, workspace_id, task_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	queueWebhookEvent(workspace_id, event, task)
+}
+
+func queueSubtaskSnapshotWebhookEvent(requester_user_id uint, task_id uint, subtask_id uint, event string) {
+	subtask, err := database.GetDetailsOfSubtask(requester_user_id, task_id, subtask_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	queueTaskWebhookEvent(task_id, event, subtask)
+}
+
+func validateWebhookURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// GET "/workspaces/:workspace_id/webhooks"
+func getWebhooks(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	webhooks, err := database.GetWebhooksInWorkspace(requester_user_id, workspace_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, webhooks)
+}
+
+// POST "/workspaces/:workspace_id/webhooks"
+func createWebhook(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	webhook_url, ok := content["url"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	} else if !validateWebhookURL(webhook_url) {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidURL)
+	}
+
+	events, ok := extractStringList(content, "events")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWebhookEvent)
+	}
+
+	webhook, err := database.CreateWebhookInWorkspace(requester_user_id, workspace_id, webhook_url, events)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, webhook)
+}
+
+// PUT "/workspaces/:workspace_id/webhooks/:webhook_id"
+func updateWebhook(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	webhook_id, ok := extractQueryParameter(c, "webhook_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWebhookId)
+	}
+
+	content_type := c.Request().Header.Get(echo.HeaderContentType)
+	if content_type != "application/json" {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidContentType)
+	}
+
+	content := make(map[string]interface{})
+	err := json.NewDecoder(c.Request().Body).Decode(&content)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidBodyFormat)
+	}
+
+	webhook_url, ok := content["url"].(string)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	} else if !validateWebhookURL(webhook_url) {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidURL)
+	}
+
+	events, ok := extractStringList(content, "events")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWebhookEvent)
+	}
+
+	is_active, ok := content["is_active"].(bool)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
+	}
+
+	err = database.UpdateWebhook(requester_user_id, workspace_id, webhook_id, webhook_url, events, is_active)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// DELETE "/workspaces/:workspace_id/webhooks/:webhook_id"
+func deleteWebhook(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	webhook_id, ok := extractQueryParameter(c, "webhook_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWebhookId)
+	}
+
+	err := database.DeleteWebhook(requester_user_id, workspace_id, webhook_id)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.NoContent(http.StatusOK)
+}
+
+// GET "/workspaces/:workspace_id/webhooks/:webhook_id/deliveries"
+func getWebhookDeliveries(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	webhook_id, ok := extractQueryParameter(c, "webhook_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWebhookId)
+	}
+
+	page, ok := extractQueryNumber(c, "page", 1)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidPagination)
+	}
+
+	per_page, ok := extractQueryNumber(c, "per_page", 20)
+	if !ok || per_page > 100 {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidPagination)
+	}
+
+	webhook_deliveries, err := database.GetWebhookDeliveries(requester_user_id, workspace_id, webhook_id, page, per_page)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusOK, webhook_deliveries)
+}
+
+// POST "/workspaces/:workspace_id/webhooks/:webhook_id/test"
+func testWebhook(c echo.Context) error {
+	requester_user_id := getUserIDFromContext(c)
+
+	workspace_id, ok := extractQueryParameter(c, "workspace_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWorkspaceId)
+	}
+
+	webhook_id, ok := extractQueryParameter(c, "webhook_id")
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidWebhookId)
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"message":   "This is a test event.",
+		"sent_by":   requester_user_id,
+		"timestamp": time.Now(),
+	})
+	if err != nil {
+		log.Println("Error:", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	webhook_delivery, err := database.QueueTestWebhookDelivery(requester_user_id, workspace_id, webhook_id, string(payload))
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return generateProperResponse(err)
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	return c.JSON(http.StatusCreated, webhook_delivery)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 	"github.com/skye-tan/trello/backend/database"
@@ -49,7 +50,18 @@ func createWorkspace(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, custom_messages.MissingData)
 	}
 
-	worksapce, err := database.CreateWorkSpace(requester_user_id, name, description)
+	var template_id uint
+	if tmp, ok := content["template_id"].(float64); ok {
+		template_id = uint(tmp)
+	} else if tmp, ok := content["template_id"].(string); ok {
+		value, err := strconv.ParseUint(tmp, 10, 32)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, custom_messages.InvalidTemplateId)
+		}
+		template_id = uint(value)
+	}
+
+	worksapce, err := database.CreateWorkSpace(requester_user_id, name, description, template_id)
 	if err != nil {
 		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
 		return generateProperResponse(err)
@@ -7,7 +7,10 @@ import (
 	"github.com/skye-tan/trello/backend/database"
 	endpoints "github.com/skye-tan/trello/backend/endpoints"
 	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/middlewares/ratelimit"
+	oidc_utils "github.com/skye-tan/trello/backend/utils/oidc"
 	"github.com/skye-tan/trello/backend/websocket_utils"
+	"github.com/skye-tan/trello/backend/workers"
 )
 
 func main() {
@@ -31,7 +34,19 @@ func main() {
 
 	monitoring.InitalizeStatistics()
 
+	oidc_utils.LoadOIDCConfig()
+
+	ratelimit.LoadRateLimitConfig()
+
 	go websocket_utils.Hub.Run()
 
+	go workers.RunRecurrenceScheduler()
+	go workers.RunReminderScheduler()
+	go workers.RunEmailDispatcher()
+	go workers.RunWebhookDispatcher()
+	go workers.RunTrashPurger()
+	go workers.RunAutoArchiver()
+	go workers.RunImportProcessor()
+
 	endpoints.Start(listen_address)
 }
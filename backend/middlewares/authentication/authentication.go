@@ -7,10 +7,59 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/ratelimit"
 	"github.com/skye-tan/trello/backend/utils/custom_messages"
 	token_utils "github.com/skye-tan/trello/backend/utils/token"
 )
 
+// taskWriteRoutes lists the routes, besides reads, that the tasks:write scope
+// grants. Routes are matched by their registered path, so new routes stay
+// out of reach of the scope until they are added here.
+var taskWriteRoutes = map[string]bool{
+	"POST /api/workspaces/:workspace_id/tasks":                                        true,
+	"POST /api/workspaces/:workspace_id/tasks/bulk":                                   true,
+	"POST /api/workspaces/:workspace_id/tasks/archive-completed":                      true,
+	"PUT /api/workspaces/:workspace_id/tasks/:task_id":                                true,
+	"PUT /api/workspaces/:workspace_id/tasks/:task_id/status":                         true,
+	"DELETE /api/workspaces/:workspace_id/tasks/:task_id":                             true,
+	"POST /api/workspaces/:workspace_id/tasks/:task_id/archive":                       true,
+	"POST /api/workspaces/:workspace_id/tasks/:task_id/unarchive":                     true,
+	"POST /api/workspaces/:workspace_id/tasks/:task_id/move":                          true,
+	"POST /api/workspaces/:workspace_id/tasks/:task_id/copy":                          true,
+	"POST /api/workspaces/:workspace_id/tasks/:task_id/assignees":                     true,
+	"DELETE /api/workspaces/:workspace_id/tasks/:task_id/assignees/:user_id":          true,
+	"POST /api/workspaces/:workspace_id/tasks/:task_id/comments":                      true,
+	"DELETE /api/workspaces/:workspace_id/tasks/:task_id/comments/:comment_id":        true,
+	"POST /api/workspaces/:workspace_id/tasks/:task_id/watch":                         true,
+	"DELETE /api/workspaces/:workspace_id/tasks/:task_id/watch":                       true,
+	"POST /api/workspaces/:workspace_id/tasks/:task_id/labels/:label_id":              true,
+	"DELETE /api/workspaces/:workspace_id/tasks/:task_id/labels/:label_id":            true,
+	"POST /api/workspaces/:workspace_id/tasks/:task_id/dependencies":                  true,
+	"DELETE /api/workspaces/:workspace_id/tasks/:task_id/dependencies/:dependency_id": true,
+	"POST /api/tasks/:task_id/subtasks":                                               true,
+	"PUT /api/tasks/:task_id/subtasks/:subtask_id":                                    true,
+	"PUT /api/tasks/:task_id/subtasks/:subtask_id/status":                             true,
+	"PUT /api/tasks/:task_id/subtasks/:subtask_id/title":                              true,
+	"PUT /api/tasks/:task_id/subtasks/:subtask_id/assigneeid":                         true,
+	"DELETE /api/tasks/:task_id/subtasks/:subtask_id":                                 true,
+	"POST /api/tasks/:task_id/subtasks/:subtask_id/assignees":                         true,
+	"DELETE /api/tasks/:task_id/subtasks/:subtask_id/assignees/:user_id":              true,
+}
+
+func scopeAllows(scopes []string, method string, path string) bool {
+	is_read := method == http.MethodGet || method == http.MethodHead
+	for _, scope := range scopes {
+		if scope == database.AdminScope {
+			return true
+		} else if scope == database.TasksWriteScope && (is_read || taskWriteRoutes[method+" "+path]) {
+			return true
+		} else if scope == database.ReadScope && is_read {
+			return true
+		}
+	}
+	return false
+}
+
 func AccessJWTMiddleware(handler echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		auth_header := c.Request().Header.Get("Authorization")
@@ -24,6 +73,22 @@ func AccessJWTMiddleware(handler echo.HandlerFunc) echo.HandlerFunc {
 		}
 
 		token := split_token[1]
+		if token_utils.IsPersonalToken(token) {
+			personal_access_token, err := database.AuthenticatePersonalAccessToken(token)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidToken)
+			} else if !scopeAllows(personal_access_token.Scopes, c.Request().Method, c.Path()) {
+				return echo.NewHTTPError(http.StatusForbidden, custom_messages.InsufficientScope)
+			}
+
+			if err := ratelimit.LimitUser(c, personal_access_token.UserID); err != nil {
+				return err
+			}
+
+			c.Set("user_id", personal_access_token.UserID)
+			return handler(c)
+		}
+
 		claims, err := token_utils.ParseToken(token)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidToken)
@@ -31,11 +96,15 @@ func AccessJWTMiddleware(handler echo.HandlerFunc) echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidTokenType)
 		}
 
-		_, err = database.GetUserByID(claims.UserID)
+		err = database.ValidateSession(claims.UserID, claims.IssuedTime())
 		if err != nil {
 			return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidToken)
 		}
 
+		if err := ratelimit.LimitUser(c, claims.UserID); err != nil {
+			return err
+		}
+
 		c.Set("user_id", claims.UserID)
 		return handler(c)
 	}
@@ -55,7 +124,7 @@ func RefreshJWTMiddleware(handler echo.HandlerFunc) echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidTokenType)
 		}
 
-		_, err = database.GetUserByID(claims.UserID)
+		err = database.ValidateSession(claims.UserID, claims.IssuedTime())
 		if err != nil {
 			return echo.NewHTTPError(http.StatusUnauthorized, custom_messages.InvalidToken)
 		}
@@ -7,9 +7,10 @@ import (
 )
 
 type Metrics struct {
-	Requests *prometheus.CounterVec
-	Queries  *prometheus.CounterVec
-	Delay    prometheus.Gauge
+	Requests  *prometheus.CounterVec
+	Queries   *prometheus.CounterVec
+	Throttled *prometheus.CounterVec
+	Delay     prometheus.Gauge
 }
 
 const (
@@ -36,6 +37,13 @@ func newMetrics() *Metrics {
 			},
 			[]string{"status"},
 		),
+		Throttled: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "total_number_of_throttled_requests",
+				Help: "How many HTTP requests were rejected by rate limiting, partitioned by the exceeded limit.",
+			},
+			[]string{"limit"},
+		),
 		Delay: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Name: "total_delay",
@@ -59,6 +67,9 @@ func InitalizeStatistics() {
 	if err := Registry.Register(Statistics.Queries); err != nil {
 		log.Fatal(err)
 	}
+	if err := Registry.Register(Statistics.Throttled); err != nil {
+		log.Fatal(err)
+	}
 	if err := Registry.Register(Statistics.Delay); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,233 @@
+package ratelimit
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_messages"
+)
+
+type RateLimitInfo struct {
+	ip_limit       int64
+	user_limit     int64
+	auth_ip_limit  int64
+	username_limit int64
+}
+
+const (
+	IPLimit       = "ip"
+	UserLimit     = "user"
+	AuthIPLimit   = "auth_ip"
+	UsernameLimit = "username"
+	LockoutLimit  = "lockout"
+)
+
+const window = time.Minute
+
+const failureWindow = time.Minute * 15
+const lockoutThreshold = 5
+const lockoutBase = time.Second * 30
+const lockoutMax = time.Hour
+
+var Info = &RateLimitInfo{
+	ip_limit:       600,
+	user_limit:     300,
+	auth_ip_limit:  20,
+	username_limit: 10,
+}
+
+func loadLimit(name string, default_limit int64) int64 {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		log.Println("Warn: Missing enviroment variable "+name+".",
+			fmt.Sprintf("Using default limit: [%d per minute]", default_limit))
+		return default_limit
+	}
+
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || limit <= 0 {
+		log.Println("Warn: Invalid enviroment variable "+name+".",
+			fmt.Sprintf("Using default limit: [%d per minute]", default_limit))
+		return default_limit
+	}
+
+	return limit
+}
+
+func LoadRateLimitConfig() {
+	Info = &RateLimitInfo{
+		ip_limit:       loadLimit("RATE_LIMIT_IP", 600),
+		user_limit:     loadLimit("RATE_LIMIT_USER", 300),
+		auth_ip_limit:  loadLimit("RATE_LIMIT_AUTH_IP", 20),
+		username_limit: loadLimit("RATE_LIMIT_AUTH_USERNAME", 10),
+	}
+}
+
+// IPExtractor only takes the client address from the X-Real-IP header when
+// the request comes through one of the proxies listed in TRUSTED_PROXIES.
+// Otherwise anyone could pick the address their attempts are counted against.
+func IPExtractor() echo.IPExtractor {
+	value, ok := os.LookupEnv("TRUSTED_PROXIES")
+	if !ok || value == "" {
+		log.Println("Warn: Missing enviroment variable TRUSTED_PROXIES.",
+			"Using the address of the connection.")
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, proxy := range strings.Split(value, ",") {
+		_, ip_range, err := net.ParseCIDR(strings.TrimSpace(proxy))
+		if err != nil {
+			log.Println("Warn: Invalid proxy range in TRUSTED_PROXIES:", proxy)
+			continue
+		}
+		options = append(options, echo.TrustIPRange(ip_range))
+	}
+
+	return echo.ExtractIPFromRealIPHeader(options...)
+}
+
+func tooManyRequests(c echo.Context, limit string, retry_after time.Duration) error {
+	monitoring.Statistics.Throttled.WithLabelValues(limit).Inc()
+
+	seconds := int64(math.Ceil(retry_after.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Response().Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+
+	return echo.NewHTTPError(http.StatusTooManyRequests, custom_messages.TooManyRequests)
+}
+
+// hit counts the request against the bucket and reports how long the caller
+// has to wait once the limit is exceeded. Requests are let through when Redis
+// is unavailable rather than taking the whole API down with it.
+func hit(bucket string, limit int64) (time.Duration, bool) {
+	count, ttl, err := database.IncrementRateCounter("ratelimit:"+bucket, window)
+	if err != nil {
+		return 0, true
+	}
+
+	return ttl, count <= limit
+}
+
+func lockedFor(subject string) time.Duration {
+	ttl, err := database.GetKeyTTL("ratelimit:lockout:" + subject)
+	if err != nil {
+		return 0
+	}
+
+	return ttl
+}
+
+// APIRateLimitMiddleware applies the general per-IP limit to every request.
+func APIRateLimitMiddleware(handler echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if retry_after, ok := hit("ip:"+c.RealIP(), Info.ip_limit); !ok {
+			return tooManyRequests(c, IPLimit, retry_after)
+		}
+
+		return handler(c)
+	}
+}
+
+// AuthRateLimitMiddleware applies the stricter per-IP limit of the
+// credential endpoints and rejects addresses that are locked out.
+func AuthRateLimitMiddleware(handler echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ip := c.RealIP()
+
+		if retry_after := lockedFor("ip:" + ip); retry_after > 0 {
+			return tooManyRequests(c, LockoutLimit, retry_after)
+		}
+
+		if retry_after, ok := hit("auth:ip:"+ip, Info.auth_ip_limit); !ok {
+			return tooManyRequests(c, AuthIPLimit, retry_after)
+		}
+
+		return handler(c)
+	}
+}
+
+// LimitUser applies the per-user limit once the requester is authenticated.
+func LimitUser(c echo.Context, user_id uint) error {
+	if retry_after, ok := hit(fmt.Sprintf("user:%d", user_id), Info.user_limit); !ok {
+		return tooManyRequests(c, UserLimit, retry_after)
+	}
+
+	return nil
+}
+
+// accountSubject ties the account to the address the attempt comes from, so
+// failures from one address cannot lock the owner of the account out.
+func accountSubject(c echo.Context, account string) string {
+	return "account:" + strings.ToLower(account) + ":ip:" + c.RealIP()
+}
+
+// CheckLoginAttempt is called with the account a login is aimed at, which the
+// middleware cannot see since it lives in the request body.
+func CheckLoginAttempt(c echo.Context, account string) error {
+	if retry_after := lockedFor(accountSubject(c, account)); retry_after > 0 {
+		return tooManyRequests(c, LockoutLimit, retry_after)
+	}
+
+	account = strings.ToLower(account)
+
+	if retry_after, ok := hit("auth:account:"+account, Info.username_limit); !ok {
+		return tooManyRequests(c, UsernameLimit, retry_after)
+	}
+
+	return nil
+}
+
+func lockoutDuration(failures int64) time.Duration {
+	exponent := failures - lockoutThreshold
+	if exponent > 10 {
+		return lockoutMax
+	}
+
+	duration := lockoutBase << exponent
+	if duration > lockoutMax {
+		return lockoutMax
+	}
+
+	return duration
+}
+
+// RegisterLoginFailure records a failed attempt for the account from the
+// address and for the address itself. Past the threshold each further
+// failure locks them out for twice as long as the previous one.
+func RegisterLoginFailure(c echo.Context, account string) {
+	subjects := []string{accountSubject(c, account), "ip:" + c.RealIP()}
+
+	for _, subject := range subjects {
+		failures, _, err := database.IncrementRateCounter("ratelimit:failures:"+subject, failureWindow)
+		if err != nil || failures < lockoutThreshold {
+			continue
+		}
+
+		database.SetExpiringKey("ratelimit:lockout:"+subject, lockoutDuration(failures))
+	}
+}
+
+func RegisterLoginSuccess(c echo.Context, account string) {
+	subject := accountSubject(c, account)
+
+	database.DeleteKeys("ratelimit:failures:"+subject, "ratelimit:lockout:"+subject)
+}
@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{lockoutThreshold, 30 * time.Second},
+		{lockoutThreshold + 1, time.Minute},
+		{lockoutThreshold + 2, 2 * time.Minute},
+		{lockoutThreshold + 3, 4 * time.Minute},
+		{lockoutThreshold + 6, 32 * time.Minute},
+		{lockoutThreshold + 7, time.Hour},
+		{lockoutThreshold + 10, time.Hour},
+		{lockoutThreshold + 11, time.Hour},
+		{100, time.Hour},
+	}
+
+	for _, test := range tests {
+		if got := lockoutDuration(test.failures); got != test.want {
+			t.Errorf("lockoutDuration(%d) = %v, want %v", test.failures, got, test.want)
+		}
+	}
+}
+
+func TestLockoutDurationNeverShrinks(t *testing.T) {
+	previous := lockoutDuration(lockoutThreshold)
+	for failures := int64(lockoutThreshold + 1); failures <= 200; failures++ {
+		duration := lockoutDuration(failures)
+		if duration < previous {
+			t.Fatalf("lockoutDuration(%d) = %v, shorter than %v for one failure less", failures, duration, previous)
+		}
+		previous = duration
+	}
+}
@@ -0,0 +1,81 @@
+package calendar_utils
+
+import (
+	"strings"
+	"time"
+)
+
+const dateTimeLayout = "20060102T150405Z"
+
+// Event is a single entry of an RFC 5545 calendar.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	Stamp       time.Time
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// foldLine splits lines longer than 75 octets as RFC 5545 requires, without
+// cutting a character in two.
+func foldLine(line string) string {
+	var folded strings.Builder
+	length := 0
+
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > 75 {
+			folded.WriteString("\r\n ")
+			length = 1
+		}
+		folded.WriteRune(r)
+		length += size
+	}
+
+	folded.WriteString("\r\n")
+	return folded.String()
+}
+
+func Build(name string, events []Event) string {
+	var calendar strings.Builder
+
+	write := func(line string) {
+		calendar.WriteString(foldLine(line))
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:-//trello//tasks//EN")
+	write("CALSCALE:GREGORIAN")
+	write("METHOD:PUBLISH")
+	write("X-WR-CALNAME:" + textEscaper.Replace(name))
+
+	for _, event := range events {
+		if event.Stamp.IsZero() {
+			event.Stamp = time.Now()
+		}
+
+		write("BEGIN:VEVENT")
+		write("UID:" + event.UID)
+		write("DTSTAMP:" + event.Stamp.UTC().Format(dateTimeLayout))
+		write("DTSTART:" + event.Start.UTC().Format(dateTimeLayout))
+		write("SUMMARY:" + textEscaper.Replace(event.Summary))
+		if event.Description != "" {
+			write("DESCRIPTION:" + textEscaper.Replace(event.Description))
+		}
+		write("END:VEVENT")
+	}
+
+	write("END:VCALENDAR")
+
+	return calendar.String()
+}
@@ -9,6 +9,8 @@ var ErrDuplicateEmail = errors.New("account with this email already exists")
 var ErrDuplicateWorspaceName = errors.New("workspace name has already been taken")
 var ErrDuplicateTaskTitle = errors.New("task with this title already exists")
 var ErrDuplicateSubtaskTitle = errors.New("subtask with this title already exists")
+var ErrDuplicateLabelName = errors.New("label with this name already exists")
+var ErrDuplicateRoleName = errors.New("role with this name already exists")
 
 var ErrCreateWorkspaceTableFailed = errors.New("failed to create workspace table")
 var ErrCreateTaskTableFailed = errors.New("failed to create task table")
@@ -18,6 +20,16 @@ var ErrCreateUserworkspaceroleTableFailed = errors.New("failed to create userwor
 var ErrCreateMessageTableFailed = errors.New("failed to create message table")
 var ErrCreateWatchTableFailed = errors.New("failed to create watch table")
 
+var ErrDependencyCycle = errors.New("dependency would create a cycle")
+var ErrTaskBlocked = errors.New("task is blocked by unfinished tasks")
+var ErrDuplicateExternalID = errors.New("task for this external id is already being created")
+var ErrTwoFactorRequired = errors.New("two-factor authentication is required by this workspace")
+var ErrEmailNotVerified = errors.New("email address has not been verified")
+var ErrDuplicateInvitation = errors.New("user has already been invited to this workspace")
+var ErrAlreadyMember = errors.New("user is already a member of this workspace")
+var ErrLastOwner = errors.New("workspace would be left without an owner, transfer ownership first")
+var ErrRoleInUse = errors.New("role is still held by members or pending invitations")
+
 var ErrTokenFailure = errors.New("failed to generate token")
 var ErrInvalidArguments = errors.New("invalid arguments")
 
@@ -1,25 +1,70 @@
 package custom_messages
 
 const (
-	InvalidCredentials = "invalid credentials"
-	InvalidWorkspaceId = "invalid workspace id"
-	InvalidTaskId      = "invalid task id"
-	InvalidSubtaskId   = "invalid subtask id"
-	InvalidUserId      = "invalid user id"
-	InvalidContentType = "invalid content type"
-	InvalidBodyFormat  = "invalid body format"
-	InvalidStatus      = "invalid status format"
-	InvalidIsCompleted = "invalid is-completed format"
-	InvalidRole        = "invlaid role"
-	PermissionDenied   = "permission denied"
-	MissingData        = "missing data"
+	InvalidCredentials    = "invalid credentials"
+	InvalidWorkspaceId    = "invalid workspace id"
+	InvalidTaskId         = "invalid task id"
+	InvalidSubtaskId      = "invalid subtask id"
+	InvalidUserId         = "invalid user id"
+	InvalidLabelId        = "invalid label id"
+	InvalidColor          = "invalid color format"
+	InvalidDependencyId   = "invalid dependency id"
+	InvalidDependencyType = "invalid dependency type"
+	InvalidRecurrenceId   = "invalid recurrence id"
+	InvalidRecurrenceRule = "invalid recurrence rule"
+	InvalidDate           = "invalid date format"
+	InvalidTemplateId     = "invalid template id"
+	InvalidNotificationId = "invalid notification id"
+	InvalidPagination     = "invalid pagination parameters"
+	InvalidEmailMode      = "invalid email mode"
+	Unsubscribed          = "you have been unsubscribed from email notifications"
+	EmailVerified         = "your email address has been verified"
+	InvalidWebhookId      = "invalid webhook id"
+	InvalidWebhookEvent   = "invalid webhook event"
+	InvalidURL            = "invalid url format"
+	InvalidFieldMapping   = "invalid field mapping"
+	InvalidExternalId     = "invalid external id"
+	InvalidTokenId        = "invalid token id"
+	InvalidTokenScope     = "invalid token scope"
+	InvalidServiceAccount = "invalid service account id"
+	InvalidInvitationId   = "invalid invitation id"
+	InvalidInvitation     = "invalid or expired invitation"
+	InvalidCommentId      = "invalid comment id"
+	InvalidTransferId     = "invalid ownership transfer id"
+	InvalidTrashId        = "invalid trash item id"
+	InvalidArchivedFilter = "invalid archived filter"
+	InvalidArchiveAge     = "invalid archive age"
+	InvalidBulkOperation  = "invalid bulk operation"
+	InvalidTaskList       = "task ids must list between 1 and 200 tasks"
+	InvalidImportId       = "invalid import job id"
+	InvalidTrelloBoard    = "invalid trello board or list statuses"
+	InvalidCSVImport      = "invalid csv or column mapping"
+	InvalidReportColumns  = "invalid report columns"
+	InvalidCalendarToken  = "invalid calendar feed token"
+	InvalidTransfer       = "invalid or expired ownership transfer"
+	InvalidRoleId         = "invalid role id"
+	InvalidPermission     = "invalid permission"
+	InvalidContentType    = "invalid content type"
+	InvalidBodyFormat     = "invalid body format"
+	InvalidStatus         = "invalid status format"
+	InvalidIsCompleted    = "invalid is-completed format"
+	InvalidRole           = "invlaid role"
+	PermissionDenied      = "permission denied"
+	MissingData           = "missing data"
 )
 
 const (
-	MissingToken       = "missing auth token"
-	InvalidTokenFormat = "invalid auth token format"
-	InvalidToken       = "invalid auth token"
-	InvalidTokenType   = "invalid token type"
+	MissingToken        = "missing auth token"
+	InvalidTokenFormat  = "invalid auth token format"
+	InvalidToken        = "invalid auth token"
+	InvalidTokenType    = "invalid token type"
+	InsufficientScope   = "token scope does not allow this request"
+	SSODisabled         = "single sign-on is not enabled"
+	InvalidSSOState     = "invalid or expired sign-on state"
+	PasswordDisabled    = "password login is disabled"
+	InvalidTwoFactor    = "invalid two-factor code"
+	TooManyRequests     = "too many requests, try again later"
+	InvalidAccountToken = "invalid or expired link"
 )
 
 const (
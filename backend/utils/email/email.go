@@ -0,0 +1,156 @@
+package email_utils
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"time"
+)
+
+type SMTPInfo struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+	base_url string
+}
+
+var Info *SMTPInfo
+
+func LoadSMTPConfig() bool {
+	host, ok := os.LookupEnv("SMTP_HOST")
+	if !ok {
+		log.Println("Warn: Missing enviroment variable SMTP_HOST.",
+			"Email notifications are disabled.")
+		return false
+	}
+
+	port, ok := os.LookupEnv("SMTP_PORT")
+	if !ok {
+		log.Println("Warn: Missing enviroment variable SMTP_PORT.",
+			"Using default port: [25]")
+		port = "25"
+	}
+
+	from, ok := os.LookupEnv("SMTP_FROM")
+	if !ok {
+		log.Println("Warn: Missing enviroment variable SMTP_FROM.",
+			"Using default sender: [noreply@localhost]")
+		from = "noreply@localhost"
+	}
+
+	base_url, ok := os.LookupEnv("APP_BASE_URL")
+	if !ok {
+		log.Println("Warn: Missing enviroment variable APP_BASE_URL.",
+			"Using default base url: [http://localhost]")
+		base_url = "http://localhost"
+	}
+
+	Info = &SMTPInfo{
+		host:     host,
+		port:     port,
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     from,
+		base_url: base_url,
+	}
+
+	return true
+}
+
+func baseURL() string {
+	if Info != nil {
+		return Info.base_url
+	}
+	return "http://localhost"
+}
+
+func UnsubscribeURL(token string) string {
+	return fmt.Sprintf("%s/api/unsubscribe/%s", baseURL(), token)
+}
+
+func CalendarURL(token string) string {
+	return fmt.Sprintf("%s/api/calendar/%s.ics", baseURL(), token)
+}
+
+func VerificationURL(token string) string {
+	return fmt.Sprintf("%s/api/email/verify/%s", baseURL(), token)
+}
+
+func SignupURL() string {
+	return fmt.Sprintf("%s/signup", baseURL())
+}
+
+func PasswordResetURL(token string) string {
+	return fmt.Sprintf("%s/reset-password?token=%s", baseURL(), token)
+}
+
+func buildMessage(to string, subject string, text_body string, html_body string, unsubscribe_url string) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		content_type string
+		content      string
+	}{
+		{"text/plain; charset=UTF-8", text_body},
+		{"text/html; charset=UTF-8", html_body},
+	}
+
+	for _, part := range parts {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.content_type)
+		header.Set("Content-Transfer-Encoding", "8bit")
+
+		part_writer, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part_writer.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", Info.from)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	if unsubscribe_url != "" {
+		fmt.Fprintf(&message, "List-Unsubscribe: <%s>\r\n", unsubscribe_url)
+		fmt.Fprintf(&message, "List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	}
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+func Send(to string, subject string, text_body string, html_body string, unsubscribe_url string) error {
+	if Info == nil {
+		return ErrSMTPNotConfigured
+	}
+
+	message, err := buildMessage(to, subject, text_body, html_body, unsubscribe_url)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if Info.username != "" {
+		auth = smtp.PlainAuth("", Info.username, Info.password, Info.host)
+	}
+
+	return smtp.SendMail(Info.host+":"+Info.port, auth, Info.from, []string{to}, message)
+}
@@ -0,0 +1,108 @@
+package email_utils
+
+import (
+	"bytes"
+	"errors"
+	html_template "html/template"
+	text_template "text/template"
+)
+
+var ErrSMTPNotConfigured = errors.New("smtp is not configured")
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+type TemplateData struct {
+	Username       string
+	Message        string
+	Messages       []string
+	UnsubscribeURL string
+	ActionURL      string
+}
+
+type emailTemplate struct {
+	subject string
+	text    *text_template.Template
+	html    *html_template.Template
+}
+
+const textFooter = `
+--
+You are receiving this email because of your notification settings.
+Unsubscribe: {{.UnsubscribeURL}}
+`
+
+const htmlFooter = `
+<hr>
+<p style="color:#888;font-size:12px">
+You are receiving this email because of your notification settings.
+<a href="{{.UnsubscribeURL}}">Unsubscribe</a>
+</p>
+`
+
+func newTemplate(subject string, text string, html string) emailTemplate {
+	return emailTemplate{
+		subject: subject,
+		text:    text_template.Must(text_template.New(subject).Parse(text + textFooter)),
+		html:    html_template.Must(html_template.New(subject).Parse(html + htmlFooter)),
+	}
+}
+
+// newAccountTemplate builds templates for account emails, which are sent
+// regardless of notification settings and therefore carry no unsubscribe link.
+func newAccountTemplate(subject string, text string, html string) emailTemplate {
+	return emailTemplate{
+		subject: subject,
+		text:    text_template.Must(text_template.New(subject).Parse(text)),
+		html:    html_template.Must(html_template.New(subject).Parse(html)),
+	}
+}
+
+var templates = map[string]emailTemplate{
+	"assignment": newTemplate("You have a new assignment",
+		"Hi {{.Username}},\n\n{{.Message}}\n",
+		"<p>Hi {{.Username}},</p>\n<p>{{.Message}}</p>\n"),
+	"mention": newTemplate("You were mentioned",
+		"Hi {{.Username}},\n\n{{.Message}}\n",
+		"<p>Hi {{.Username}},</p>\n<p>{{.Message}}</p>\n"),
+	"reminder": newTemplate("Task due date reminder",
+		"Hi {{.Username}},\n\n{{.Message}}\n",
+		"<p>Hi {{.Username}},</p>\n<p>{{.Message}}</p>\n"),
+	"digest": newTemplate("Your daily digest",
+		"Hi {{.Username}},\n\nHere is what happened since your last digest:\n{{range .Messages}}\n- {{.}}{{end}}\n",
+		"<p>Hi {{.Username}},</p>\n<p>Here is what happened since your last digest:</p>\n<ul>{{range .Messages}}\n<li>{{.}}</li>{{end}}\n</ul>\n"),
+	"invitation": newTemplate("You have been invited to a workspace",
+		"Hi {{.Username}},\n\n{{.Message}}\n",
+		"<p>Hi {{.Username}},</p>\n<p>{{.Message}}</p>\n"),
+	"invitation_signup": newAccountTemplate("You have been invited to a workspace",
+		"Hi,\n\n{{.Message}}\n\nCreate an account with this email address to accept it:\n{{.ActionURL}}\n",
+		"<p>Hi,</p>\n<p>{{.Message}}</p>\n<p><a href=\"{{.ActionURL}}\">Create an account</a> with this email address to accept it.</p>\n"),
+	"verify_email": newAccountTemplate("Confirm your email address",
+		"Hi {{.Username}},\n\nPlease confirm your email address by opening the link below:\n{{.ActionURL}}\n\nIf you did not sign up, you can ignore this email.\n",
+		"<p>Hi {{.Username}},</p>\n<p>Please confirm your email address by opening the link below:</p>\n<p><a href=\"{{.ActionURL}}\">Confirm email address</a></p>\n<p>If you did not sign up, you can ignore this email.</p>\n"),
+	"password_reset": newAccountTemplate("Reset your password",
+		"Hi {{.Username}},\n\nA password reset was requested for your account. Open the link below to choose a new password:\n{{.ActionURL}}\n\nThe link expires in one hour. If you did not request it, you can ignore this email.\n",
+		"<p>Hi {{.Username}},</p>\n<p>A password reset was requested for your account. Open the link below to choose a new password:</p>\n<p><a href=\"{{.ActionURL}}\">Reset password</a></p>\n<p>The link expires in one hour. If you did not request it, you can ignore this email.</p>\n"),
+}
+
+func HasTemplate(name string) bool {
+	_, ok := templates[name]
+	return ok
+}
+
+func Render(name string, data TemplateData) (string, string, string, error) {
+	template, ok := templates[name]
+	if !ok {
+		return "", "", "", ErrUnknownTemplate
+	}
+
+	var text_body bytes.Buffer
+	if err := template.text.Execute(&text_body, data); err != nil {
+		return "", "", "", err
+	}
+
+	var html_body bytes.Buffer
+	if err := template.html.Execute(&html_body, data); err != nil {
+		return "", "", "", err
+	}
+
+	return template.subject, text_body.String(), html_body.String(), nil
+}
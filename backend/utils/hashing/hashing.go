@@ -1,7 +1,9 @@
 package hashing_utils
 
 import (
+	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/hex"
 )
 
 func HashUsingSha256(input string) []byte {
@@ -12,3 +14,12 @@ func HashUsingSha256(input string) []byte {
 
 	return hashed_input
 }
+
+func SignUsingHmacSha256(secret string, input []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(input)
+
+	signature := mac.Sum(nil)
+
+	return hex.EncodeToString(signature)
+}
@@ -0,0 +1,328 @@
+package oidc_utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrOIDCNotConfigured = errors.New("single sign-on is not configured")
+var ErrOIDCExchangeFailed = errors.New("failed to exchange authorization code")
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+type OIDCInfo struct {
+	issuer           string
+	client_id        string
+	client_secret    string
+	redirect_url     string
+	scopes           string
+	post_login_url   string
+	auto_provision   bool
+	password_enabled bool
+
+	mutex     sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]*rsa.PublicKey
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type Claims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+	Name              string `json:"name"`
+	Nonce             string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+var Info = &OIDCInfo{auto_provision: true, password_enabled: true}
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+func LoadOIDCConfig() {
+	info := &OIDCInfo{
+		auto_provision:   os.Getenv("OIDC_AUTO_PROVISION") != "false",
+		password_enabled: os.Getenv("PASSWORD_LOGIN_ENABLED") != "false",
+	}
+
+	issuer, ok := os.LookupEnv("OIDC_ISSUER")
+	if !ok {
+		log.Println("Warn: Missing enviroment variable OIDC_ISSUER.",
+			"Single sign-on is disabled.")
+		Info = info
+		return
+	}
+
+	client_id, ok := os.LookupEnv("OIDC_CLIENT_ID")
+	if !ok {
+		log.Println("Warn: Missing enviroment variable OIDC_CLIENT_ID.",
+			"Single sign-on is disabled.")
+		Info = info
+		return
+	}
+
+	redirect_url, ok := os.LookupEnv("OIDC_REDIRECT_URL")
+	if !ok {
+		log.Println("Warn: Missing enviroment variable OIDC_REDIRECT_URL.",
+			"Using default redirect url: [http://localhost/api/auth/oidc/callback]")
+		redirect_url = "http://localhost/api/auth/oidc/callback"
+	}
+
+	scopes, ok := os.LookupEnv("OIDC_SCOPES")
+	if !ok {
+		scopes = "openid email profile"
+	}
+
+	info.issuer = strings.TrimSuffix(issuer, "/")
+	info.client_id = client_id
+	info.client_secret = os.Getenv("OIDC_CLIENT_SECRET")
+	info.redirect_url = redirect_url
+	info.scopes = scopes
+	info.post_login_url = os.Getenv("OIDC_POST_LOGIN_URL")
+
+	Info = info
+}
+
+func Enabled() bool {
+	return Info.issuer != ""
+}
+
+func PasswordLoginEnabled() bool {
+	return Info.password_enabled
+}
+
+func AutoProvisionEnabled() bool {
+	return Info.auto_provision
+}
+
+func Issuer() string {
+	return Info.issuer
+}
+
+func PostLoginURL() string {
+	return Info.post_login_url
+}
+
+func RandomString() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+func CodeChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func (info *OIDCInfo) getDiscovery() (*discoveryDocument, error) {
+	info.mutex.Lock()
+	defer info.mutex.Unlock()
+
+	if info.discovery != nil {
+		return info.discovery, nil
+	}
+
+	response, err := client.Get(info.issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery returned status %d", response.StatusCode)
+	}
+
+	var discovery discoveryDocument
+	if err := json.NewDecoder(response.Body).Decode(&discovery); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != info.issuer {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", discovery.Issuer, info.issuer)
+	}
+
+	info.discovery = &discovery
+	return info.discovery, nil
+}
+
+func (info *OIDCInfo) fetchKeys(jwks_uri string) (map[string]*rsa.PublicKey, error) {
+	response, err := client.Get(jwks_uri)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&jwks); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range jwks.Keys {
+		if key.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			continue
+		}
+
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+// getKey looks the signing key up by its id and refetches the key set once
+// when it is unknown, so key rotation on the provider side is picked up.
+func (info *OIDCInfo) getKey(jwks_uri string, kid string) (*rsa.PublicKey, error) {
+	info.mutex.Lock()
+	defer info.mutex.Unlock()
+
+	if key, ok := info.keys[kid]; ok {
+		return key, nil
+	}
+
+	keys, err := info.fetchKeys(jwks_uri)
+	if err != nil {
+		return nil, err
+	}
+	info.keys = keys
+
+	if key, ok := info.keys[kid]; ok {
+		return key, nil
+	} else if len(info.keys) == 1 && kid == "" {
+		for _, key := range info.keys {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func AuthorizationURL(state string, nonce string, verifier string) (string, error) {
+	if !Enabled() {
+		return "", ErrOIDCNotConfigured
+	}
+
+	discovery, err := Info.getDiscovery()
+	if err != nil {
+		log.Println("Error:", err)
+		return "", ErrOIDCNotConfigured
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", Info.client_id)
+	query.Set("redirect_uri", Info.redirect_url)
+	query.Set("scope", Info.scopes)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+func Exchange(code string, verifier string, nonce string) (*Claims, error) {
+	if !Enabled() {
+		return nil, ErrOIDCNotConfigured
+	}
+
+	discovery, err := Info.getDiscovery()
+	if err != nil {
+		log.Println("Error:", err)
+		return nil, ErrOIDCNotConfigured
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", Info.redirect_url)
+	form.Set("client_id", Info.client_id)
+	form.Set("code_verifier", verifier)
+	if Info.client_secret != "" {
+		form.Set("client_secret", Info.client_secret)
+	}
+
+	response, err := client.PostForm(discovery.TokenEndpoint, form)
+	if err != nil {
+		log.Println("Error:", err)
+		return nil, ErrOIDCExchangeFailed
+	}
+	defer response.Body.Close()
+
+	var token_response struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&token_response); err != nil {
+		log.Println("Error:", err)
+		return nil, ErrOIDCExchangeFailed
+	} else if response.StatusCode != http.StatusOK || token_response.IDToken == "" {
+		log.Println("Error: token endpoint returned", response.StatusCode, token_response.Error)
+		return nil, ErrOIDCExchangeFailed
+	}
+
+	claims := &Claims{}
+	_, err = jwt.ParseWithClaims(token_response.IDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return Info.getKey(discovery.JWKSURI, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(Info.client_id),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute))
+
+	if err != nil {
+		log.Println("Error:", err)
+		return nil, ErrInvalidIDToken
+	} else if claims.Nonce != nonce || claims.Subject == "" {
+		return nil, ErrInvalidIDToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,197 @@
+package recurrence_utils
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule is the supported subset of an RFC 5545 RRULE:
+// FREQ=DAILY|WEEKLY|MONTHLY with optional INTERVAL, BYDAY (weekly only)
+// and BYMONTHDAY (monthly only).
+type Rule struct {
+	Frequency string
+	Interval  int
+	Weekdays  []time.Weekday
+	MonthDay  int
+}
+
+func ParseRule(rule string) (Rule, error) {
+	parsed := Rule{Interval: 1}
+
+	for _, part := range strings.Split(strings.TrimPrefix(strings.ToUpper(rule), "RRULE:"), ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Rule{}, ErrInvalidRule
+		}
+
+		switch key {
+		case "FREQ":
+			if value != Daily && value != Weekly && value != Monthly {
+				return Rule{}, ErrInvalidRule
+			}
+			parsed.Frequency = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return Rule{}, ErrInvalidRule
+			}
+			parsed.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return Rule{}, ErrInvalidRule
+				}
+				parsed.Weekdays = append(parsed.Weekdays, weekday)
+			}
+		case "BYMONTHDAY":
+			month_day, err := strconv.Atoi(value)
+			if err != nil || month_day < 1 || month_day > 31 {
+				return Rule{}, ErrInvalidRule
+			}
+			parsed.MonthDay = month_day
+		default:
+			return Rule{}, ErrInvalidRule
+		}
+	}
+
+	if parsed.Frequency == "" {
+		return Rule{}, ErrInvalidRule
+	} else if len(parsed.Weekdays) != 0 && parsed.Frequency != Weekly {
+		return Rule{}, ErrInvalidRule
+	} else if parsed.MonthDay != 0 && parsed.Frequency != Monthly {
+		return Rule{}, ErrInvalidRule
+	}
+
+	return parsed, nil
+}
+
+func (rule Rule) String() string {
+	parts := []string{"FREQ=" + rule.Frequency}
+	if rule.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(rule.Interval))
+	}
+	if len(rule.Weekdays) != 0 {
+		days := []string{}
+		for _, weekday := range rule.Weekdays {
+			for name, value := range weekdays {
+				if value == weekday {
+					days = append(days, name)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if rule.MonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(rule.MonthDay))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence strictly after the given time for a
+// series that started at anchor. The time of day is taken from anchor.
+func (rule Rule) Next(anchor time.Time, after time.Time) time.Time {
+	if after.Before(anchor) {
+		after = anchor.Add(-time.Nanosecond)
+	}
+
+	switch rule.Frequency {
+	case Daily:
+		return rule.nextDaily(anchor, after)
+	case Weekly:
+		return rule.nextWeekly(anchor, after)
+	default:
+		return rule.nextMonthly(anchor, after)
+	}
+}
+
+func daysBetween(from time.Time, to time.Time) int {
+	from_date := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to_date := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to_date.Sub(from_date).Hours() / 24)
+}
+
+func (rule Rule) nextDaily(anchor time.Time, after time.Time) time.Time {
+	steps := daysBetween(anchor, after) / rule.Interval
+	for {
+		candidate := anchor.AddDate(0, 0, steps*rule.Interval)
+		if candidate.After(after) {
+			return candidate
+		}
+		steps++
+	}
+}
+
+func (rule Rule) nextWeekly(anchor time.Time, after time.Time) time.Time {
+	days := rule.Weekdays
+	if len(days) == 0 {
+		days = []time.Weekday{anchor.Weekday()}
+	}
+
+	week_start := anchor.AddDate(0, 0, -int(anchor.Weekday()))
+	offset := daysBetween(anchor, after)
+	if offset < 0 {
+		offset = 0
+	}
+
+	for {
+		candidate := anchor.AddDate(0, 0, offset)
+		weeks := daysBetween(week_start, candidate) / 7
+		if candidate.After(after) && weeks%rule.Interval == 0 {
+			for _, day := range days {
+				if candidate.Weekday() == day {
+					return candidate
+				}
+			}
+		}
+		offset++
+	}
+}
+
+func (rule Rule) nextMonthly(anchor time.Time, after time.Time) time.Time {
+	month_day := rule.MonthDay
+	if month_day == 0 {
+		month_day = anchor.Day()
+	}
+
+	months := (after.Year()-anchor.Year())*12 + int(after.Month()) - int(anchor.Month())
+	steps := months / rule.Interval
+	if steps < 0 {
+		steps = 0
+	}
+
+	for {
+		first := time.Date(anchor.Year(), anchor.Month()+time.Month(steps*rule.Interval), 1,
+			anchor.Hour(), anchor.Minute(), anchor.Second(), 0, anchor.Location())
+		last_day := first.AddDate(0, 1, -1).Day()
+		day := month_day
+		if day > last_day {
+			day = last_day
+		}
+		candidate := first.AddDate(0, 0, day-1)
+		if candidate.After(after) && !candidate.Before(anchor) {
+			return candidate
+		}
+		steps++
+	}
+}
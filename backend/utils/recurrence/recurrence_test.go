@@ -0,0 +1,131 @@
+package recurrence_utils
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestRuleNext(t *testing.T) {
+	new_york, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("loading time zone: %v", err)
+	}
+
+	date := func(location *time.Location, year int, month time.Month, day int, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, location)
+	}
+
+	tests := []struct {
+		name   string
+		rule   string
+		anchor time.Time
+		after  time.Time
+		want   time.Time
+	}{
+		{
+			name:   "first occurrence is the anchor",
+			rule:   "FREQ=DAILY",
+			anchor: date(time.UTC, 2026, time.March, 1, 9),
+			after:  date(time.UTC, 2026, time.February, 1, 9),
+			want:   date(time.UTC, 2026, time.March, 1, 9),
+		},
+		{
+			name:   "daily with interval",
+			rule:   "FREQ=DAILY;INTERVAL=3",
+			anchor: date(time.UTC, 2026, time.January, 30, 9),
+			after:  date(time.UTC, 2026, time.February, 1, 12),
+			want:   date(time.UTC, 2026, time.February, 2, 9),
+		},
+		{
+			name:   "daily into daylight saving time",
+			rule:   "FREQ=DAILY",
+			anchor: date(new_york, 2026, time.March, 7, 9),
+			after:  date(new_york, 2026, time.March, 7, 9),
+			want:   date(new_york, 2026, time.March, 8, 9),
+		},
+		{
+			name:   "daily out of daylight saving time",
+			rule:   "FREQ=DAILY",
+			anchor: date(new_york, 2026, time.October, 31, 9),
+			after:  date(new_york, 2026, time.October, 31, 9),
+			want:   date(new_york, 2026, time.November, 1, 9),
+		},
+		{
+			name:   "weekly on several days",
+			rule:   "FREQ=WEEKLY;BYDAY=MO,WE",
+			anchor: date(time.UTC, 2026, time.January, 5, 9),
+			after:  date(time.UTC, 2026, time.January, 5, 9),
+			want:   date(time.UTC, 2026, time.January, 7, 9),
+		},
+		{
+			name:   "every other week skips a week",
+			rule:   "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			anchor: date(time.UTC, 2026, time.January, 5, 9),
+			after:  date(time.UTC, 2026, time.January, 7, 9),
+			want:   date(time.UTC, 2026, time.January, 19, 9),
+		},
+		{
+			name:   "weekly across the end of a month",
+			rule:   "FREQ=WEEKLY",
+			anchor: date(time.UTC, 2026, time.January, 28, 9),
+			after:  date(time.UTC, 2026, time.January, 28, 9),
+			want:   date(time.UTC, 2026, time.February, 4, 9),
+		},
+		{
+			name:   "weekly into daylight saving time",
+			rule:   "FREQ=WEEKLY",
+			anchor: date(new_york, 2026, time.March, 1, 9),
+			after:  date(new_york, 2026, time.March, 1, 9),
+			want:   date(new_york, 2026, time.March, 8, 9),
+		},
+		{
+			name:   "monthly on the 31st falls back to the end of february",
+			rule:   "FREQ=MONTHLY",
+			anchor: date(time.UTC, 2026, time.January, 31, 10),
+			after:  date(time.UTC, 2026, time.January, 31, 10),
+			want:   date(time.UTC, 2026, time.February, 28, 10),
+		},
+		{
+			name:   "monthly on the 31st returns to the 31st",
+			rule:   "FREQ=MONTHLY",
+			anchor: date(time.UTC, 2026, time.January, 31, 10),
+			after:  date(time.UTC, 2026, time.February, 28, 10),
+			want:   date(time.UTC, 2026, time.March, 31, 10),
+		},
+		{
+			name:   "month day in a leap year",
+			rule:   "FREQ=MONTHLY;BYMONTHDAY=30",
+			anchor: date(time.UTC, 2028, time.January, 30, 10),
+			after:  date(time.UTC, 2028, time.January, 30, 10),
+			want:   date(time.UTC, 2028, time.February, 29, 10),
+		},
+		{
+			name:   "quarterly across the end of a year",
+			rule:   "FREQ=MONTHLY;INTERVAL=3",
+			anchor: date(time.UTC, 2026, time.November, 30, 10),
+			after:  date(time.UTC, 2026, time.November, 30, 10),
+			want:   date(time.UTC, 2027, time.February, 28, 10),
+		},
+		{
+			name:   "monthly into daylight saving time",
+			rule:   "FREQ=MONTHLY",
+			anchor: date(new_york, 2026, time.February, 15, 9),
+			after:  date(new_york, 2026, time.February, 15, 9),
+			want:   date(new_york, 2026, time.March, 15, 9),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := ParseRule(test.rule)
+			if err != nil {
+				t.Fatalf("parsing %q: %v", test.rule, err)
+			}
+
+			if got := rule.Next(test.anchor, test.after); !got.Equal(test.want) {
+				t.Errorf("Next(%v, %v) = %v, want %v", test.anchor, test.after, got, test.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ const (
 	usernameRegex = "^[A-Za-z0-9]{4,12}$"
 	emailRegex    = "^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9-]+(?:\\.[a-zA-Z0-9-]+)*$"
 	passwordRegex = "^(?=.*[a-z])(?=.*[A-Z])(?=.*[0-9])(?=.*[@#$!%*?&])[A-Za-z0-9@#$!%*?&]{8,32}$"
+	colorRegex    = "^#[0-9a-fA-F]{6}$"
+	mentionRegex  = "(?<![A-Za-z0-9])@([A-Za-z0-9]{4,12})(?![A-Za-z0-9])"
 )
 
 func ValidateUsername(username string) bool {
@@ -41,3 +43,27 @@ func ValidatePassword(password string) bool {
 	}
 	return match
 }
+
+func ValidateColor(color string) bool {
+	regex := regexp2.MustCompile(colorRegex, 0)
+	match, err := regex.MatchString(color)
+	if err != nil {
+		log.Println("Error:", err)
+		return false
+	}
+	return match
+}
+
+func ExtractMentions(text string) []string {
+	mentions := []string{}
+	regex := regexp2.MustCompile(mentionRegex, 0)
+	match, err := regex.FindStringMatch(text)
+	for match != nil && err == nil {
+		mentions = append(mentions, match.GroupByNumber(1).String())
+		match, err = regex.FindNextMatch(match)
+	}
+	if err != nil {
+		log.Println("Error:", err)
+	}
+	return mentions
+}
@@ -1,7 +1,10 @@
 package token_utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,6 +15,7 @@ import (
 const (
 	Refresh TokenType = iota + 1
 	Access
+	TwoFactor
 )
 
 type TokenType int
@@ -24,12 +28,24 @@ type CustomClaims struct {
 
 var secretKey = []byte("e903e653cff56bba3447b0857c18cd7d0e1680ec9ca6dd59d51ee926098bf4c1")
 
+const PersonalTokenPrefix = "tpat_"
+
 const refreshTokenValidPeriod = time.Hour * 24
 const accessTokenValidPeriod = time.Minute * 30
+const twoFactorTokenValidPeriod = time.Minute * 5
+
+// Issue times are compared with session revocations, which are recorded to
+// the microsecond, so the default one second precision would let tokens
+// issued shortly before a revocation survive it.
+func init() {
+	jwt.TimePrecision = time.Microsecond
+}
 
 func calculateValidPeriod(token_type TokenType) time.Time {
 	if token_type == Refresh {
 		return time.Now().Add(refreshTokenValidPeriod)
+	} else if token_type == TwoFactor {
+		return time.Now().Add(twoFactorTokenValidPeriod)
 	} else {
 		return time.Now().Add(accessTokenValidPeriod)
 	}
@@ -41,6 +57,7 @@ func GenerateToken(user_id uint, token_type TokenType) (string, error) {
 		Type:   token_type,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(calculateValidPeriod(token_type)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
@@ -55,6 +72,15 @@ func GenerateToken(user_id uint, token_type TokenType) (string, error) {
 	return token_string, nil
 }
 
+// IssuedTime returns when the token was issued, or the zero time for tokens
+// issued before the claim was added so that any revocation covers them.
+func (claims *CustomClaims) IssuedTime() time.Time {
+	if claims.IssuedAt == nil {
+		return time.Time{}
+	}
+	return claims.IssuedAt.Time
+}
+
 func GenerateTokens(user_id uint) (string, string, error) {
 	refresh_token, err := GenerateToken(user_id, Refresh)
 	if err != nil {
@@ -83,3 +109,17 @@ func ParseToken(token_string string) (*CustomClaims, error) {
 		return nil, custom_errors.ErrTokenFailure
 	}
 }
+
+func GeneratePersonalToken() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		log.Println("Error:", err)
+		return "", custom_errors.ErrTokenFailure
+	}
+
+	return PersonalTokenPrefix + hex.EncodeToString(buffer), nil
+}
+
+func IsPersonalToken(token_string string) bool {
+	return strings.HasPrefix(token_string, PersonalTokenPrefix)
+}
@@ -0,0 +1,121 @@
+package totp_utils
+
+import (
+	"testing"
+	"time"
+)
+
+// secret is the RFC 6238 SHA1 test key "12345678901234567890" in base32.
+const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, test := range tests {
+		code, err := GenerateCode(secret, time.Unix(test.unix, 0))
+		if err != nil {
+			t.Fatalf("GenerateCode at %d: %v", test.unix, err)
+		} else if code != test.want {
+			t.Errorf("GenerateCode at %d = %s, want %s", test.unix, code, test.want)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / period
+
+	tests := []struct {
+		name      string
+		generated time.Time
+		want_ok   bool
+		want_step int64
+	}{
+		{"current step", now, true, current},
+		{"previous step", now.Add(-period * time.Second), true, current - 1},
+		{"next step", now.Add(period * time.Second), true, current + 1},
+		{"two steps behind", now.Add(-2 * period * time.Second), false, 0},
+		{"two steps ahead", now.Add(2 * period * time.Second), false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, err := GenerateCode(secret, test.generated)
+			if err != nil {
+				t.Fatalf("generating code: %v", err)
+			}
+
+			step, ok := Validate(secret, code, now)
+			if ok != test.want_ok || step != test.want_step {
+				t.Errorf("Validate = (%d, %v), want (%d, %v)", step, ok, test.want_step, test.want_ok)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsMalformedInput(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+	}{
+		{"short code", secret, "05924"},
+		{"long code", secret, "0005924"},
+		{"wrong code", secret, "005925"},
+		{"invalid secret", "not base32!", "005924"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, ok := Validate(test.secret, test.code, now); ok {
+				t.Errorf("Validate(%q, %q) accepted", test.secret, test.code)
+			}
+		})
+	}
+}
+
+// TestValidateReplay follows how the two-factor queries use the matched step:
+// a code is only accepted when its step is newer than the last one used.
+func TestValidateReplay(t *testing.T) {
+	start := time.Unix(1234567890, 0)
+
+	current_code, _ := GenerateCode(secret, start)
+	previous_code, _ := GenerateCode(secret, start.Add(-period*time.Second))
+	next_code, _ := GenerateCode(secret, start.Add(period*time.Second))
+
+	attempts := []struct {
+		name    string
+		code    string
+		at      time.Time
+		want_ok bool
+	}{
+		{"fresh code", current_code, start, true},
+		{"same code again", current_code, start.Add(5 * time.Second), false},
+		{"older code after a newer one", previous_code, start.Add(10 * time.Second), false},
+		{"code of the next step", next_code, start.Add(period * time.Second), true},
+		{"next code replayed", next_code, start.Add(period*time.Second + 5*time.Second), false},
+	}
+
+	var last_used_step int64
+	for _, attempt := range attempts {
+		step, ok := Validate(secret, attempt.code, attempt.at)
+		accepted := ok && step > last_used_step
+		if accepted {
+			last_used_step = step
+		}
+
+		if accepted != attempt.want_ok {
+			t.Errorf("%s: accepted = %v, want %v", attempt.name, accepted, attempt.want_ok)
+		}
+	}
+}
@@ -10,9 +10,11 @@ const (
 	CommentGroup   = "comment"
 	MemeberGroup   = "memeber"
 	WorkspaceGroup = "workspace"
+	LabelGroup     = "label"
 
-	UpdateType = "update"
-	WatchType  = "watch"
+	UpdateType   = "update"
+	WatchType    = "watch"
+	ReminderType = "reminder"
 )
 
 const (
@@ -34,6 +36,7 @@ type WebsocketBody struct {
 type WebsocketBroadcast struct {
 	TargetUserIDs []uint
 	Body          *WebsocketBody
+	Event         string
 }
 
 type WebsocketGetStatus struct {
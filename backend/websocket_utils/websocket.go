@@ -43,6 +43,28 @@ func try_broadcast_offline_message(user_id uint) {
 	}
 }
 
+func try_record_notification(request *WebsocketBroadcast) {
+	target_user_ids, err := database.CreateNotifications(request.Event, request.TargetUserIDs, request.Body.Message)
+
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		target_user_ids = request.TargetUserIDs
+	} else {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+		if err := database.QueueNotificationEmails(request.Event, target_user_ids, request.Body.Message); err != nil {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		} else {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+		}
+	}
+
+	Hub.Broadcast <- &WebsocketBroadcast{
+		TargetUserIDs: target_user_ids,
+		Body:          request.Body,
+	}
+}
+
 func (hub *WebsocketHub) unregister(user_id uint) {
 	if conn, ok := hub.clients[user_id]; ok {
 		conn.Close()
@@ -78,7 +100,11 @@ func (hub *WebsocketHub) Run() {
 		case user_id := <-hub.Unregister:
 			hub.unregister(user_id)
 		case request := <-hub.Broadcast:
-			hub.broadcast(request)
+			if request.Event != "" {
+				go try_record_notification(request)
+			} else {
+				hub.broadcast(request)
+			}
 		case request := <-hub.GetStatus:
 			hub.getStatus(request)
 		}
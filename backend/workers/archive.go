@@ -0,0 +1,29 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+)
+
+const autoArchivePeriod = time.Hour
+
+func RunAutoArchiver() {
+	ticker := time.NewTicker(autoArchivePeriod)
+	defer ticker.Stop()
+
+	for {
+		autoArchiveTasks()
+		<-ticker.C
+	}
+}
+
+func autoArchiveTasks() {
+	_, err := database.AutoArchiveTasks()
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+}
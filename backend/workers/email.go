@@ -0,0 +1,76 @@
+package workers
+
+import (
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	email_utils "github.com/skye-tan/trello/backend/utils/email"
+)
+
+const (
+	emailCheckPeriod = 30 * time.Second
+	emailBatchSize   = 50
+	emailLease       = 10 * time.Minute
+	emailMaxAttempts = 5
+)
+
+func RunEmailDispatcher() {
+	if !email_utils.LoadSMTPConfig() {
+		return
+	}
+
+	ticker := time.NewTicker(emailCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		queueDigestEmails(time.Now())
+		dispatchEmails(time.Now())
+		<-ticker.C
+	}
+}
+
+func queueDigestEmails(now time.Time) {
+	recipients, err := database.GetDigestRecipients(now)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	for _, user_id := range recipients {
+		if err := database.QueueDigestEmail(user_id, now); err != nil {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		} else {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+		}
+	}
+}
+
+func emailRetryDelay(attempts int) time.Duration {
+	return time.Duration(1<<attempts) * time.Minute
+}
+
+func dispatchEmails(now time.Time) {
+	outbox_emails, err := database.ClaimPendingEmails(now, emailBatchSize, emailLease)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	for _, outbox_email := range outbox_emails {
+		err := email_utils.Send(outbox_email.Recipient, outbox_email.Subject,
+			outbox_email.TextBody, outbox_email.HTMLBody, outbox_email.UnsubscribeURL)
+		if err != nil {
+			log.Println("Error:", err)
+			give_up := outbox_email.Attempts >= emailMaxAttempts
+			database.MarkEmailFailed(outbox_email.ID, give_up,
+				time.Now().Add(emailRetryDelay(outbox_email.Attempts)), err.Error())
+			continue
+		}
+
+		database.MarkEmailSent(outbox_email.ID)
+	}
+}
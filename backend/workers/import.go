@@ -0,0 +1,47 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+)
+
+const (
+	importCheckPeriod = 5 * time.Second
+	importLease       = 10 * time.Minute
+)
+
+func RunImportProcessor() {
+	ticker := time.NewTicker(importCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		for processImportJob(time.Now()) {
+		}
+		<-ticker.C
+	}
+}
+
+// processImportJob runs the next queued import, if any, and reports whether
+// there was one.
+func processImportJob(now time.Time) bool {
+	import_job, ok, err := database.ClaimImportJob(now, importLease)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return false
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if !ok {
+		return false
+	}
+
+	if _, err := database.RunImportJob(import_job); err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+	} else {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+	}
+
+	return true
+}
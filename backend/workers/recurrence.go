@@ -0,0 +1,183 @@
+package workers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/utils/custom_errors"
+	recurrence_utils "github.com/skye-tan/trello/backend/utils/recurrence"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+const recurrenceCheckPeriod = time.Minute
+
+func RunRecurrenceScheduler() {
+	ticker := time.NewTicker(recurrenceCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		generateRecurringTasks(time.Now())
+		<-ticker.C
+	}
+}
+
+func generateRecurringTasks(now time.Time) {
+	recurring_tasks, err := database.GetDueRecurringTasks(now)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	for _, recurring_task := range recurring_tasks {
+		generateRecurringTask(recurring_task, now)
+	}
+}
+
+func generateRecurringTask(recurring_task database.RecurringTask, now time.Time) {
+	rule, err := recurrence_utils.ParseRule(recurring_task.Rule)
+	if err != nil {
+		log.Println("Error:", err)
+		database.PauseRecurringTask(recurring_task.ID)
+		return
+	}
+
+	occurrence := recurring_task.NextRunAt
+	after := occurrence
+	if now.After(after) {
+		after = now
+	}
+	next_run_at := rule.Next(recurring_task.StartAt, after)
+
+	is_member, err := database.IsRecurringTaskCreatorMember(recurring_task)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if !is_member {
+		database.PauseRecurringTask(recurring_task.ID)
+		return
+	}
+
+	claimed, err := database.ClaimRecurringTaskOccurrence(recurring_task.ID, occurrence)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	if !claimed {
+		database.AdvanceRecurringTask(recurring_task.ID, next_run_at)
+		return
+	}
+
+	task, err := instantiateRecurringTask(recurring_task, occurrence)
+	if err == custom_errors.ErrDuplicateTaskTitle {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		database.CompleteRecurringTaskOccurrence(recurring_task.ID, occurrence, 0, next_run_at)
+		return
+	} else if err == custom_errors.ErrAccessDenied || err == custom_errors.ErrInvalidArguments || err == custom_errors.ErrTwoFactorRequired {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		database.ReleaseRecurringTaskOccurrence(recurring_task.ID, occurrence)
+		database.PauseRecurringTask(recurring_task.ID)
+		return
+	} else if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		database.ReleaseRecurringTaskOccurrence(recurring_task.ID, occurrence)
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	database.CompleteRecurringTaskOccurrence(recurring_task.ID, occurrence, task.ID, next_run_at)
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: task.AssigneeIDs,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.WatchType,
+			Message: fmt.Sprintf("Task '%s' has been assigned to you.", task.Title),
+		},
+		Event: database.AssignmentEvent,
+	}
+
+	associated_users, err := database.GetAssociatedUsersWithTask(task.ID)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+		TargetUserIDs: associated_users,
+		Body: &websocket_utils.WebsocketBody{
+			Group:   websocket_utils.TaskGroup,
+			Type:    websocket_utils.UpdateType,
+			Message: fmt.Sprintf("Task '%s' has been created.", task.Title),
+		},
+	}
+}
+
+func recurringTaskTitle(title string, occurrence time.Time) string {
+	suffix := " " + occurrence.Format("2006-01-02")
+
+	runes := []rune(title)
+	if limit := 30 - len(suffix); len(runes) > limit {
+		runes = runes[:limit]
+	}
+
+	return string(runes) + suffix
+}
+
+func instantiateRecurringTask(recurring_task database.RecurringTask, occurrence time.Time) (database.Task, error) {
+	requester_user_id := recurring_task.CreatedBy
+	workspace_id := recurring_task.WorkspaceID
+
+	template, err := database.GetDetailsOfTask(requester_user_id, workspace_id, recurring_task.TemplateTaskID)
+	if err != nil {
+		return database.Task{}, err
+	}
+
+	due_offset := template.DueDate.Truncate(24 * time.Hour).Sub(template.CreatedAt.Truncate(24 * time.Hour))
+	if due_offset < 0 {
+		due_offset = 0
+	}
+
+	task, err := database.CreateTaskInWorkspace(requester_user_id, workspace_id,
+		recurringTaskTitle(template.Title, occurrence), template.Description,
+		template.EstimatedTime, 0, occurrence.Add(due_offset), template.Priority,
+		template.AssigneeIDs, template.ImageURL)
+	if err != nil {
+		return database.Task{}, err
+	}
+
+	subtasks, err := database.GetAllSubtasksInTask(requester_user_id, template.ID)
+	if err != nil {
+		return task, nil
+	}
+
+	for _, subtask := range subtasks {
+		_, err := database.CreateSubtaskInTask(requester_user_id, task.ID, subtask.Title, subtask.AssigneeIDs)
+		if err != nil {
+			log.Println("Error:", err)
+		}
+	}
+
+	labels, err := database.GetLabelsOfTask(requester_user_id, workspace_id, template.ID)
+	if err != nil {
+		return task, nil
+	}
+
+	for _, label := range labels {
+		err := database.AddLabelToTask(requester_user_id, workspace_id, task.ID, label.ID)
+		if err != nil {
+			log.Println("Error:", err)
+		}
+	}
+
+	return task, nil
+}
@@ -0,0 +1,117 @@
+package workers
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	"github.com/skye-tan/trello/backend/websocket_utils"
+)
+
+const reminderCheckPeriod = time.Minute
+
+type reminderWindow struct {
+	Kind     string
+	Duration time.Duration
+}
+
+func loadReminderWindows() []reminderWindow {
+	value, ok := os.LookupEnv("REMINDER_WINDOWS")
+	if !ok {
+		log.Println("Warn: Missing enviroment variable REMINDER_WINDOWS.",
+			"Using default reminder windows: [24h,1h]")
+		value = "24h,1h"
+	}
+
+	windows := []reminderWindow{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+
+		duration, err := time.ParseDuration(part)
+		if err != nil || duration <= 0 {
+			log.Println("Warn: Ignoring invalid reminder window:", part)
+			continue
+		}
+
+		windows = append(windows, reminderWindow{Kind: part, Duration: duration})
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Duration < windows[j].Duration
+	})
+
+	return windows
+}
+
+func RunReminderScheduler() {
+	windows := loadReminderWindows()
+
+	ticker := time.NewTicker(reminderCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		sendReminders(windows, time.Now())
+		<-ticker.C
+	}
+}
+
+func sendReminders(windows []reminderWindow, now time.Time) {
+	sendReminderBatch(database.OverdueReminder, time.Time{}, now, "Task '%s' is overdue.")
+
+	// Each window only covers the span beyond the next smaller one, so a task
+	// that enters the 1h window without a 24h reminder gets a single message.
+	from := now
+	for _, window := range windows {
+		to := now.Add(window.Duration)
+		sendReminderBatch(window.Kind, from, to, "Task '%s' is due within "+window.Kind+".")
+		from = to
+	}
+}
+
+func sendReminderBatch(kind string, from time.Time, to time.Time, format string) {
+	tasks, err := database.GetTasksForReminder(kind, from, to)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	for _, task := range tasks {
+		claimed, err := database.ClaimTaskReminder(task.ID, kind, task.DueDate)
+		if err != nil {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+			continue
+		}
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+		if !claimed {
+			continue
+		}
+
+		recipients, err := database.GetTaskReminderRecipients(task.ID)
+		if err != nil {
+			monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+			continue
+		}
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+		if len(recipients) == 0 {
+			continue
+		}
+
+		websocket_utils.Hub.Broadcast <- &websocket_utils.WebsocketBroadcast{
+			TargetUserIDs: recipients,
+			Body: &websocket_utils.WebsocketBody{
+				Group:   websocket_utils.TaskGroup,
+				Type:    websocket_utils.ReminderType,
+				Message: fmt.Sprintf(format, task.Title),
+			},
+			Event: database.ReminderEvent,
+		}
+	}
+}
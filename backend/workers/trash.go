@@ -0,0 +1,51 @@
+package workers
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+)
+
+const trashPurgePeriod = time.Hour
+
+func loadTrashRetention() time.Duration {
+	value, ok := os.LookupEnv("TRASH_RETENTION_DAYS")
+	if !ok {
+		log.Println("Warn: Missing enviroment variable TRASH_RETENTION_DAYS.",
+			"Using default trash retention: [30]")
+		value = "30"
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		log.Println("Warn: Invalid trash retention:", value, "Using default trash retention: [30]")
+		days = 30
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func RunTrashPurger() {
+	retention := loadTrashRetention()
+
+	ticker := time.NewTicker(trashPurgePeriod)
+	defer ticker.Stop()
+
+	for {
+		purgeTrash(time.Now().Add(-retention))
+		<-ticker.C
+	}
+}
+
+func purgeTrash(cutoff time.Time) {
+	_, err := database.PurgeTrash(cutoff)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+}
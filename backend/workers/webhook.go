@@ -0,0 +1,150 @@
+package workers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/skye-tan/trello/backend/database"
+	"github.com/skye-tan/trello/backend/middlewares/monitoring"
+	hashing_utils "github.com/skye-tan/trello/backend/utils/hashing"
+)
+
+const (
+	webhookCheckPeriod = 10 * time.Second
+	webhookBatchSize   = 50
+	webhookLease       = 5 * time.Minute
+	webhookMaxAttempts = 8
+	webhookTimeout     = 10 * time.Second
+)
+
+var errForbiddenWebhookAddress = errors.New("webhook address is not publicly routable")
+
+// webhookClient refuses to connect to internal addresses. The check runs on
+// the address actually dialed, so it also covers redirects and hostnames
+// that resolve differently from when the webhook was registered.
+var webhookClient = &http.Client{
+	Timeout: webhookTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: webhookTimeout,
+			Control: checkWebhookAddress,
+		}).DialContext,
+		TLSHandshakeTimeout: webhookTimeout,
+	},
+}
+
+func checkWebhookAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return errForbiddenWebhookAddress
+	}
+
+	return nil
+}
+
+type webhookEnvelope struct {
+	ID          uint            `json:"id"`
+	Event       string          `json:"event"`
+	WorkspaceID uint            `json:"workspace_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+func RunWebhookDispatcher() {
+	ticker := time.NewTicker(webhookCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		dispatchWebhooks(time.Now())
+		<-ticker.C
+	}
+}
+
+func webhookRetryDelay(attempts int) time.Duration {
+	return time.Duration(1<<attempts) * 30 * time.Second
+}
+
+func dispatchWebhooks(now time.Time) {
+	webhook_deliveries, err := database.ClaimPendingWebhookDeliveries(now, webhookBatchSize, webhookLease)
+	if err != nil {
+		monitoring.Statistics.Queries.WithLabelValues(monitoring.Unsuccessful).Inc()
+		return
+	}
+	monitoring.Statistics.Queries.WithLabelValues(monitoring.Successful).Inc()
+
+	for _, webhook_delivery := range webhook_deliveries {
+		response_status, err := deliverWebhook(webhook_delivery)
+		if err != nil {
+			log.Println("Error:", err)
+			give_up := webhook_delivery.Attempts >= webhookMaxAttempts
+			database.MarkWebhookDeliveryFailed(webhook_delivery.ID, give_up,
+				time.Now().Add(webhookRetryDelay(webhook_delivery.Attempts)), response_status, err.Error())
+			continue
+		}
+
+		database.MarkWebhookDelivered(webhook_delivery.ID, response_status)
+	}
+}
+
+func deliverWebhook(webhook_delivery database.WebhookDelivery) (int, error) {
+	webhook, err := database.GetWebhookForDelivery(webhook_delivery.WebhookID)
+	if err != nil {
+		return 0, err
+	}
+
+	body, err := json.Marshal(webhookEnvelope{
+		ID:          webhook_delivery.ID,
+		Event:       webhook_delivery.Event,
+		WorkspaceID: webhook.WorkspaceID,
+		CreatedAt:   webhook_delivery.CreatedAt,
+		Data:        json.RawMessage(webhook_delivery.Payload),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "trello-webhooks/1.0")
+	request.Header.Set("X-Webhook-Event", webhook_delivery.Event)
+	request.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(webhook_delivery.ID), 10))
+	request.Header.Set("X-Webhook-Signature", "sha256="+hashing_utils.SignUsingHmacSha256(webhook.Secret, body))
+
+	response, err := webhookClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("unexpected response status %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}